@@ -0,0 +1,61 @@
+// Command machine-client registers a client for a backend service to use with
+// the client credentials grant, and prints its id and secret once.
+//
+//	go run ./cmd/machine-client -name billing-service -scopes users:read
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/infrastructure/config"
+	"github/imfropz/go-ddd/internal/infrastructure/db/postgres"
+	"os"
+	"strings"
+)
+
+func main() {
+	name := flag.String("name", "", "name of the service the client is for")
+	scopes := flag.String("scopes", "", "comma separated scopes the client is allowed")
+	list := flag.Bool("list", false, "list the registered clients instead")
+	flag.Parse()
+
+	postgresConfig, err := config.LoadPostgres(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := postgres.NewConnection(*postgresConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable connect to database")
+		os.Exit(1)
+	}
+	db.AutoMigrate(&postgres.MachineClient{})
+
+	machineClientService := service.NewMachineClientService(postgres.NewGormMachineClientRepository(db))
+
+	if *list {
+		result, err := machineClientService.ListMachineClients()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to list machine clients: %v\n", err)
+			os.Exit(1)
+		}
+		for _, client := range result.Result {
+			fmt.Printf("%s\t%s\t%s\n", client.ClientId, client.Name, strings.Join(client.Scopes, " "))
+		}
+		return
+	}
+
+	result, err := machineClientService.RegisterMachineClient(&command.RegisterMachineClientCommand{
+		Name:   *name,
+		Scopes: strings.Split(*scopes, ","),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to register machine client: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("client_id:     %s\nclient_secret: %s\n", result.Result.ClientId, result.ClientSecret)
+}
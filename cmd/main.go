@@ -2,95 +2,245 @@ package main
 
 import (
 	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
 	"github/imfropz/go-ddd/internal/application/handler"
 	"github/imfropz/go-ddd/internal/application/service"
 	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/infrastructure/config"
 	"github/imfropz/go-ddd/internal/infrastructure/db/postgres"
 	"github/imfropz/go-ddd/internal/infrastructure/db/valkey"
 	"github/imfropz/go-ddd/internal/infrastructure/gmail"
 	"github/imfropz/go-ddd/internal/infrastructure/kafka"
+	"github/imfropz/go-ddd/internal/infrastructure/storage"
 	"github/imfropz/go-ddd/internal/interface/api"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
 )
 
 func main() {
-	db, err := postgres.NewConnection()
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		panic(fmt.Sprintf("invalid configuration:\n%v", err))
+	}
+
+	db, err := postgres.NewConnection(cfg.Postgres)
 	if err != nil {
 		panic("unable connect to database")
 	}
 
 	databaseMigration(db)
 
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-	smtpUsername := os.Getenv("SMTP_USERNAME")
-	smtpPassword := os.Getenv("SMTP_PASSWORD")
-	if smtpHost == "" || smtpPort == "" || smtpUsername == "" || smtpPassword == "" {
-		panic("missing any smtp configuration: SMTP_HOST, SMTP_PORT, SMTP_USERNAME, and SMTP_PASSWORD")
+	if err := loadKeyring(cfg.Jwt); err != nil {
+		panic(fmt.Sprintf("unable to load signing keys: %v", err))
 	}
+	go reloadKeyringOnHangup()
 
-	mail := gmail.NewGmailMail(gmail.SMTPConfig{
-		SMTPHost:     smtpHost,
-		SMTPPort:     smtpPort,
-		SMTPUsername: smtpUsername,
-		SMTPPassword: smtpPassword,
-	})
+	mail := gmail.NewGmailMail(cfg.SMTP)
 
 	userRepository := postgres.NewGormUserRepository(db)
-
-	consumer, err := kafka.NewSaramaConsumer(&kafka.SaramaConfig{
-		Brokers:  []string{"localhost:9092"},
-		Version:  "3.9.1",
-		ClientID: "user-service",
-	}, "notification-service-group")
+	oauthClientRepository := postgres.NewGormOAuthClientRepository(db)
+	oauthConsentRepository := postgres.NewGormOAuthConsentRepository(db)
+	machineClientRepository := postgres.NewGormMachineClientRepository(db)
+	apiKeyRepository := postgres.NewGormApiKeyRepository(db)
+	totpCredentialRepository := postgres.NewGormTotpCredentialRepository(db)
+	passkeyRepository := postgres.NewGormPasskeyRepository(db)
+	recoveryCodeRepository := postgres.NewGormRecoveryCodeRepository(db)
+	roleRepository := postgres.NewGormRoleRepository(db)
+	auditEventRepository := postgres.NewGormAuditEventRepository(db)
+
+	consumer, err := kafka.NewSaramaConsumer(&cfg.Kafka)
 	if err != nil {
 		slog.Error(fmt.Sprintf("Failed to create kafka consumer: %v", err))
 		return
 	}
 	defer consumer.Close()
 
-	valkeyRepository, err := valkey.NewValkeyRepository()
+	valkeyRepository, err := valkey.NewValkeyRepository(cfg.Valkey)
 	if err != nil {
 		slog.Error(fmt.Sprintf("Failed to create valkey repository: %v", err))
 		return
 	}
 	defer valkeyRepository.Close()
 
+	exportStorage, err := storage.NewLocalFileStorage(cfg.Export.Directory)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to create export storage: %v", err))
+		return
+	}
+
+	userProducer, err := kafka.NewSaramaProducer(&cfg.Kafka)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to create kafka producer: %v", err))
+		return
+	}
+
+	lockoutPolicy := entity.LoginLockoutPolicy{
+		EmailThreshold: cfg.Auth.Lockout.EmailThreshold,
+		IpThreshold:    cfg.Auth.Lockout.IpThreshold,
+		Window:         cfg.Auth.Lockout.Window,
+		BaseDuration:   cfg.Auth.Lockout.BaseDuration,
+		MaxDuration:    cfg.Auth.Lockout.MaxDuration,
+	}
+	authenticateService := service.NewAuthenticateService(userProducer, valkeyRepository, userRepository, auditEventRepository, cfg.Auth.EmailVerificationPolicy, lockoutPolicy, cfg.Auth.Deletion.GracePeriod)
+	userService := service.NewUserService(userProducer, valkeyRepository, userRepository, cfg.Auth.Deletion.GracePeriod)
+	sessionService := service.NewSessionService(valkeyRepository)
+	auditService := service.NewAuditService(auditEventRepository)
+	apiKeyService := service.NewApiKeyService(apiKeyRepository)
+	roleService := service.NewRoleService(userRepository, roleRepository, totpCredentialRepository)
+	if err := roleService.SeedRoles(&command.SeedRolesCommand{AdminEmails: cfg.Auth.AdminEmails}); err != nil {
+		slog.Error(fmt.Sprintf("Failed to seed roles: %v", err))
+		return
+	}
+	tokenService := service.NewTokenService(userProducer, valkeyRepository, userRepository, apiKeyRepository, auditEventRepository, sessionService, roleService)
+	mfaService := service.NewMfaService(userProducer, valkeyRepository, userRepository, totpCredentialRepository, recoveryCodeRepository, cfg.Mfa.TotpIssuer, lockoutPolicy)
+	passkeyService := service.NewPasskeyService(valkeyRepository, userRepository, passkeyRepository, auditEventRepository, util.WebauthnRelyingParty{
+		Id:      cfg.Webauthn.RpId,
+		Name:    cfg.Webauthn.RpName,
+		Origins: cfg.Webauthn.Origins,
+	}, cfg.Auth.EmailVerificationPolicy)
+	oauthService := service.NewOAuthService(valkeyRepository, userRepository, oauthClientRepository, oauthConsentRepository, machineClientRepository, sessionService, tokenService, cfg.Oidc.Issuer)
+	dataExportService := service.NewDataExportService(userProducer, valkeyRepository, userRepository, apiKeyRepository, oauthClientRepository, passkeyRepository, totpCredentialRepository, recoveryCodeRepository, roleRepository, auditEventRepository, sessionService, exportStorage, cfg.Export.LinkDuration)
+
 	notificationService := service.NewNotificationService(mail)
 
-	notificationHandler := handler.NewNotificationEventHandler(notificationService)
+	notificationHandler := handler.NewNotificationEventHandler(notificationService, cfg.Mail.FromEmail, handler.EmailLinks{
+		MagicLink:          cfg.Mail.MagicLinkUrl,
+		VerifyEmail:        cfg.Mail.VerifyEmailUrl,
+		ConfirmEmailChange: cfg.Mail.ConfirmEmailChangeUrl,
+		RevertEmailChange:  cfg.Mail.RevertEmailChangeUrl,
+		RestoreAccount:     cfg.Mail.RestoreAccountUrl,
+		DataExport:         cfg.Mail.DataExportUrl,
+	})
+	dataExportHandler := handler.NewDataExportEventHandler(dataExportService)
 
-	topics := []string{entity.RESET_PASSWORD}
-	if err := consumer.Consume(topics, notificationHandler); err != nil {
+	router := handler.EventRouter{entity.DATA_EXPORT_REQUESTED: dataExportHandler}
+	for _, topic := range []string{entity.RESET_PASSWORD, entity.RECOVERY_CODES_REGENERATED, entity.MAGIC_LINK, entity.VERIFY_EMAIL, entity.EMAIL_CHANGE, entity.LOGIN_LOCKED, entity.USER_DELETION_SCHEDULED, entity.DATA_EXPORT_READY} {
+		router[topic] = notificationHandler
+	}
+	if err := consumer.Consume(router.Topics(), router); err != nil {
 		slog.Error(fmt.Sprintf("Failed to start consumer: %v", err))
 	}
 
-	userProducer, err := kafka.NewSaramaProducer(&kafka.SaramaConfig{
-		Brokers:  []string{"localhost:9092"},
-		Version:  "3.9.1",
-		ClientID: "user-service",
-	})
+	trustedProxies, err := request.ParseTrustedProxies(cfg.Server.TrustedProxies)
 	if err != nil {
-		slog.Error(fmt.Sprintf("Failed to create kafka producer: %v", err))
-		return
+		panic(fmt.Sprintf("invalid trusted proxies: %v", err))
 	}
 
-	authenticateService := service.NewAuthenticateService(userProducer, valkeyRepository, userRepository)
+	go purgeDeletedUsers(userService, cfg.Auth.Deletion.PurgeInterval)
+	go purgeExpiredDataExports(dataExportService, cfg.Export.PurgeInterval)
 
-	r := mux.NewRouter()
-	api.NewAuthenticateController(r, authenticateService, userRepository)
+	authenticator := middleware.NewAuthenticator(userRepository, tokenService, cfg.Auth.EmailVerificationPolicy)
+	var rateLimiter middleware.RateLimiter
+	if cfg.RateLimit.Enabled {
+		rateLimiter = middleware.NewValkeyRateLimiter(valkeyRepository)
+	}
+	rateLimit := middleware.NewRateLimit(rateLimiter, trustedProxies)
 
-	slog.Info("Starting server on :8080")
-	if err := http.ListenAndServe(":8080", r); err != nil {
+	r := mux.NewRouter()
+	api.NewAuthenticateController(r, authenticateService, tokenService, mfaService, authenticator, rateLimit, trustedProxies)
+	api.NewMfaController(r, mfaService, authenticator, rateLimit, trustedProxies)
+	api.NewPasskeyController(r, passkeyService, tokenService, authenticator, rateLimit, trustedProxies)
+	api.NewUserController(r, userService, tokenService, authenticator)
+	api.NewSessionController(r, sessionService, authenticator)
+	api.NewRoleController(r, roleService, authenticator)
+	api.NewApiKeyController(r, apiKeyService, authenticator)
+	api.NewJwksController(r)
+	api.NewOAuthController(r, oauthService, authenticateService, tokenService, mfaService, userRepository, authenticator, rateLimit, trustedProxies)
+	api.NewOidcController(r, oauthService, tokenService, cfg.Oidc.Issuer)
+	api.NewDataExportController(r, dataExportService, authenticator, rateLimit)
+	api.NewAuditController(r, auditService, authenticator)
+
+	slog.Info(fmt.Sprintf("Starting server on %s", cfg.Server.Address))
+	if err := http.ListenAndServe(cfg.Server.Address, r); err != nil {
 		slog.Error(fmt.Sprintf("Failed to start server: %s", err))
 	}
 }
 
+// purgeDeletedUsers removes the accounts whose deletion grace period is over,
+// once per interval for as long as the server runs.
+func purgeDeletedUsers(userService *service.UserService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := userService.PurgeDeletedUsers()
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to purge deleted users: %v", err))
+		}
+		if purged > 0 {
+			slog.Info(fmt.Sprintf("Purged %d deleted users", purged))
+		}
+	}
+}
+
+// purgeExpiredDataExports removes the export archives that can no longer be
+// downloaded.
+func purgeExpiredDataExports(dataExportService *service.DataExportService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := dataExportService.PurgeExpiredDataExports()
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to purge expired data exports: %v", err))
+		}
+		if purged > 0 {
+			slog.Info(fmt.Sprintf("Purged %d expired data exports", purged))
+		}
+	}
+}
+
 func databaseMigration(db *gorm.DB) {
-	db.AutoMigrate(&postgres.User{})
+	db.AutoMigrate(&postgres.User{}, &postgres.OAuthClient{}, &postgres.OAuthConsent{}, &postgres.MachineClient{}, &postgres.ApiKey{}, &postgres.TotpCredential{}, &postgres.Passkey{}, &postgres.RecoveryCode{}, &postgres.Role{}, &postgres.UserRole{}, &postgres.AuditEvent{})
+}
+
+func loadKeyring(jwtConfig config.JwtConfig) error {
+	if jwtConfig.Ephemeral {
+		slog.Warn("jwt.ephemeral is set, signing tokens with a key that does not survive a restart")
+		keyring, err := util.NewEphemeralKeyring()
+		if err != nil {
+			return err
+		}
+		util.SetKeyring(keyring)
+		return nil
+	}
+
+	keyring, err := util.LoadKeyring(jwtConfig.SigningKeyFile, jwtConfig.VerificationKeyFiles)
+	if err != nil {
+		return err
+	}
+	util.SetKeyring(keyring)
+
+	return nil
+}
+
+// reloadKeyringOnHangup re-reads the configuration on SIGHUP so signing keys
+// can be rotated without restarting the server.
+func reloadKeyringOnHangup() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	for range hangup {
+		cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to reload configuration: %v", err))
+			continue
+		}
+
+		if err := loadKeyring(cfg.Jwt); err != nil {
+			slog.Error(fmt.Sprintf("Failed to reload signing keys: %v", err))
+			continue
+		}
+		slog.Info("Reloaded signing keys")
+	}
 }
@@ -0,0 +1,267 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync/atomic"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type SigningKey struct {
+	Id         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+type JSONWebKey struct {
+	Kty string
+	Kid string
+	Use string
+	Alg string
+	Crv string
+	X   string
+	N   string
+	E   string
+}
+
+// Keyring signs with a single active key and verifies with the active key plus
+// any previous keys, so tokens issued before a rotation stay valid until they
+// expire.
+type Keyring struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+var keyring atomic.Pointer[Keyring]
+
+func NewKeyring(active *SigningKey, previous ...*SigningKey) (*Keyring, error) {
+	if active == nil || active.PrivateKey == nil {
+		return nil, errors.New("active key must have a private key")
+	}
+
+	k := &Keyring{
+		active: active,
+		keys:   map[string]*SigningKey{active.Id: active},
+	}
+	for _, key := range previous {
+		k.keys[key.Id] = key
+	}
+
+	return k, nil
+}
+
+// LoadKeyring reads the active private key and the previous verification keys
+// from PEM files.
+func LoadKeyring(activeKeyPath string, previousKeyPaths []string) (*Keyring, error) {
+	active, err := LoadSigningKey(activeKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make([]*SigningKey, 0, len(previousKeyPaths))
+	for _, path := range previousKeyPaths {
+		key, err := LoadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, key)
+	}
+
+	return NewKeyring(active, previous...)
+}
+
+// NewEphemeralKeyring generates an in-memory Ed25519 key. Tokens signed with it
+// do not survive a restart, so it is only meant for development and tests.
+func NewEphemeralKeyring() (*Keyring, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newSigningKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyring(key)
+}
+
+// LoadSigningKey reads a PKCS#8, PKCS#1 or PKIX encoded RSA or Ed25519 key. A
+// public key can only be used to verify tokens.
+func LoadSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(key)
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(key)
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newVerificationKey(key)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q in %s", block.Type, path)
+	}
+}
+
+func SetKeyring(k *Keyring) {
+	keyring.Store(k)
+}
+
+// CurrentKeyring returns the keyring installed with SetKeyring. Signing or
+// validating a token before one is installed is a programming error.
+func CurrentKeyring() *Keyring {
+	k := keyring.Load()
+	if k == nil {
+		panic("no signing keyring installed, call SetKeyring at startup")
+	}
+
+	return k
+}
+
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	token.Header["kid"] = k.active.Id
+
+	return token.SignedString(k.active.PrivateKey)
+}
+
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("missing kid header")
+	}
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for kid %q", token.Method.Alg(), kid)
+	}
+
+	return key.PublicKey, nil
+}
+
+func (k *Keyring) ValidMethods() []string {
+	methods := make([]string, 0, len(k.keys))
+	seen := map[string]bool{}
+	for _, key := range k.keys {
+		if !seen[key.Method.Alg()] {
+			seen[key.Method.Alg()] = true
+			methods = append(methods, key.Method.Alg())
+		}
+	}
+	return methods
+}
+
+func (k *Keyring) PublicKeys() []JSONWebKey {
+	keys := make([]JSONWebKey, 0, len(k.keys))
+	keys = append(keys, k.active.JWK())
+	for id, key := range k.keys {
+		if id != k.active.Id {
+			keys = append(keys, key.JWK())
+		}
+	}
+	return keys
+}
+
+func (key *SigningKey) JWK() JSONWebKey {
+	jwk := JSONWebKey{
+		Kid: key.Id,
+		Use: "sig",
+		Alg: key.Method.Alg(),
+	}
+
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+
+	return jwk
+}
+
+func newSigningKey(privateKey any) (*SigningKey, error) {
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign")
+	}
+
+	key, err := newVerificationKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.PrivateKey = signer
+
+	return key, nil
+}
+
+func newVerificationKey(publicKey any) (*SigningKey, error) {
+	key := &SigningKey{PublicKey: publicKey}
+
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, expected RSA or Ed25519", publicKey)
+	}
+
+	key.Id = thumbprint(key.JWK())
+
+	return key, nil
+}
+
+// thumbprint derives the kid from the RFC 7638 JWK thumbprint so it stays the
+// same for a given key no matter which file it is loaded from.
+func thumbprint(jwk JSONWebKey) string {
+	var canonical string
+	switch jwk.Kty {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case "OKP":
+		canonical = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Crv, jwk.X)
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,102 @@
+package util_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github/imfropz/go-ddd/common/util"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePem(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), uuid.NewString()+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaDer, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	rsaPath := writePem(t, "PRIVATE KEY", rsaDer)
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edDer, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	edPath := writePem(t, "PRIVATE KEY", edDer)
+
+	claims := util.AccessTokenClaims{
+		Id:        uuid.New(),
+		Name:      "John Doe",
+		Email:     "test@example.com",
+		SessionId: uuid.New(),
+		TokenId:   "token-id",
+	}
+
+	t.Run("success: previous key still verifies", func(t *testing.T) {
+		oldKeyring, err := util.LoadKeyring(rsaPath, nil)
+		assert.NoError(t, err)
+		util.SetKeyring(oldKeyring)
+
+		token, err := util.GenerateAccessToken(claims)
+		assert.NoError(t, err)
+
+		newKeyring, err := util.LoadKeyring(edPath, []string{rsaPath})
+		assert.NoError(t, err)
+		util.SetKeyring(newKeyring)
+
+		validated, err := util.ValidateAccessToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, claims.Id, validated.Id)
+
+		keys := newKeyring.PublicKeys()
+		assert.Len(t, keys, 2)
+		assert.Equal(t, "EdDSA", keys[0].Alg)
+		assert.Equal(t, "OKP", keys[0].Kty)
+		assert.Equal(t, "RS256", keys[1].Alg)
+		assert.Equal(t, "RSA", keys[1].Kty)
+	})
+
+	t.Run("failure: retired key no longer verifies", func(t *testing.T) {
+		oldKeyring, _ := util.LoadKeyring(rsaPath, nil)
+		util.SetKeyring(oldKeyring)
+
+		token, _ := util.GenerateAccessToken(claims)
+
+		newKeyring, _ := util.LoadKeyring(edPath, nil)
+		util.SetKeyring(newKeyring)
+
+		_, err := util.ValidateAccessToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: public key cannot sign", func(t *testing.T) {
+		publicDer, _ := x509.MarshalPKIXPublicKey(edKey.Public())
+		publicPath := writePem(t, "PUBLIC KEY", publicDer)
+
+		_, err := util.LoadKeyring(publicPath, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: refresh token is not an access token", func(t *testing.T) {
+		keyring, _ := util.LoadKeyring(edPath, nil)
+		util.SetKeyring(keyring)
+
+		token, _ := util.GenerateRefreshToken(util.RefreshTokenClaims{
+			Id:        claims.Id,
+			TokenId:   "token-id",
+			FamilyId:  "family-id",
+			SessionId: claims.SessionId,
+		})
+
+		_, err := util.ValidateAccessToken(token)
+		assert.Error(t, err)
+	})
+}
@@ -1,7 +1,10 @@
 package util
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -9,22 +12,89 @@ import (
 	"github.com/google/uuid"
 )
 
-// TODO: convert into env
+// Every token is signed by the same keyring, so the typ claim keeps one kind of
+// token from being accepted in place of another.
 const (
-	TOKEN_SIGNATURE                = "signature-token"
-	REFRESH_TOKEN_SIGNATURE        = "signature-refresh-token"
-	RESET_PASSWORD_TOKEN_SIGNATURE = "signature-reset-password-token"
+	ACCESS_TOKEN_TYPE         = "access"
+	REFRESH_TOKEN_TYPE        = "refresh"
+	RESET_PASSWORD_TOKEN_TYPE = "reset-password"
+	ID_TOKEN_TYPE             = "id"
+	MFA_CHALLENGE_TOKEN_TYPE  = "mfa-challenge"
+	MAGIC_LINK_TOKEN_TYPE     = "magic-link"
+	VERIFY_EMAIL_TOKEN_TYPE   = "verify-email"
+
+	CONFIRM_EMAIL_CHANGE_TOKEN_TYPE = "confirm-email-change"
+	REVERT_EMAIL_CHANGE_TOKEN_TYPE  = "revert-email-change"
+	RESTORE_ACCOUNT_TOKEN_TYPE      = "restore-account"
+	DATA_EXPORT_TOKEN_TYPE          = "data-export"
+)
+
+// Access tokens are issued either to a user or, through the client credentials
+// grant, to a machine client acting on its own behalf.
+const (
+	SUBJECT_TYPE_USER   = "user"
+	SUBJECT_TYPE_CLIENT = "client"
+)
+
+const (
+	ACCESS_TOKEN_DURATION  = time.Second * time.Duration(200)
+	REFRESH_TOKEN_DURATION = time.Hour * time.Duration(2)
+	ID_TOKEN_DURATION      = time.Minute * time.Duration(10)
+
+	MFA_CHALLENGE_TOKEN_DURATION = time.Minute * time.Duration(5)
+	MAGIC_LINK_TOKEN_DURATION    = time.Minute * time.Duration(15)
+	VERIFY_EMAIL_TOKEN_DURATION  = time.Hour * time.Duration(24)
+
+	CONFIRM_EMAIL_CHANGE_TOKEN_DURATION = time.Hour * time.Duration(24)
+	REVERT_EMAIL_CHANGE_TOKEN_DURATION  = time.Hour * time.Duration(24*7)
 )
 
 type AccessTokenClaims struct {
-	Id    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Email string    `json:"email"`
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	SessionId   uuid.UUID `json:"sid"`
+	TokenId     string    `json:"jti"`
+	ExpiresAt   time.Time `json:"exp"`
+	ClientId    string    `json:"client_id"`
+	Scope       string    `json:"scope"`
+	SubjectType string    `json:"sub_type"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+	ApiKeyId    uuid.UUID `json:"-"`
 	jwt.Claims
 }
 
 type RefreshTokenClaims struct {
-	Id uuid.UUID `json:"id"`
+	Id        uuid.UUID `json:"id"`
+	TokenId   string    `json:"jti"`
+	FamilyId  string    `json:"family"`
+	SessionId uuid.UUID `json:"sid"`
+	ExpiresAt time.Time `json:"exp"`
+	ClientId  string    `json:"client_id"`
+	Scope     string    `json:"scope"`
+	jwt.Claims
+}
+
+// IdTokenClaims are the OpenID Connect claims about the authenticated user.
+// Name and email are left out of the token when empty.
+type IdTokenClaims struct {
+	Issuer        string
+	Subject       uuid.UUID
+	Audience      string
+	Nonce         string
+	AuthTime      time.Time
+	Name          string
+	Email         string
+	EmailVerified bool
+}
+
+// MfaChallengeTokenClaims are handed out after the password was checked, to
+// be exchanged together with a second factor for the token pair.
+type MfaChallengeTokenClaims struct {
+	Id          uuid.UUID `json:"id"`
+	ChallengeId string    `json:"jti"`
+	Method      string    `json:"method"`
 	jwt.Claims
 }
 
@@ -33,21 +103,186 @@ type ResetPasswordTokenClaims struct {
 	jwt.Claims
 }
 
+// VerifyEmailTokenClaims name the address being verified, so that a link sent
+// before the email was changed cannot verify the new one.
+type VerifyEmailTokenClaims struct {
+	Id    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+	jwt.Claims
+}
+
+// EmailChangeTokenClaims are carried both by the confirmation sent to the new
+// address and by the revert link sent to the old one, which stays valid longer.
+type EmailChangeTokenClaims struct {
+	Id       uuid.UUID `json:"id"`
+	OldEmail string    `json:"old_email"`
+	NewEmail string    `json:"new_email"`
+	jwt.Claims
+}
+
+// RestoreAccountTokenClaims are emailed when an account is deleted and stay
+// valid until it is purged. DeletedAt ties the link to that one deletion.
+type RestoreAccountTokenClaims struct {
+	Id        uuid.UUID `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	ExpiresAt time.Time `json:"exp"`
+	jwt.Claims
+}
+
+// DataExportTokenClaims are emailed once a data export is ready. Id is the
+// export's id, so the link only ever downloads that one archive.
+type DataExportTokenClaims struct {
+	Id        uuid.UUID `json:"id"`
+	UserId    uuid.UUID `json:"sub"`
+	ExpiresAt time.Time `json:"exp"`
+	jwt.Claims
+}
+
+// MagicLinkTokenClaims are emailed to sign in without a password. The token id
+// is what makes a link single use.
+type MagicLinkTokenClaims struct {
+	Email   string `json:"email"`
+	TokenId string `json:"jti"`
+	jwt.Claims
+}
+
+// IsMachine reports whether the token was issued to a machine client, in which
+// case Id is the client's id and there is no email or session.
+func (c AccessTokenClaims) IsMachine() bool {
+	return c.SubjectType == SUBJECT_TYPE_CLIENT
+}
+
+// IsApiKey reports whether the claims were built from an API key rather than
+// a token, in which case there is no session.
+func (c AccessTokenClaims) IsApiKey() bool {
+	return c.ApiKeyId != uuid.Nil
+}
+
+func (c AccessTokenClaims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+type claimsKey struct{}
+
+// ContextWithAccessTokenClaims stores the claims of the authenticated request.
+func ContextWithAccessTokenClaims(ctx context.Context, claims AccessTokenClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+func AccessTokenClaimsFromContext(ctx context.Context) (AccessTokenClaims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(AccessTokenClaims)
+	return claims, ok
+}
+
+// MustAccessTokenClaims is AccessTokenClaimsFromContext for handlers that are
+// always wrapped in an authentication handler. It panics otherwise.
+func MustAccessTokenClaims(ctx context.Context) AccessTokenClaims {
+	claims, ok := AccessTokenClaimsFromContext(ctx)
+	if !ok {
+		panic("access token claims missing from the request context")
+	}
+	return claims
+}
+
 func RemoveBearer(token string) (string, bool) {
 	return strings.CutPrefix(token, "Bearer ")
 }
 
 func GenerateAccessToken(c AccessTokenClaims) (string, error) {
+	claims := jwt.MapClaims{
+		"id":   c.Id.String(),
+		"name": c.Name,
+		"jti":  c.TokenId,
+		"exp":  time.Now().Add(ACCESS_TOKEN_DURATION).Unix(),
+	}
+	if c.IsMachine() {
+		claims["sub_type"] = SUBJECT_TYPE_CLIENT
+	} else {
+		claims["sub_type"] = SUBJECT_TYPE_USER
+		claims["email"] = c.Email
+		claims["sid"] = c.SessionId.String()
+		// Sent space delimited, like the scope.
+		if len(c.Roles) > 0 {
+			claims["roles"] = strings.Join(c.Roles, " ")
+		}
+		if len(c.Permissions) > 0 {
+			claims["permissions"] = strings.Join(c.Permissions, " ")
+		}
+	}
+	setOptionalClaims(claims, c.ClientId, c.Scope)
+
+	tokenString, err := signToken(ACCESS_TOKEN_TYPE, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func GenerateRefreshToken(c RefreshTokenClaims) (string, error) {
+	claims := jwt.MapClaims{
+		"id":     c.Id.String(),
+		"jti":    c.TokenId,
+		"family": c.FamilyId,
+		"sid":    c.SessionId.String(),
+		"exp":    time.Now().Add(REFRESH_TOKEN_DURATION).Unix(),
+	}
+	setOptionalClaims(claims, c.ClientId, c.Scope)
+
+	tokenString, err := signToken(REFRESH_TOKEN_TYPE, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func GenerateResetPasswordToken(c ResetPasswordTokenClaims) (string, error) {
+	claims := jwt.MapClaims{
+		"email": c.Email,
+		"exp":   time.Now().Add(time.Hour * time.Duration(1)).Unix(),
+	}
+
+	tokenString, err := signToken(RESET_PASSWORD_TOKEN_TYPE, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func GenerateVerifyEmailToken(c VerifyEmailTokenClaims) (string, error) {
 	claims := jwt.MapClaims{
 		"id":    c.Id.String(),
-		"name":  c.Name,
 		"email": c.Email,
-		"exp":   time.Now().Add(time.Second * time.Duration(200)).Unix(),
+		"exp":   time.Now().Add(VERIFY_EMAIL_TOKEN_DURATION).Unix(),
+	}
+
+	tokenString, err := signToken(VERIFY_EMAIL_TOKEN_TYPE, claims)
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return tokenString, nil
+}
+
+func GenerateConfirmEmailChangeToken(c EmailChangeTokenClaims) (string, error) {
+	return generateEmailChangeToken(CONFIRM_EMAIL_CHANGE_TOKEN_TYPE, CONFIRM_EMAIL_CHANGE_TOKEN_DURATION, c)
+}
+
+func GenerateRevertEmailChangeToken(c EmailChangeTokenClaims) (string, error) {
+	return generateEmailChangeToken(REVERT_EMAIL_CHANGE_TOKEN_TYPE, REVERT_EMAIL_CHANGE_TOKEN_DURATION, c)
+}
+
+func generateEmailChangeToken(tokenType string, duration time.Duration, c EmailChangeTokenClaims) (string, error) {
+	claims := jwt.MapClaims{
+		"id":        c.Id.String(),
+		"old_email": c.OldEmail,
+		"new_email": c.NewEmail,
+		"exp":       time.Now().Add(duration).Unix(),
+	}
 
-	tokenString, err := token.SignedString([]byte(TOKEN_SIGNATURE))
+	tokenString, err := signToken(tokenType, claims)
 	if err != nil {
 		return "", err
 	}
@@ -55,15 +290,29 @@ func GenerateAccessToken(c AccessTokenClaims) (string, error) {
 	return tokenString, nil
 }
 
-func GenerateRefreshToken(c RefreshTokenClaims) (string, error) {
+func GenerateRestoreAccountToken(c RestoreAccountTokenClaims) (string, error) {
 	claims := jwt.MapClaims{
-		"id":  c.Id.String(),
-		"exp": time.Now().Add(time.Hour * time.Duration(2)).Unix(),
+		"id":         c.Id.String(),
+		"deleted_at": c.DeletedAt.Unix(),
+		"exp":        c.ExpiresAt.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := signToken(RESTORE_ACCOUNT_TOKEN_TYPE, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func GenerateDataExportToken(c DataExportTokenClaims) (string, error) {
+	claims := jwt.MapClaims{
+		"id":  c.Id.String(),
+		"sub": c.UserId.String(),
+		"exp": c.ExpiresAt.Unix(),
+	}
 
-	tokenString, err := token.SignedString([]byte(REFRESH_TOKEN_SIGNATURE))
+	tokenString, err := signToken(DATA_EXPORT_TOKEN_TYPE, claims)
 	if err != nil {
 		return "", err
 	}
@@ -71,15 +320,60 @@ func GenerateRefreshToken(c RefreshTokenClaims) (string, error) {
 	return tokenString, nil
 }
 
-func GenerateResetPasswordToken(c ResetPasswordTokenClaims) (string, error) {
+func GenerateMagicLinkToken(c MagicLinkTokenClaims) (string, error) {
 	claims := jwt.MapClaims{
 		"email": c.Email,
-		"exp":   time.Now().Add(time.Hour * time.Duration(1)).Unix(),
+		"jti":   c.TokenId,
+		"exp":   time.Now().Add(MAGIC_LINK_TOKEN_DURATION).Unix(),
+	}
+
+	tokenString, err := signToken(MAGIC_LINK_TOKEN_TYPE, claims)
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return tokenString, nil
+}
+
+func GenerateMfaChallengeToken(c MfaChallengeTokenClaims) (string, error) {
+	claims := jwt.MapClaims{
+		"id":     c.Id.String(),
+		"jti":    c.ChallengeId,
+		"method": c.Method,
+		"exp":    time.Now().Add(MFA_CHALLENGE_TOKEN_DURATION).Unix(),
+	}
+
+	tokenString, err := signToken(MFA_CHALLENGE_TOKEN_TYPE, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func GenerateIdToken(c IdTokenClaims) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss":       c.Issuer,
+		"sub":       c.Subject.String(),
+		"aud":       c.Audience,
+		"iat":       now.Unix(),
+		"exp":       now.Add(ID_TOKEN_DURATION).Unix(),
+		"auth_time": c.AuthTime.Unix(),
+	}
+	if c.Nonce != "" {
+		claims["nonce"] = c.Nonce
+	}
+	if c.Name != "" {
+		claims["name"] = c.Name
+	}
+	if c.Email != "" {
+		claims["email"] = c.Email
+		claims["email_verified"] = c.EmailVerified
+	}
 
-	tokenString, err := token.SignedString([]byte(RESET_PASSWORD_TOKEN_SIGNATURE))
+	tokenString, err := signToken(ID_TOKEN_TYPE, claims)
 	if err != nil {
 		return "", err
 	}
@@ -88,9 +382,7 @@ func GenerateResetPasswordToken(c ResetPasswordTokenClaims) (string, error) {
 }
 
 func ValidateAccessToken(tokenString string) (AccessTokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(TOKEN_SIGNATURE), nil
-	})
+	token, err := parseToken(ACCESS_TOKEN_TYPE, tokenString)
 	if err != nil {
 		return AccessTokenClaims{}, err
 	}
@@ -114,12 +406,52 @@ func ValidateAccessToken(tokenString string) (AccessTokenClaims, error) {
 			return AccessTokenClaims{}, errors.New("missing name claims")
 		}
 
-		if email, ok := claims["email"].(string); ok {
-			new_claims.Email = email
+		new_claims.SubjectType = SUBJECT_TYPE_USER
+		if subjectType, ok := claims["sub_type"].(string); ok {
+			new_claims.SubjectType = subjectType
+		}
+
+		if new_claims.IsMachine() {
+			if clientId, ok := claims["client_id"].(string); !ok || clientId == "" {
+				return AccessTokenClaims{}, errors.New("missing client_id claims")
+			}
+		} else {
+			if email, ok := claims["email"].(string); ok {
+				new_claims.Email = email
+			} else {
+				return AccessTokenClaims{}, errors.New("missing email claims")
+			}
+
+			if sessionId, ok := claims["sid"].(string); ok {
+				if sessionId, err := uuid.Parse(sessionId); err == nil {
+					new_claims.SessionId = sessionId
+				} else {
+					return AccessTokenClaims{}, errors.New("invalid uuid format in sid claims")
+				}
+			} else {
+				return AccessTokenClaims{}, errors.New("missing sid claims")
+			}
+		}
+
+		if tokenId, ok := claims["jti"].(string); ok && tokenId != "" {
+			new_claims.TokenId = tokenId
+		} else {
+			return AccessTokenClaims{}, errors.New("missing jti claims")
+		}
+
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			new_claims.ExpiresAt = exp.Time
 		} else {
-			return AccessTokenClaims{}, errors.New("missing email claims")
+			return AccessTokenClaims{}, errors.New("missing exp claims")
 		}
 
+		new_claims.ClientId, _ = claims["client_id"].(string)
+		new_claims.Scope, _ = claims["scope"].(string)
+		roles, _ := claims["roles"].(string)
+		new_claims.Roles = strings.Fields(roles)
+		permissions, _ := claims["permissions"].(string)
+		new_claims.Permissions = strings.Fields(permissions)
+
 		return new_claims, nil
 	}
 
@@ -127,9 +459,7 @@ func ValidateAccessToken(tokenString string) (AccessTokenClaims, error) {
 }
 
 func ValidateRefreshToken(tokenString string) (RefreshTokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(REFRESH_TOKEN_SIGNATURE), nil
-	})
+	token, err := parseToken(REFRESH_TOKEN_TYPE, tokenString)
 	if err != nil {
 		return RefreshTokenClaims{}, err
 	}
@@ -147,6 +477,37 @@ func ValidateRefreshToken(tokenString string) (RefreshTokenClaims, error) {
 			return RefreshTokenClaims{}, errors.New("missing id claims")
 		}
 
+		if tokenId, ok := claims["jti"].(string); ok && tokenId != "" {
+			new_claims.TokenId = tokenId
+		} else {
+			return RefreshTokenClaims{}, errors.New("missing jti claims")
+		}
+
+		if familyId, ok := claims["family"].(string); ok && familyId != "" {
+			new_claims.FamilyId = familyId
+		} else {
+			return RefreshTokenClaims{}, errors.New("missing family claims")
+		}
+
+		if sessionId, ok := claims["sid"].(string); ok {
+			if sessionId, err := uuid.Parse(sessionId); err == nil {
+				new_claims.SessionId = sessionId
+			} else {
+				return RefreshTokenClaims{}, errors.New("invalid uuid format in sid claims")
+			}
+		} else {
+			return RefreshTokenClaims{}, errors.New("missing sid claims")
+		}
+
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			new_claims.ExpiresAt = exp.Time
+		} else {
+			return RefreshTokenClaims{}, errors.New("missing exp claims")
+		}
+
+		new_claims.ClientId, _ = claims["client_id"].(string)
+		new_claims.Scope, _ = claims["scope"].(string)
+
 		return new_claims, nil
 	}
 
@@ -154,9 +515,7 @@ func ValidateRefreshToken(tokenString string) (RefreshTokenClaims, error) {
 }
 
 func ValidateResetPasswordToken(tokenString string) (ResetPasswordTokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(RESET_PASSWORD_TOKEN_SIGNATURE), nil
-	})
+	token, err := parseToken(RESET_PASSWORD_TOKEN_TYPE, tokenString)
 	if err != nil {
 		return ResetPasswordTokenClaims{}, err
 	}
@@ -169,3 +528,208 @@ func ValidateResetPasswordToken(tokenString string) (ResetPasswordTokenClaims, e
 
 	return ResetPasswordTokenClaims{}, errors.New("invalid reset password token")
 }
+
+func ValidateVerifyEmailToken(tokenString string) (VerifyEmailTokenClaims, error) {
+	token, err := parseToken(VERIFY_EMAIL_TOKEN_TYPE, tokenString)
+	if err != nil {
+		return VerifyEmailTokenClaims{}, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		rawId, _ := claims["id"].(string)
+		id, err := uuid.Parse(rawId)
+		if err != nil {
+			return VerifyEmailTokenClaims{}, errors.New("invalid uuid format in id claims")
+		}
+
+		email, _ := claims["email"].(string)
+		if email == "" {
+			return VerifyEmailTokenClaims{}, errors.New("verify email token has no email")
+		}
+
+		return VerifyEmailTokenClaims{
+			Id:    id,
+			Email: email,
+		}, nil
+	}
+
+	return VerifyEmailTokenClaims{}, errors.New("invalid verify email token")
+}
+
+func ValidateConfirmEmailChangeToken(tokenString string) (EmailChangeTokenClaims, error) {
+	return validateEmailChangeToken(CONFIRM_EMAIL_CHANGE_TOKEN_TYPE, tokenString)
+}
+
+func ValidateRevertEmailChangeToken(tokenString string) (EmailChangeTokenClaims, error) {
+	return validateEmailChangeToken(REVERT_EMAIL_CHANGE_TOKEN_TYPE, tokenString)
+}
+
+func validateEmailChangeToken(tokenType string, tokenString string) (EmailChangeTokenClaims, error) {
+	token, err := parseToken(tokenType, tokenString)
+	if err != nil {
+		return EmailChangeTokenClaims{}, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		rawId, _ := claims["id"].(string)
+		id, err := uuid.Parse(rawId)
+		if err != nil {
+			return EmailChangeTokenClaims{}, errors.New("invalid uuid format in id claims")
+		}
+
+		oldEmail, _ := claims["old_email"].(string)
+		newEmail, _ := claims["new_email"].(string)
+		if oldEmail == "" || newEmail == "" {
+			return EmailChangeTokenClaims{}, errors.New("email change token has no email")
+		}
+
+		return EmailChangeTokenClaims{
+			Id:       id,
+			OldEmail: oldEmail,
+			NewEmail: newEmail,
+		}, nil
+	}
+
+	return EmailChangeTokenClaims{}, errors.New("invalid email change token")
+}
+
+func ValidateRestoreAccountToken(tokenString string) (RestoreAccountTokenClaims, error) {
+	token, err := parseToken(RESTORE_ACCOUNT_TOKEN_TYPE, tokenString)
+	if err != nil {
+		return RestoreAccountTokenClaims{}, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		rawId, _ := claims["id"].(string)
+		id, err := uuid.Parse(rawId)
+		if err != nil {
+			return RestoreAccountTokenClaims{}, errors.New("invalid uuid format in id claims")
+		}
+
+		deletedAt, ok := claims["deleted_at"].(float64)
+		if !ok {
+			return RestoreAccountTokenClaims{}, errors.New("restore account token has no deletion time")
+		}
+
+		return RestoreAccountTokenClaims{
+			Id:        id,
+			DeletedAt: time.Unix(int64(deletedAt), 0),
+		}, nil
+	}
+
+	return RestoreAccountTokenClaims{}, errors.New("invalid restore account token")
+}
+
+func ValidateDataExportToken(tokenString string) (DataExportTokenClaims, error) {
+	token, err := parseToken(DATA_EXPORT_TOKEN_TYPE, tokenString)
+	if err != nil {
+		return DataExportTokenClaims{}, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		rawId, _ := claims["id"].(string)
+		id, err := uuid.Parse(rawId)
+		if err != nil {
+			return DataExportTokenClaims{}, errors.New("invalid uuid format in id claims")
+		}
+
+		rawUserId, _ := claims["sub"].(string)
+		userId, err := uuid.Parse(rawUserId)
+		if err != nil {
+			return DataExportTokenClaims{}, errors.New("invalid uuid format in sub claims")
+		}
+
+		return DataExportTokenClaims{
+			Id:     id,
+			UserId: userId,
+		}, nil
+	}
+
+	return DataExportTokenClaims{}, errors.New("invalid data export token")
+}
+
+func ValidateMagicLinkToken(tokenString string) (MagicLinkTokenClaims, error) {
+	token, err := parseToken(MAGIC_LINK_TOKEN_TYPE, tokenString)
+	if err != nil {
+		return MagicLinkTokenClaims{}, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		email, _ := claims["email"].(string)
+		tokenId, _ := claims["jti"].(string)
+		if email == "" || tokenId == "" {
+			return MagicLinkTokenClaims{}, errors.New("magic link token has no email or id")
+		}
+
+		return MagicLinkTokenClaims{
+			Email:   email,
+			TokenId: tokenId,
+		}, nil
+	}
+
+	return MagicLinkTokenClaims{}, errors.New("invalid magic link token")
+}
+
+func ValidateMfaChallengeToken(tokenString string) (MfaChallengeTokenClaims, error) {
+	token, err := parseToken(MFA_CHALLENGE_TOKEN_TYPE, tokenString)
+	if err != nil {
+		return MfaChallengeTokenClaims{}, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		rawId, _ := claims["id"].(string)
+		id, err := uuid.Parse(rawId)
+		if err != nil {
+			return MfaChallengeTokenClaims{}, errors.New("invalid uuid format in id claims")
+		}
+
+		challengeId, _ := claims["jti"].(string)
+		if challengeId == "" {
+			return MfaChallengeTokenClaims{}, errors.New("mfa challenge token has no id")
+		}
+
+		method, _ := claims["method"].(string)
+
+		return MfaChallengeTokenClaims{
+			Id:          id,
+			ChallengeId: challengeId,
+			Method:      method,
+		}, nil
+	}
+
+	return MfaChallengeTokenClaims{}, errors.New("invalid mfa challenge token")
+}
+
+// setOptionalClaims adds the claims only present on tokens issued to OAuth
+// clients.
+func setOptionalClaims(claims jwt.MapClaims, clientId string, scope string) {
+	if clientId != "" {
+		claims["client_id"] = clientId
+	}
+	if scope != "" {
+		claims["scope"] = scope
+	}
+}
+
+func signToken(tokenType string, claims jwt.MapClaims) (string, error) {
+	claims["typ"] = tokenType
+
+	return CurrentKeyring().Sign(claims)
+}
+
+func parseToken(tokenType string, tokenString string) (*jwt.Token, error) {
+	keyring := CurrentKeyring()
+
+	token, err := jwt.Parse(tokenString, keyring.Keyfunc, jwt.WithValidMethods(keyring.ValidMethods()))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if typ, _ := claims["typ"].(string); typ != tokenType {
+			return nil, fmt.Errorf("expected %s token but got %q", tokenType, typ)
+		}
+	}
+
+	return token, nil
+}
@@ -0,0 +1,71 @@
+package util_test
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The SHA-1 secret from the RFC 6238 test vectors, "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTotpCode(t *testing.T) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := util.TotpCode(rfcSecret, util.TotpStep(time.Unix(unix, 0)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+func TestValidateTotp(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, _ := util.TotpCode(rfcSecret, util.TotpStep(now))
+
+	t.Run("success", func(t *testing.T) {
+		step, ok := util.ValidateTotp(rfcSecret, code, now)
+
+		assert.True(t, ok)
+		assert.Equal(t, util.TotpStep(now), step)
+	})
+
+	t.Run("success: previous step", func(t *testing.T) {
+		_, ok := util.ValidateTotp(rfcSecret, code, now.Add(util.TOTP_PERIOD*time.Second))
+
+		assert.True(t, ok)
+	})
+
+	t.Run("failure: outside the window", func(t *testing.T) {
+		_, ok := util.ValidateTotp(rfcSecret, code, now.Add(2*util.TOTP_PERIOD*time.Second))
+
+		assert.False(t, ok)
+	})
+
+	t.Run("failure: wrong code", func(t *testing.T) {
+		_, ok := util.ValidateTotp(rfcSecret, "000000", now)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestTotpUri(t *testing.T) {
+	secret, err := util.GenerateTotpSecret()
+	assert.NoError(t, err)
+
+	uri := util.TotpUri("go-ddd", "test@example.com", secret)
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/go-ddd:test@example.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=go-ddd")
+}
@@ -0,0 +1,298 @@
+package util
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// WebAuthn (https://www.w3.org/TR/webauthn-2/) ceremonies verified with the
+// "none" attestation conveyance: the authenticator's own attestation is not
+// checked, only that the credential signs our challenges.
+const (
+	WEBAUTHN_CREATE = "webauthn.create"
+	WEBAUTHN_GET    = "webauthn.get"
+)
+
+// COSE algorithm identifiers supported for passkeys, by preference.
+const (
+	COSE_ALG_ES256 int64 = -7
+	COSE_ALG_EDDSA int64 = -8
+	COSE_ALG_RS256 int64 = -257
+)
+
+var COSE_ALGORITHMS = []int64{COSE_ALG_ES256, COSE_ALG_EDDSA, COSE_ALG_RS256}
+
+const (
+	authenticatorFlagUserPresent  = 0x01
+	authenticatorFlagUserVerified = 0x04
+	authenticatorFlagAttested     = 0x40
+	authenticatorFlagExtensions   = 0x80
+)
+
+// WebauthnRelyingParty is this service as the browser sees it. The id is the
+// registrable domain credentials are scoped to, and origins lists where the
+// ceremonies may be run from.
+type WebauthnRelyingParty struct {
+	Id      string
+	Name    string
+	Origins []string
+}
+
+type WebauthnClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type WebauthnAuthenticatorData struct {
+	RpIdHash     []byte
+	Flags        byte
+	SignCount    uint32
+	CredentialId []byte
+	// PublicKey is the COSE encoded key, only present when registering.
+	PublicKey []byte
+}
+
+func (d *WebauthnAuthenticatorData) UserPresent() bool {
+	return d.Flags&authenticatorFlagUserPresent != 0
+}
+
+func (d *WebauthnAuthenticatorData) UserVerified() bool {
+	return d.Flags&authenticatorFlagUserVerified != 0
+}
+
+func ParseWebauthnClientData(clientDataJSON []byte) (*WebauthnClientData, error) {
+	var clientData WebauthnClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return nil, fmt.Errorf("invalid client data: %w", err)
+	}
+
+	return &clientData, nil
+}
+
+// VerifyWebauthnRegistration checks the response to a creation ceremony and
+// returns the new credential.
+func VerifyWebauthnRegistration(rp WebauthnRelyingParty, challenge string, clientDataJSON []byte, attestationObject []byte) (*WebauthnAuthenticatorData, error) {
+	if err := verifyWebauthnClientData(rp, WEBAUTHN_CREATE, challenge, clientDataJSON); err != nil {
+		return nil, err
+	}
+
+	decoded, _, err := decodeCbor(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	attestation, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, errors.New("attestation object is not a map")
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("attestation object has no authenticator data")
+	}
+
+	authData, err := parseWebauthnAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyWebauthnAuthenticatorData(rp, authData); err != nil {
+		return nil, err
+	}
+	if authData.PublicKey == nil {
+		return nil, errors.New("authenticator data has no attested credential")
+	}
+	if _, _, err := parseCosePublicKey(authData.PublicKey); err != nil {
+		return nil, err
+	}
+
+	return authData, nil
+}
+
+// VerifyWebauthnAssertion checks the response to a get ceremony against the
+// stored credential and returns the authenticator's new signature counter.
+func VerifyWebauthnAssertion(rp WebauthnRelyingParty, challenge string, clientDataJSON []byte, rawAuthData []byte, signature []byte, publicKey []byte, signCount uint32) (uint32, error) {
+	if err := verifyWebauthnClientData(rp, WEBAUTHN_GET, challenge, clientDataJSON); err != nil {
+		return 0, err
+	}
+
+	authData, err := parseWebauthnAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+	if err := verifyWebauthnAuthenticatorData(rp, authData); err != nil {
+		return 0, err
+	}
+
+	key, alg, err := parseCosePublicKey(publicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	if err := verifyCoseSignature(key, alg, signed, signature); err != nil {
+		return 0, err
+	}
+
+	// Authenticators that count signatures must always count up, otherwise the
+	// credential may have been cloned. Synced passkeys keep the counter at 0.
+	if (authData.SignCount != 0 || signCount != 0) && authData.SignCount <= signCount {
+		return 0, errors.New("signature counter did not increase")
+	}
+
+	return authData.SignCount, nil
+}
+
+func verifyWebauthnClientData(rp WebauthnRelyingParty, ceremony string, challenge string, clientDataJSON []byte) error {
+	clientData, err := ParseWebauthnClientData(clientDataJSON)
+	if err != nil {
+		return err
+	}
+
+	if clientData.Type != ceremony {
+		return fmt.Errorf("expected %s ceremony but got %q", ceremony, clientData.Type)
+	}
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(clientData.Challenge), []byte(challenge)) != 1 {
+		return errors.New("challenge does not match")
+	}
+	if !slices.Contains(rp.Origins, clientData.Origin) {
+		return fmt.Errorf("origin %q is not allowed", clientData.Origin)
+	}
+
+	return nil
+}
+
+// verifyWebauthnAuthenticatorData requires user verification, so a passkey
+// is a second factor on its own.
+func verifyWebauthnAuthenticatorData(rp WebauthnRelyingParty, authData *WebauthnAuthenticatorData) error {
+	rpIdHash := sha256.Sum256([]byte(rp.Id))
+	if !bytes.Equal(authData.RpIdHash, rpIdHash[:]) {
+		return errors.New("credential belongs to another relying party")
+	}
+	if !authData.UserPresent() || !authData.UserVerified() {
+		return errors.New("user was not verified by the authenticator")
+	}
+
+	return nil
+}
+
+func parseWebauthnAuthenticatorData(raw []byte) (*WebauthnAuthenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+
+	authData := WebauthnAuthenticatorData{
+		RpIdHash:  raw[:32],
+		Flags:     raw[32],
+		SignCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rest := raw[37:]
+
+	if authData.Flags&authenticatorFlagAttested != 0 {
+		// AAGUID followed by the length prefixed credential id and its key.
+		if len(rest) < 18 {
+			return nil, errors.New("attested credential data is too short")
+		}
+		length := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if len(rest) < length {
+			return nil, errors.New("credential id is truncated")
+		}
+		authData.CredentialId = rest[:length]
+		rest = rest[length:]
+
+		_, afterKey, err := decodeCbor(rest)
+		if err != nil {
+			return nil, err
+		}
+		authData.PublicKey = rest[:len(rest)-len(afterKey)]
+		rest = afterKey
+	}
+
+	if authData.Flags&authenticatorFlagExtensions == 0 && len(rest) > 0 {
+		return nil, errors.New("unexpected trailing authenticator data")
+	}
+
+	return &authData, nil
+}
+
+// parseCosePublicKey reads a COSE_Key (RFC 9053) of one of COSE_ALGORITHMS.
+func parseCosePublicKey(raw []byte) (crypto.PublicKey, int64, error) {
+	decoded, _, err := decodeCbor(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	coseKey, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, 0, errors.New("public key is not a COSE key")
+	}
+
+	alg, _ := coseKey[int64(3)].(int64)
+	switch alg {
+	case COSE_ALG_ES256:
+		x, _ := coseKey[int64(-2)].([]byte)
+		y, _ := coseKey[int64(-3)].([]byte)
+		if coseKey[int64(1)] != int64(2) || coseKey[int64(-1)] != int64(1) || len(x) != 32 || len(y) != 32 {
+			return nil, 0, errors.New("invalid ES256 key")
+		}
+		point := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, 0, errors.New("ES256 key is not on the curve")
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, alg, nil
+	case COSE_ALG_EDDSA:
+		x, _ := coseKey[int64(-2)].([]byte)
+		if coseKey[int64(1)] != int64(1) || coseKey[int64(-1)] != int64(6) || len(x) != ed25519.PublicKeySize {
+			return nil, 0, errors.New("invalid EdDSA key")
+		}
+		return ed25519.PublicKey(x), alg, nil
+	case COSE_ALG_RS256:
+		n, _ := coseKey[int64(-1)].([]byte)
+		e, _ := coseKey[int64(-2)].([]byte)
+		if coseKey[int64(1)] != int64(3) || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, 0, errors.New("invalid RS256 key")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, alg, nil
+	}
+
+	return nil, 0, fmt.Errorf("unsupported COSE algorithm %d", alg)
+}
+
+func verifyCoseSignature(key crypto.PublicKey, alg int64, signed []byte, signature []byte) error {
+	digest := sha256.Sum256(signed)
+
+	valid := false
+	switch alg {
+	case COSE_ALG_ES256:
+		valid = ecdsa.VerifyASN1(key.(*ecdsa.PublicKey), digest[:], signature)
+	case COSE_ALG_EDDSA:
+		valid = ed25519.Verify(key.(ed25519.PublicKey), signed, signature)
+	case COSE_ALG_RS256:
+		valid = rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	}
+
+	if !valid {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package util_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"github/imfropz/go-ddd/common/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rp = util.WebauthnRelyingParty{
+	Id:      "example.com",
+	Name:    "Example",
+	Origins: []string{"https://example.com"},
+}
+
+// cborHead and cborMap encode just enough CBOR to play an authenticator.
+func cborHead(major byte, n int) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n < 256:
+		return []byte{major<<5 | 24, byte(n)}
+	default:
+		return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+	}
+}
+
+func cborValue(value any) []byte {
+	switch v := value.(type) {
+	case int:
+		if v < 0 {
+			return cborHead(1, -1-v)
+		}
+		return cborHead(0, v)
+	case []byte:
+		return append(cborHead(2, len(v)), v...)
+	case string:
+		return append(cborHead(3, len(v)), v...)
+	case map[any]any:
+		out := cborHead(5, len(v))
+		for key, item := range v {
+			out = append(out, cborValue(key)...)
+			out = append(out, cborValue(item)...)
+		}
+		return out
+	}
+	panic("unsupported value")
+}
+
+type fakeAuthenticator struct {
+	credentialId []byte
+	coseKey      []byte
+	sign         func(data []byte) []byte
+	signCount    uint32
+}
+
+func newES256Authenticator() *fakeAuthenticator {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	x, y := make([]byte, 32), make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+
+	return &fakeAuthenticator{
+		credentialId: []byte("es256-credential"),
+		coseKey:      cborValue(map[any]any{1: 2, 3: -7, -1: 1, -2: x, -3: y}),
+		sign: func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			signature, _ := ecdsa.SignASN1(rand.Reader, key, digest[:])
+			return signature
+		},
+	}
+}
+
+func newEdDSAAuthenticator() *fakeAuthenticator {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+
+	return &fakeAuthenticator{
+		credentialId: []byte("eddsa-credential"),
+		coseKey:      cborValue(map[any]any{1: 1, 3: -8, -1: 6, -2: []byte(public)}),
+		sign: func(data []byte) []byte {
+			return ed25519.Sign(private, data)
+		},
+	}
+}
+
+func (a *fakeAuthenticator) authData(rpId string, flags byte, attested bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(rpId))
+	out := append([]byte(nil), rpIdHash[:]...)
+	if attested {
+		flags |= 0x40
+	}
+	out = append(out, flags)
+	out = binary.BigEndian.AppendUint32(out, a.signCount)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credentialId)))
+		out = append(out, a.credentialId...)
+		out = append(out, a.coseKey...)
+	}
+	return out
+}
+
+func clientData(ceremony string, challenge string, origin string) []byte {
+	raw, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	return raw
+}
+
+func (a *fakeAuthenticator) create(challenge string) ([]byte, []byte) {
+	attestationObject := cborValue(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": a.authData(rp.Id, 0x05, true),
+	})
+	return clientData(util.WEBAUTHN_CREATE, challenge, "https://example.com"), attestationObject
+}
+
+func (a *fakeAuthenticator) get(challenge string) ([]byte, []byte, []byte) {
+	clientDataJSON := clientData(util.WEBAUTHN_GET, challenge, "https://example.com")
+	authData := a.authData(rp.Id, 0x05, false)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signature := a.sign(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	return clientDataJSON, authData, signature
+}
+
+func TestVerifyWebauthnRegistration(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		for _, authenticator := range []*fakeAuthenticator{newES256Authenticator(), newEdDSAAuthenticator()} {
+			clientDataJSON, attestationObject := authenticator.create("challenge")
+
+			credential, err := util.VerifyWebauthnRegistration(rp, "challenge", clientDataJSON, attestationObject)
+
+			assert.NoError(t, err)
+			assert.Equal(t, authenticator.credentialId, credential.CredentialId)
+			assert.Equal(t, authenticator.coseKey, credential.PublicKey)
+		}
+	})
+
+	t.Run("failure: wrong challenge", func(t *testing.T) {
+		clientDataJSON, attestationObject := newES256Authenticator().create("other-challenge")
+
+		_, err := util.VerifyWebauthnRegistration(rp, "challenge", clientDataJSON, attestationObject)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: wrong origin", func(t *testing.T) {
+		authenticator := newES256Authenticator()
+		_, attestationObject := authenticator.create("challenge")
+		clientDataJSON := clientData(util.WEBAUTHN_CREATE, "challenge", "https://evil.example")
+
+		_, err := util.VerifyWebauthnRegistration(rp, "challenge", clientDataJSON, attestationObject)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: user not verified", func(t *testing.T) {
+		authenticator := newES256Authenticator()
+		clientDataJSON, _ := authenticator.create("challenge")
+		attestationObject := cborValue(map[any]any{
+			"fmt":      "none",
+			"attStmt":  map[any]any{},
+			"authData": authenticator.authData(rp.Id, 0x01, true),
+		})
+
+		_, err := util.VerifyWebauthnRegistration(rp, "challenge", clientDataJSON, attestationObject)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: truncated attestation object", func(t *testing.T) {
+		clientDataJSON, attestationObject := newES256Authenticator().create("challenge")
+
+		_, err := util.VerifyWebauthnRegistration(rp, "challenge", clientDataJSON, attestationObject[:len(attestationObject)/2])
+
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyWebauthnAssertion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		for _, authenticator := range []*fakeAuthenticator{newES256Authenticator(), newEdDSAAuthenticator()} {
+			authenticator.signCount = 8
+			clientDataJSON, authData, signature := authenticator.get("challenge")
+
+			signCount, err := util.VerifyWebauthnAssertion(rp, "challenge", clientDataJSON, authData, signature, authenticator.coseKey, 7)
+
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(8), signCount)
+		}
+	})
+
+	t.Run("success: synced passkey without counter", func(t *testing.T) {
+		authenticator := newES256Authenticator()
+		clientDataJSON, authData, signature := authenticator.get("challenge")
+
+		_, err := util.VerifyWebauthnAssertion(rp, "challenge", clientDataJSON, authData, signature, authenticator.coseKey, 0)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: signed by another key", func(t *testing.T) {
+		clientDataJSON, authData, signature := newES256Authenticator().get("challenge")
+
+		_, err := util.VerifyWebauthnAssertion(rp, "challenge", clientDataJSON, authData, signature, newES256Authenticator().coseKey, 0)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: counter went back", func(t *testing.T) {
+		authenticator := newES256Authenticator()
+		authenticator.signCount = 3
+		clientDataJSON, authData, signature := authenticator.get("challenge")
+
+		_, err := util.VerifyWebauthnAssertion(rp, "challenge", clientDataJSON, authData, signature, authenticator.coseKey, 3)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: creation response replayed", func(t *testing.T) {
+		authenticator := newES256Authenticator()
+		_, authData, signature := authenticator.get("challenge")
+		clientDataJSON := clientData(util.WEBAUTHN_CREATE, "challenge", "https://example.com")
+
+		_, err := util.VerifyWebauthnAssertion(rp, "challenge", clientDataJSON, authData, signature, authenticator.coseKey, 0)
+
+		assert.Error(t, err)
+	})
+}
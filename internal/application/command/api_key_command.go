@@ -0,0 +1,33 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateApiKeyCommand struct {
+	UserId    uuid.UUID
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+type CreateApiKeyCommandResult struct {
+	Result *common.ApiKeyResult
+	Key    string
+}
+
+type ListApiKeysCommand struct {
+	UserId uuid.UUID
+}
+
+type ListApiKeysCommandResult struct {
+	Result []*common.ApiKeyResult
+}
+
+type RevokeApiKeyCommand struct {
+	UserId uuid.UUID
+	Id     uuid.UUID
+}
@@ -0,0 +1,25 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+
+	"github.com/google/uuid"
+)
+
+type ListAuditEventsCommand struct {
+	Criteria *criteria.AuditEventCriteria
+}
+
+type ListAuditEventsCommandResult struct {
+	Result *common.AuditEventListResult
+}
+
+type ListUserAuditEventsCommand struct {
+	UserId   uuid.UUID
+	Criteria *criteria.AuditEventCriteria
+}
+
+type ListUserAuditEventsCommandResult struct {
+	Result *common.AuditEventListResult
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+
+	"github.com/google/uuid"
+)
+
+type RequestDataExportCommand struct {
+	UserId uuid.UUID
+}
+
+type RequestDataExportCommandResult struct {
+	Result *common.DataExportResult
+}
+
+type GetDataExportCommand struct {
+	UserId uuid.UUID
+}
+
+type GetDataExportCommandResult struct {
+	Result *common.DataExportResult
+}
+
+type ProcessDataExportCommand struct {
+	Id     uuid.UUID
+	UserId uuid.UUID
+}
+
+type DownloadDataExportCommand struct {
+	Token string
+}
+
+type DownloadDataExportCommandResult struct {
+	Result *common.DataExportFileResult
+}
@@ -1,6 +1,8 @@
 package command
 
 type DeleteProfileCommand struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IpAddress string
+	UserAgent string
 }
@@ -0,0 +1,23 @@
+package command
+
+import "github/imfropz/go-ddd/internal/application/common"
+
+type ConfirmEmailChangeCommand struct {
+	Token     string
+	IpAddress string
+	UserAgent string
+}
+
+type ConfirmEmailChangeCommandResult struct {
+	Result *common.UserResult
+}
+
+type RevertEmailChangeCommand struct {
+	Token     string
+	IpAddress string
+	UserAgent string
+}
+
+type RevertEmailChangeCommandResult struct {
+	Result *common.UserResult
+}
@@ -3,8 +3,10 @@ package command
 import "github/imfropz/go-ddd/internal/application/common"
 
 type LoginCommand struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IpAddress string
+	UserAgent string
 }
 
 type LoginCommandResult struct {
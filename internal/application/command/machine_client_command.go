@@ -0,0 +1,17 @@
+package command
+
+import "github/imfropz/go-ddd/internal/application/common"
+
+type RegisterMachineClientCommand struct {
+	Name   string
+	Scopes []string
+}
+
+type RegisterMachineClientCommandResult struct {
+	Result       *common.MachineClientResult
+	ClientSecret string
+}
+
+type ListMachineClientsCommandResult struct {
+	Result []*common.MachineClientResult
+}
@@ -0,0 +1,19 @@
+package command
+
+import "github/imfropz/go-ddd/internal/application/common"
+
+type RequestMagicLinkCommand struct {
+	Email     string
+	IpAddress string
+	UserAgent string
+}
+
+type LoginWithMagicLinkCommand struct {
+	Token     string
+	IpAddress string
+	UserAgent string
+}
+
+type LoginWithMagicLinkCommandResult struct {
+	Result *common.UserResult
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+
+	"github.com/google/uuid"
+)
+
+type EnrollTotpCommand struct {
+	UserId uuid.UUID
+	Email  string
+}
+
+type EnrollTotpCommandResult struct {
+	Result *common.TotpEnrollmentResult
+}
+
+type ConfirmTotpCommand struct {
+	UserId uuid.UUID
+	Code   string
+}
+
+type ConfirmTotpCommandResult struct {
+	Result *common.RecoveryCodesResult
+}
+
+type DisableTotpCommand struct {
+	UserId    uuid.UUID
+	Password  string
+	IpAddress string
+}
+
+type GetMfaStatusCommand struct {
+	UserId uuid.UUID
+}
+
+type GetMfaStatusCommandResult struct {
+	Result *common.MfaStatusResult
+}
+
+type CreateMfaChallengeCommand struct {
+	User *common.UserResult
+	// Method is the login method the challenge completes, handed back by
+	// VerifyMfaChallenge.
+	Method string
+}
+
+type CreateMfaChallengeCommandResult struct {
+	Result *common.MfaChallengeResult
+}
+
+// VerifyMfaChallengeCommand takes either a code from the authenticator app or
+// one of the recovery codes.
+type VerifyMfaChallengeCommand struct {
+	MfaToken     string
+	Code         string
+	RecoveryCode string
+	IpAddress    string
+}
+
+type VerifyMfaChallengeCommandResult struct {
+	Result *common.UserResult
+	Method string
+}
+
+type RegenerateRecoveryCodesCommand struct {
+	UserId    uuid.UUID
+	Password  string
+	IpAddress string
+}
+
+type RegenerateRecoveryCodesCommandResult struct {
+	Result *common.RecoveryCodesResult
+}
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+
+	"github.com/google/uuid"
+)
+
+type RegisterOAuthClientCommand struct {
+	OwnerId      uuid.UUID
+	Name         string
+	RedirectUris []string
+	Scopes       []string
+	Public       bool
+}
+
+type RegisterOAuthClientCommandResult struct {
+	Result       *common.OAuthClientResult
+	ClientSecret string
+}
+
+type ListOAuthClientsCommand struct {
+	OwnerId uuid.UUID
+}
+
+type ListOAuthClientsCommandResult struct {
+	Result []*common.OAuthClientResult
+}
+
+type AuthorizeCommand struct {
+	UserId              uuid.UUID
+	SessionId           uuid.UUID
+	ResponseType        string
+	ClientId            string
+	RedirectUri         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+// AuthorizeCommandResult asks for the user's consent instead of carrying a
+// code when ConsentRequired is set.
+type AuthorizeCommandResult struct {
+	RedirectUri     string
+	State           string
+	Code            string
+	ConsentRequired bool
+	ConsentId       string
+	ClientName      string
+	Scopes          []string
+}
+
+type OAuthConsentCommand struct {
+	UserId    uuid.UUID
+	SessionId uuid.UUID
+	ConsentId string
+	Approved  bool
+}
+
+type OAuthTokenCommand struct {
+	GrantType    string
+	ClientId     string
+	ClientSecret string
+	Code         string
+	RedirectUri  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	IpAddress    string
+	UserAgent    string
+}
+
+type OAuthTokenCommandResult struct {
+	Result *common.TokenResult
+}
+
+type IntrospectCommand struct {
+	ClientId      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
+
+type IntrospectCommandResult struct {
+	Result *common.IntrospectionResult
+}
+
+type RevokeCommand struct {
+	ClientId      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
+
+type UserInfoCommand struct {
+	UserId   uuid.UUID
+	ClientId string
+	Scope    string
+}
+
+type UserInfoCommandResult struct {
+	Result *common.UserInfoResult
+}
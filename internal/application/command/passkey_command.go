@@ -0,0 +1,59 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+
+	"github.com/google/uuid"
+)
+
+type BeginPasskeyRegistrationCommand struct {
+	UserId uuid.UUID
+}
+
+type BeginPasskeyRegistrationCommandResult struct {
+	Result *common.PasskeyRegistrationOptionsResult
+}
+
+type FinishPasskeyRegistrationCommand struct {
+	UserId            uuid.UUID
+	Name              string
+	CredentialId      []byte
+	ClientDataJSON    []byte
+	AttestationObject []byte
+	Transports        []string
+}
+
+type FinishPasskeyRegistrationCommandResult struct {
+	Result *common.PasskeyResult
+}
+
+type BeginPasskeyLoginCommandResult struct {
+	Result *common.PasskeyLoginOptionsResult
+}
+
+type FinishPasskeyLoginCommand struct {
+	CredentialId      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+	IpAddress         string
+	UserAgent         string
+}
+
+type FinishPasskeyLoginCommandResult struct {
+	Result *common.UserResult
+}
+
+type ListPasskeysCommand struct {
+	UserId uuid.UUID
+}
+
+type ListPasskeysCommandResult struct {
+	Result []*common.PasskeyResult
+}
+
+type DeletePasskeyCommand struct {
+	UserId uuid.UUID
+	Id     uuid.UUID
+}
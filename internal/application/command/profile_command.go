@@ -3,7 +3,9 @@ package command
 import "github/imfropz/go-ddd/internal/application/common"
 
 type ProfileCommand struct {
-	Email string
+	Email     string
+	IpAddress string
+	UserAgent string
 }
 
 type ProfileCommandResult struct {
@@ -3,11 +3,16 @@ package command
 import "github/imfropz/go-ddd/internal/application/common"
 
 type RegisterCommand struct {
-	Name     string
-	Email    string
-	Password string
+	Name      string
+	Email     string
+	Password  string
+	IpAddress string
+	UserAgent string
 }
 
+// RegisterCommandResult.VerificationRequired tells that the user cannot sign
+// in before verifying their email.
 type RegisterCommandResult struct {
-	Result *common.UserResult
+	Result               *common.UserResult
+	VerificationRequired bool
 }
@@ -3,7 +3,9 @@ package command
 import "github/imfropz/go-ddd/internal/application/common"
 
 type ResetPasswordCommand struct {
-	Email string
+	Email     string
+	IpAddress string
+	UserAgent string
 }
 
 type ResetPasswordCommandResult struct {
@@ -13,6 +15,8 @@ type ResetPasswordCommandResult struct {
 type ResetPasswordWithTokenCommand struct {
 	Token       string
 	NewPassword string
+	IpAddress   string
+	UserAgent   string
 }
 
 type ResetPasswordWithTokenCommandResult struct {
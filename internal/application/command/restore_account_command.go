@@ -0,0 +1,13 @@
+package command
+
+import "github/imfropz/go-ddd/internal/application/common"
+
+type RestoreAccountCommand struct {
+	Token     string
+	IpAddress string
+	UserAgent string
+}
+
+type RestoreAccountCommandResult struct {
+	Result *common.UserResult
+}
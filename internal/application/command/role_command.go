@@ -0,0 +1,42 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+
+	"github.com/google/uuid"
+)
+
+type SeedRolesCommand struct {
+	AdminEmails []string
+}
+
+type ListRolesCommandResult struct {
+	Result []*common.RoleResult
+}
+
+type ListUserRolesCommand struct {
+	UserId uuid.UUID
+}
+
+type ListUserRolesCommandResult struct {
+	Result []*common.RoleResult
+}
+
+type AssignRoleCommand struct {
+	UserId uuid.UUID
+	Role   string
+}
+
+type UnassignRoleCommand struct {
+	UserId uuid.UUID
+	Role   string
+}
+
+type ResolvePermissionsCommand struct {
+	UserId uuid.UUID
+}
+
+type ResolvePermissionsCommandResult struct {
+	Roles       []string
+	Permissions []string
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+
+	"github.com/google/uuid"
+)
+
+type CreateSessionCommand struct {
+	UserId    uuid.UUID
+	Device    string
+	IpAddress string
+	UserAgent string
+}
+
+type CreateSessionCommandResult struct {
+	Result *common.SessionResult
+}
+
+type TouchSessionCommand struct {
+	UserId    uuid.UUID
+	SessionId uuid.UUID
+}
+
+type GetSessionCommand struct {
+	UserId    uuid.UUID
+	SessionId uuid.UUID
+}
+
+type GetSessionCommandResult struct {
+	Result *common.SessionResult
+}
+
+type ListSessionsCommand struct {
+	UserId uuid.UUID
+}
+
+type ListSessionsCommandResult struct {
+	Result []*common.SessionResult
+}
+
+type RevokeSessionCommand struct {
+	UserId    uuid.UUID
+	SessionId uuid.UUID
+}
+
+type RevokeOtherSessionsCommand struct {
+	UserId           uuid.UUID
+	CurrentSessionId uuid.UUID
+}
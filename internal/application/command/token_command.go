@@ -0,0 +1,76 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/common"
+)
+
+type IssueTokenCommand struct {
+	User      *common.UserResult
+	Device    string
+	IpAddress string
+	UserAgent string
+	ClientId  string
+	Scope     string
+	// Method is set when the tokens complete a login, to one of the
+	// entity.LOGIN_METHOD values. The login is then audited and published.
+	Method string
+}
+
+type IssueTokenCommandResult struct {
+	Result *common.TokenResult
+}
+
+type IssueMachineTokenCommand struct {
+	Client *common.MachineClientResult
+	Scope  string
+}
+
+type IssueMachineTokenCommandResult struct {
+	Result *common.TokenResult
+}
+
+type RefreshTokenCommand struct {
+	RefreshToken string
+	ClientId     string
+}
+
+type RefreshTokenCommandResult struct {
+	Result *common.TokenResult
+}
+
+type ValidateAccessTokenCommand struct {
+	AccessToken string
+}
+
+type ValidateAccessTokenCommandResult struct {
+	Result *util.AccessTokenClaims
+}
+
+type ValidateApiKeyCommand struct {
+	ApiKey string
+}
+
+type ValidateApiKeyCommandResult struct {
+	Result *util.AccessTokenClaims
+}
+
+type IntrospectTokenCommand struct {
+	Token         string
+	TokenTypeHint string
+}
+
+type IntrospectTokenCommandResult struct {
+	Result *common.IntrospectionResult
+}
+
+type RevokeTokenCommand struct {
+	Token         string
+	TokenTypeHint string
+	ClientId      string
+}
+
+type LogoutCommand struct {
+	Claims       *util.AccessTokenClaims
+	RefreshToken string
+}
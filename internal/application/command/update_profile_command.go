@@ -8,12 +8,18 @@ import (
 
 type UpdateProfileCommand struct {
 	Id              uuid.UUID
+	SessionId       uuid.UUID
 	Name            string
 	Email           string
 	CurrentPassword string
 	NewPassword     string
+	IpAddress       string
+	UserAgent       string
 }
 
+// UpdateProfileCommandResult.PendingEmail is set when the email was asked to
+// change; the user keeps their current email until the new one is confirmed.
 type UpdateProfileCommandResult struct {
-	Result *common.UserResult
+	Result       *common.UserResult
+	PendingEmail string
 }
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+
+	"github.com/google/uuid"
+)
+
+type GetUserCommand struct {
+	Id uuid.UUID
+}
+
+type GetUserCommandResult struct {
+	Result *common.UserResult
+}
+
+type ListUsersCommand struct {
+	Criteria *criteria.UserCriteria
+}
+
+type ListUsersCommandResult struct {
+	Result *common.UserListResult
+}
+
+type UpdateUserCommand struct {
+	Id    uuid.UUID
+	Name  string
+	Email string
+}
+
+type UpdateUserCommandResult struct {
+	Result *common.UserResult
+}
+
+type ForcePasswordResetCommand struct {
+	Id uuid.UUID
+}
+
+// ActorId is the admin making the change, who may not disable or delete
+// their own account.
+type DisableUserCommand struct {
+	ActorId uuid.UUID
+	Id      uuid.UUID
+}
+
+type DisableUserCommandResult struct {
+	Result *common.UserResult
+}
+
+type EnableUserCommand struct {
+	Id uuid.UUID
+}
+
+type EnableUserCommandResult struct {
+	Result *common.UserResult
+}
+
+type DeleteUserCommand struct {
+	ActorId uuid.UUID
+	Id      uuid.UUID
+}
+
+type RestoreUserCommand struct {
+	Id uuid.UUID
+}
+
+type RestoreUserCommandResult struct {
+	Result *common.UserResult
+}
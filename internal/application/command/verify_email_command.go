@@ -0,0 +1,19 @@
+package command
+
+import "github/imfropz/go-ddd/internal/application/common"
+
+type VerifyEmailCommand struct {
+	Token     string
+	IpAddress string
+	UserAgent string
+}
+
+type VerifyEmailCommandResult struct {
+	Result *common.UserResult
+}
+
+type ResendVerifyEmailCommand struct {
+	Email     string
+	IpAddress string
+	UserAgent string
+}
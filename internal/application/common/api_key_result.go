@@ -0,0 +1,18 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApiKeyResult struct {
+	Id         uuid.UUID
+	Name       string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
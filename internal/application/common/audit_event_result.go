@@ -0,0 +1,25 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEventResult struct {
+	Id        uuid.UUID
+	CreatedAt time.Time
+	UserId    *uuid.UUID
+	ActorId   *uuid.UUID
+	Email     string
+	Action    string
+	Outcome   string
+	Reason    string
+	IpAddress string
+	UserAgent string
+}
+
+type AuditEventListResult struct {
+	Events []*AuditEventResult
+	Total  int64
+}
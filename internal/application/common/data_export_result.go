@@ -0,0 +1,23 @@
+package common
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExportResult struct {
+	Id          uuid.UUID
+	Status      string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+// DataExportFileResult is the archive being downloaded. The caller has to
+// close Content.
+type DataExportFileResult struct {
+	Name    string
+	Content io.ReadCloser
+}
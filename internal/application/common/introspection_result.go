@@ -0,0 +1,22 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntrospectionResult describes a token as seen by RFC 7662. Every field but
+// Active is left empty for tokens that are not active.
+type IntrospectionResult struct {
+	Active      bool
+	TokenType   string
+	Subject     uuid.UUID
+	SubjectType string
+	Username    string
+	SessionId   uuid.UUID
+	TokenId     string
+	ClientId    string
+	Scope       string
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,16 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MachineClientResult struct {
+	Id        uuid.UUID
+	ClientId  string
+	Name      string
+	Scopes    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,24 @@
+package common
+
+import "time"
+
+type MfaStatusResult struct {
+	TotpEnabled            bool
+	RecoveryCodesRemaining int
+}
+
+// RecoveryCodesResult holds the plain codes. They are only ever shown once.
+type RecoveryCodesResult struct {
+	Codes []string
+}
+
+type TotpEnrollmentResult struct {
+	Secret string
+	Uri    string
+}
+
+type MfaChallengeResult struct {
+	Required  bool
+	MfaToken  string
+	ExpiresAt time.Time
+}
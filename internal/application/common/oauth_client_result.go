@@ -0,0 +1,18 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OAuthClientResult struct {
+	Id           uuid.UUID
+	ClientId     string
+	Name         string
+	RedirectUris []string
+	Scopes       []string
+	Public       bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasskeyResult struct {
+	Id           uuid.UUID
+	Name         string
+	CredentialId []byte
+	Transports   []string
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+// PasskeyRegistrationOptionsResult is what the browser needs to create a
+// credential for the user.
+type PasskeyRegistrationOptionsResult struct {
+	Challenge          string
+	RelyingPartyId     string
+	RelyingPartyName   string
+	UserHandle         []byte
+	UserName           string
+	UserDisplayName    string
+	Algorithms         []int64
+	Timeout            time.Duration
+	ExcludeCredentials []*PasskeyResult
+}
+
+type PasskeyLoginOptionsResult struct {
+	Challenge      string
+	RelyingPartyId string
+	Timeout        time.Duration
+}
@@ -0,0 +1,17 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RoleResult struct {
+	Id          uuid.UUID
+	Name        string
+	Description string
+	Permissions []string
+	RequireMfa  bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,17 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionResult struct {
+	Id         uuid.UUID
+	UserId     uuid.UUID
+	Device     string
+	IpAddress  string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
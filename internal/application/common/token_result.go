@@ -0,0 +1,12 @@
+package common
+
+import "github.com/google/uuid"
+
+type TokenResult struct {
+	SessionId    uuid.UUID
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int
+	Scope        string
+	IdToken      string
+}
@@ -0,0 +1,10 @@
+package common
+
+import "github.com/google/uuid"
+
+type UserInfoResult struct {
+	Subject       uuid.UUID
+	Name          string
+	Email         string
+	EmailVerified bool
+}
@@ -7,10 +7,21 @@ import (
 )
 
 type UserResult struct {
-	Id        uuid.UUID
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id            uuid.UUID
+	Name          string
+	Email         string
+	Password      string
+	EmailVerified bool
+	VerifiedAt    *time.Time
+	DisabledAt    *time.Time
+	DeletedAt     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// UserListResult is a page of users. NextCursor is empty on the last page.
+type UserListResult struct {
+	Users      []*UserResult
+	Total      int64
+	NextCursor string
 }
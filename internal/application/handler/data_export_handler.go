@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+// DataExportEventHandler is the worker that builds the data exports users ask
+// for.
+type DataExportEventHandler struct {
+	dataExportService interfaces.DataExportService
+}
+
+func NewDataExportEventHandler(dataExportService interfaces.DataExportService) *DataExportEventHandler {
+	return &DataExportEventHandler{
+		dataExportService: dataExportService,
+	}
+}
+
+func (handler *DataExportEventHandler) Handle(topic string, key, value []byte) error {
+	switch topic {
+	case entity.DATA_EXPORT_REQUESTED:
+		var event entity.DataExportRequestedEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal data export requested event: %v", err)
+		}
+		return handler.dataExportService.ProcessDataExport(&command.ProcessDataExportCommand{
+			Id:     event.Id,
+			UserId: event.UserId,
+		})
+	default:
+		return fmt.Errorf("unknown topic: %s", topic)
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"fmt"
+	"github/imfropz/go-ddd/internal/domain/event"
+	"sort"
+)
+
+// EventRouter hands every event to the handler registered for its topic, so
+// a single consumer can serve several handlers.
+type EventRouter map[string]event.EventHandler
+
+func (router EventRouter) Topics() []string {
+	topics := make([]string, 0, len(router))
+	for topic := range router {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func (router EventRouter) Handle(topic string, key, value []byte) error {
+	handler, ok := router[topic]
+	if !ok {
+		return fmt.Errorf("unknown topic: %s", topic)
+	}
+	return handler.Handle(topic, key, value)
+}
@@ -2,22 +2,37 @@ package handler
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github/imfropz/go-ddd/internal/application/command"
 	"github/imfropz/go-ddd/internal/application/interfaces"
 	"github/imfropz/go-ddd/internal/domain/entity"
-	"log/slog"
-	"os"
+	"html"
+	"net/url"
+	"time"
 )
 
+// EmailLinks are the frontend pages that receive an emailed token as their
+// token query parameter.
+type EmailLinks struct {
+	MagicLink          string
+	VerifyEmail        string
+	ConfirmEmailChange string
+	RevertEmailChange  string
+	RestoreAccount     string
+	DataExport         string
+}
+
 type NotificationEventHandler struct {
 	notificationService interfaces.NotificationService
+	fromEmail           string
+	links               EmailLinks
 }
 
-func NewNotificationEventHandler(notificationService interfaces.NotificationService) *NotificationEventHandler {
+func NewNotificationEventHandler(notificationService interfaces.NotificationService, fromEmail string, links EmailLinks) *NotificationEventHandler {
 	return &NotificationEventHandler{
 		notificationService: notificationService,
+		fromEmail:           fromEmail,
+		links:               links,
 	}
 }
 
@@ -29,24 +44,158 @@ func (handler *NotificationEventHandler) Handle(topic string, key, value []byte)
 			return fmt.Errorf("failed to unmarshal reset password event: %v", err)
 		}
 		return handler.handleResetPassword(event)
+	case entity.VERIFY_EMAIL:
+		var event entity.VerifyEmailEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal verify email event: %v", err)
+		}
+		return handler.handleVerifyEmail(event)
+	case entity.EMAIL_CHANGE:
+		var event entity.EmailChangeEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal email change event: %v", err)
+		}
+		return handler.handleEmailChange(event)
+	case entity.LOGIN_LOCKED:
+		var event entity.LoginLockedEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal login locked event: %v", err)
+		}
+		return handler.handleLoginLocked(event)
+	case entity.USER_DELETION_SCHEDULED:
+		var event entity.UserDeletionScheduledEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal user deletion scheduled event: %v", err)
+		}
+		return handler.handleUserDeletionScheduled(event)
+	case entity.DATA_EXPORT_READY:
+		var event entity.DataExportReadyEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal data export ready event: %v", err)
+		}
+		return handler.handleDataExportReady(event)
+	case entity.MAGIC_LINK:
+		var event entity.MagicLinkEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal magic link event: %v", err)
+		}
+		return handler.handleMagicLink(event)
+	case entity.RECOVERY_CODES_REGENERATED:
+		var event entity.RecoveryCodesRegeneratedEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal recovery codes regenerated event: %v", err)
+		}
+		return handler.handleRecoveryCodesRegenerated(event)
 	default:
 		return fmt.Errorf("unknown topic: %s", topic)
 	}
 }
 
 func (handler *NotificationEventHandler) handleResetPassword(event entity.ResetPasswordEvent) error {
-	fromEmail := os.Getenv("FROM_EMAIL")
-	if fromEmail == "" {
-		slog.Error("missing FROM_EMAIL enviorment variable")
-		return errors.New("missing FROM_EMAIL enviorment variable")
-	}
-
 	// TODO: Update the html body template
 	handler.notificationService.SendEmail(&command.SendEmailCommand{
-		FromEmail: fromEmail,
+		FromEmail: handler.fromEmail,
 		ToEmails:  []string{event.Email},
 		Subject:   "Reset Password - Buon18",
 		HtmlBody:  `<h1>Hello World</h1> <p>This is a test email.</p>`,
 	})
 	return nil
 }
+
+func (handler *NotificationEventHandler) handleVerifyEmail(event entity.VerifyEmailEvent) error {
+	link := handler.links.VerifyEmail + "?token=" + url.QueryEscape(event.Token)
+
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.Email},
+		Subject:   "Verify Your Email - Buon18",
+		HtmlBody: fmt.Sprintf(`<p>Hello %s,</p> <p><a href="%s">Click here to verify your email</a>. The link expires at %s.</p> <p>If you did not create an account, you can ignore this email.</p>`,
+			html.EscapeString(event.Name), html.EscapeString(link), event.Exp.UTC().Format(time.RFC1123)),
+	})
+	return nil
+}
+
+// handleEmailChange asks the new address to confirm the change and tells the
+// old address how to undo it.
+func (handler *NotificationEventHandler) handleEmailChange(event entity.EmailChangeEvent) error {
+	confirmLink := handler.links.ConfirmEmailChange + "?token=" + url.QueryEscape(event.ConfirmToken)
+	revertLink := handler.links.RevertEmailChange + "?token=" + url.QueryEscape(event.RevertToken)
+
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.NewEmail},
+		Subject:   "Confirm Your New Email - Buon18",
+		HtmlBody: fmt.Sprintf(`<p>Hello %s,</p> <p><a href="%s">Click here to use this address for your account</a>. The link expires at %s.</p> <p>If you did not ask for it, you can ignore this email.</p>`,
+			html.EscapeString(event.Name), html.EscapeString(confirmLink), event.Exp.UTC().Format(time.RFC1123)),
+	})
+
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.OldEmail},
+		Subject:   "Security Alert - Buon18",
+		HtmlBody: fmt.Sprintf(`<p>Hello %s,</p> <p>Someone asked to change the email of your account to %s.</p> <p>If this was not you, <a href="%s">click here to keep this address</a> and sign out every session.</p>`,
+			html.EscapeString(event.Name), html.EscapeString(event.NewEmail), html.EscapeString(revertLink)),
+	})
+	return nil
+}
+
+func (handler *NotificationEventHandler) handleLoginLocked(event entity.LoginLockedEvent) error {
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.Email},
+		Subject:   "Security Alert - Buon18",
+		HtmlBody: fmt.Sprintf(`<p>Hello %s,</p> <p>Signing in to your account was locked until %s after too many failed attempts from %s.</p> <p>If this was not you, change your password once the lock expires.</p>`,
+			html.EscapeString(event.Name), event.Until.UTC().Format(time.RFC1123), html.EscapeString(event.IpAddress)),
+	})
+	return nil
+}
+
+func (handler *NotificationEventHandler) handleUserDeletionScheduled(event entity.UserDeletionScheduledEvent) error {
+	link := handler.links.RestoreAccount + "?token=" + url.QueryEscape(event.Token)
+
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.Email},
+		Subject:   "Your Account Will Be Deleted - Buon18",
+		HtmlBody: fmt.Sprintf(`<p>Hello %s,</p> <p>Your account was deleted and will be removed for good on %s.</p> <p>Until then you can <a href="%s">click here to restore it</a>.</p>`,
+			html.EscapeString(event.Name), event.PurgeAt.UTC().Format(time.RFC1123), html.EscapeString(link)),
+	})
+	return nil
+}
+
+func (handler *NotificationEventHandler) handleDataExportReady(event entity.DataExportReadyEvent) error {
+	link := handler.links.DataExport + "?token=" + url.QueryEscape(event.Token)
+
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.Email},
+		Subject:   "Your Data Export Is Ready - Buon18",
+		HtmlBody: fmt.Sprintf(`<p>Hello %s,</p> <p>The copy of your data you asked for is ready. <a href="%s">Click here to download it</a>. The link expires at %s.</p> <p>If you did not ask for it, change your password right away.</p>`,
+			html.EscapeString(event.Name), html.EscapeString(link), event.Exp.UTC().Format(time.RFC1123)),
+	})
+	return nil
+}
+
+func (handler *NotificationEventHandler) handleMagicLink(event entity.MagicLinkEvent) error {
+	link := handler.links.MagicLink + "?token=" + url.QueryEscape(event.Token)
+
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.Email},
+		Subject:   "Sign In - Buon18",
+		HtmlBody: fmt.Sprintf(`<p><a href="%s">Click here to sign in</a>.</p> <p>The link can be used once and expires at %s. If you did not ask for it, you can ignore this email.</p>`,
+			html.EscapeString(link), event.Exp.UTC().Format(time.RFC1123)),
+	})
+	return nil
+}
+
+func (handler *NotificationEventHandler) handleRecoveryCodesRegenerated(event entity.RecoveryCodesRegeneratedEvent) error {
+	handler.notificationService.SendEmail(&command.SendEmailCommand{
+		FromEmail: handler.fromEmail,
+		ToEmails:  []string{event.Email},
+		Subject:   "Security Alert - Buon18",
+		HtmlBody: fmt.Sprintf(`<p>Hello %s,</p> <p>The recovery codes of your account were regenerated on %s. Your previous codes no longer work.</p> <p>If this was not you, change your password right away.</p>`,
+			html.EscapeString(event.Name), event.Time.UTC().Format(time.RFC1123)),
+	})
+	return nil
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type ApiKeyService interface {
+	CreateApiKey(createApiKeyCommand *command.CreateApiKeyCommand) (*command.CreateApiKeyCommandResult, error)
+	ListApiKeys(listApiKeysCommand *command.ListApiKeysCommand) (*command.ListApiKeysCommandResult, error)
+	RevokeApiKey(revokeApiKeyCommand *command.RevokeApiKeyCommand) error
+}
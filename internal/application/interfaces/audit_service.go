@@ -0,0 +1,8 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type AuditService interface {
+	ListAuditEvents(listAuditEventsCommand *command.ListAuditEventsCommand) (*command.ListAuditEventsCommandResult, error)
+	ListUserAuditEvents(listUserAuditEventsCommand *command.ListUserAuditEventsCommand) (*command.ListUserAuditEventsCommandResult, error)
+}
@@ -10,4 +10,11 @@ type AuthenticateService interface {
 	ResetPassword(resetPasswordCommand *command.ResetPasswordCommand) (*command.ResetPasswordCommandResult, error)
 	ResetPasswordWithToken(resetPasswordWithTokenCommand *command.ResetPasswordWithTokenCommand) (*command.ResetPasswordWithTokenCommandResult, error)
 	DeleteProfile(deleteProfileCommand *command.DeleteProfileCommand) error
+	RestoreAccount(restoreAccountCommand *command.RestoreAccountCommand) (*command.RestoreAccountCommandResult, error)
+	RequestMagicLink(requestMagicLinkCommand *command.RequestMagicLinkCommand) error
+	ConfirmEmailChange(confirmEmailChangeCommand *command.ConfirmEmailChangeCommand) (*command.ConfirmEmailChangeCommandResult, error)
+	RevertEmailChange(revertEmailChangeCommand *command.RevertEmailChangeCommand) (*command.RevertEmailChangeCommandResult, error)
+	VerifyEmail(verifyEmailCommand *command.VerifyEmailCommand) (*command.VerifyEmailCommandResult, error)
+	ResendVerifyEmail(resendVerifyEmailCommand *command.ResendVerifyEmailCommand) error
+	LoginWithMagicLink(loginWithMagicLinkCommand *command.LoginWithMagicLinkCommand) (*command.LoginWithMagicLinkCommandResult, error)
 }
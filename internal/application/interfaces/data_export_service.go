@@ -0,0 +1,10 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type DataExportService interface {
+	RequestDataExport(requestDataExportCommand *command.RequestDataExportCommand) (*command.RequestDataExportCommandResult, error)
+	GetDataExport(getDataExportCommand *command.GetDataExportCommand) (*command.GetDataExportCommandResult, error)
+	ProcessDataExport(processDataExportCommand *command.ProcessDataExportCommand) error
+	DownloadDataExport(downloadDataExportCommand *command.DownloadDataExportCommand) (*command.DownloadDataExportCommandResult, error)
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type MachineClientService interface {
+	RegisterMachineClient(registerMachineClientCommand *command.RegisterMachineClientCommand) (*command.RegisterMachineClientCommandResult, error)
+	ListMachineClients() (*command.ListMachineClientsCommandResult, error)
+}
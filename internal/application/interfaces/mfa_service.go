@@ -0,0 +1,13 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type MfaService interface {
+	EnrollTotp(enrollTotpCommand *command.EnrollTotpCommand) (*command.EnrollTotpCommandResult, error)
+	ConfirmTotp(confirmTotpCommand *command.ConfirmTotpCommand) (*command.ConfirmTotpCommandResult, error)
+	DisableTotp(disableTotpCommand *command.DisableTotpCommand) error
+	GetMfaStatus(getMfaStatusCommand *command.GetMfaStatusCommand) (*command.GetMfaStatusCommandResult, error)
+	CreateMfaChallenge(createMfaChallengeCommand *command.CreateMfaChallengeCommand) (*command.CreateMfaChallengeCommandResult, error)
+	VerifyMfaChallenge(verifyMfaChallengeCommand *command.VerifyMfaChallengeCommand) (*command.VerifyMfaChallengeCommandResult, error)
+	RegenerateRecoveryCodes(regenerateRecoveryCodesCommand *command.RegenerateRecoveryCodesCommand) (*command.RegenerateRecoveryCodesCommandResult, error)
+}
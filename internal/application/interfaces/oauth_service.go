@@ -0,0 +1,14 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type OAuthService interface {
+	RegisterClient(registerOAuthClientCommand *command.RegisterOAuthClientCommand) (*command.RegisterOAuthClientCommandResult, error)
+	ListClients(listOAuthClientsCommand *command.ListOAuthClientsCommand) (*command.ListOAuthClientsCommandResult, error)
+	Authorize(authorizeCommand *command.AuthorizeCommand) (*command.AuthorizeCommandResult, error)
+	Consent(oauthConsentCommand *command.OAuthConsentCommand) (*command.AuthorizeCommandResult, error)
+	Token(oauthTokenCommand *command.OAuthTokenCommand) (*command.OAuthTokenCommandResult, error)
+	Introspect(introspectCommand *command.IntrospectCommand) (*command.IntrospectCommandResult, error)
+	Revoke(revokeCommand *command.RevokeCommand) error
+	UserInfo(userInfoCommand *command.UserInfoCommand) (*command.UserInfoCommandResult, error)
+}
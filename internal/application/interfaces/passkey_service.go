@@ -0,0 +1,12 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type PasskeyService interface {
+	BeginPasskeyRegistration(beginPasskeyRegistrationCommand *command.BeginPasskeyRegistrationCommand) (*command.BeginPasskeyRegistrationCommandResult, error)
+	FinishPasskeyRegistration(finishPasskeyRegistrationCommand *command.FinishPasskeyRegistrationCommand) (*command.FinishPasskeyRegistrationCommandResult, error)
+	BeginPasskeyLogin() (*command.BeginPasskeyLoginCommandResult, error)
+	FinishPasskeyLogin(finishPasskeyLoginCommand *command.FinishPasskeyLoginCommand) (*command.FinishPasskeyLoginCommandResult, error)
+	ListPasskeys(listPasskeysCommand *command.ListPasskeysCommand) (*command.ListPasskeysCommandResult, error)
+	DeletePasskey(deletePasskeyCommand *command.DeletePasskeyCommand) error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type RoleService interface {
+	SeedRoles(seedRolesCommand *command.SeedRolesCommand) error
+	ListRoles() (*command.ListRolesCommandResult, error)
+	ListUserRoles(listUserRolesCommand *command.ListUserRolesCommand) (*command.ListUserRolesCommandResult, error)
+	AssignRole(assignRoleCommand *command.AssignRoleCommand) error
+	UnassignRole(unassignRoleCommand *command.UnassignRoleCommand) error
+	ResolvePermissions(resolvePermissionsCommand *command.ResolvePermissionsCommand) (*command.ResolvePermissionsCommandResult, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type SessionService interface {
+	CreateSession(createSessionCommand *command.CreateSessionCommand) (*command.CreateSessionCommandResult, error)
+	TouchSession(touchSessionCommand *command.TouchSessionCommand) error
+	GetSession(getSessionCommand *command.GetSessionCommand) (*command.GetSessionCommandResult, error)
+	ListSessions(listSessionsCommand *command.ListSessionsCommand) (*command.ListSessionsCommandResult, error)
+	RevokeSession(revokeSessionCommand *command.RevokeSessionCommand) error
+	RevokeOtherSessions(revokeOtherSessionsCommand *command.RevokeOtherSessionsCommand) error
+}
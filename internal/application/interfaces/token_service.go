@@ -0,0 +1,14 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type TokenService interface {
+	IssueToken(issueTokenCommand *command.IssueTokenCommand) (*command.IssueTokenCommandResult, error)
+	IssueMachineToken(issueMachineTokenCommand *command.IssueMachineTokenCommand) (*command.IssueMachineTokenCommandResult, error)
+	RefreshToken(refreshTokenCommand *command.RefreshTokenCommand) (*command.RefreshTokenCommandResult, error)
+	ValidateAccessToken(validateAccessTokenCommand *command.ValidateAccessTokenCommand) (*command.ValidateAccessTokenCommandResult, error)
+	ValidateApiKey(validateApiKeyCommand *command.ValidateApiKeyCommand) (*command.ValidateApiKeyCommandResult, error)
+	IntrospectToken(introspectTokenCommand *command.IntrospectTokenCommand) (*command.IntrospectTokenCommandResult, error)
+	RevokeToken(revokeTokenCommand *command.RevokeTokenCommand) error
+	Logout(logoutCommand *command.LogoutCommand) error
+}
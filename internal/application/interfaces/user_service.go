@@ -0,0 +1,14 @@
+package interfaces
+
+import "github/imfropz/go-ddd/internal/application/command"
+
+type UserService interface {
+	GetUser(getUserCommand *command.GetUserCommand) (*command.GetUserCommandResult, error)
+	ListUsers(listUsersCommand *command.ListUsersCommand) (*command.ListUsersCommandResult, error)
+	UpdateUser(updateUserCommand *command.UpdateUserCommand) (*command.UpdateUserCommandResult, error)
+	ForcePasswordReset(forcePasswordResetCommand *command.ForcePasswordResetCommand) error
+	DisableUser(disableUserCommand *command.DisableUserCommand) (*command.DisableUserCommandResult, error)
+	EnableUser(enableUserCommand *command.EnableUserCommand) (*command.EnableUserCommandResult, error)
+	DeleteUser(deleteUserCommand *command.DeleteUserCommand) error
+	RestoreUser(restoreUserCommand *command.RestoreUserCommand) (*command.RestoreUserCommandResult, error)
+}
@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewApiKeyResultFromEntity(key *entity.ApiKey) *common.ApiKeyResult {
+	if key == nil {
+		return nil
+	}
+
+	return &common.ApiKeyResult{
+		Id:         key.Id,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
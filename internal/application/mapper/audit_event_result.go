@@ -0,0 +1,25 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewAuditEventResultFromEntity(event *entity.AuditEvent) *common.AuditEventResult {
+	if event == nil {
+		return nil
+	}
+
+	return &common.AuditEventResult{
+		Id:        event.Id,
+		CreatedAt: event.CreatedAt,
+		UserId:    event.UserId,
+		ActorId:   event.ActorId,
+		Email:     event.Email,
+		Action:    event.Action,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		IpAddress: event.IpAddress,
+		UserAgent: event.UserAgent,
+	}
+}
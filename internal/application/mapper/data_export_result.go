@@ -0,0 +1,20 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewDataExportResultFromEntity(export *entity.DataExport) *common.DataExportResult {
+	if export == nil {
+		return nil
+	}
+
+	return &common.DataExportResult{
+		Id:          export.Id,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
@@ -0,0 +1,21 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewMachineClientResultFromEntity(client *entity.MachineClient) *common.MachineClientResult {
+	if client == nil {
+		return nil
+	}
+
+	return &common.MachineClientResult{
+		Id:        client.Id,
+		ClientId:  client.ClientId,
+		Name:      client.Name,
+		Scopes:    client.Scopes,
+		CreatedAt: client.CreatedAt,
+		UpdatedAt: client.UpdatedAt,
+	}
+}
@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewOAuthClientResultFromEntity(client *entity.OAuthClient) *common.OAuthClientResult {
+	if client == nil {
+		return nil
+	}
+
+	return &common.OAuthClientResult{
+		Id:           client.Id,
+		ClientId:     client.ClientId,
+		Name:         client.Name,
+		RedirectUris: client.RedirectUris,
+		Scopes:       client.Scopes,
+		Public:       client.IsPublic(),
+		CreatedAt:    client.CreatedAt,
+		UpdatedAt:    client.UpdatedAt,
+	}
+}
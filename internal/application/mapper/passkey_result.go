@@ -0,0 +1,21 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewPasskeyResultFromEntity(passkey *entity.Passkey) *common.PasskeyResult {
+	if passkey == nil {
+		return nil
+	}
+
+	return &common.PasskeyResult{
+		Id:           passkey.Id,
+		Name:         passkey.Name,
+		CredentialId: passkey.CredentialId,
+		Transports:   passkey.Transports,
+		CreatedAt:    passkey.CreatedAt,
+		LastUsedAt:   passkey.LastUsedAt,
+	}
+}
@@ -0,0 +1,22 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewRoleResultFromEntity(role *entity.Role) *common.RoleResult {
+	if role == nil {
+		return nil
+	}
+
+	return &common.RoleResult{
+		Id:          role.Id,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		RequireMfa:  role.RequireMfa,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
@@ -0,0 +1,22 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+func NewSessionResultFromEntity(session *entity.Session) *common.SessionResult {
+	if session == nil {
+		return nil
+	}
+
+	return &common.SessionResult{
+		Id:         session.Id,
+		UserId:     session.UserId,
+		Device:     session.Device,
+		IpAddress:  session.IpAddress,
+		UserAgent:  session.UserAgent,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+	}
+}
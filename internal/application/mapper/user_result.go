@@ -11,12 +11,16 @@ func NewUserResultFromEntity(user *entity.User) *common.UserResult {
 	}
 
 	return &common.UserResult{
-		Id:        user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		Password:  user.Password,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		Id:            user.Id,
+		Name:          user.Name,
+		Email:         user.Email,
+		Password:      user.Password,
+		EmailVerified: user.EmailVerified,
+		VerifiedAt:    user.VerifiedAt,
+		DisabledAt:    user.DisabledAt,
+		DeletedAt:     user.DeletedAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}
 }
 
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"time"
+)
+
+type ApiKeyService struct {
+	apiKeyRepository repository.ApiKeyRepository
+}
+
+func NewApiKeyService(apiKeyRepository repository.ApiKeyRepository) *ApiKeyService {
+	return &ApiKeyService{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+// CreateApiKey generates a new key for the user. The plain key is only
+// returned here; just its hash is stored.
+func (service *ApiKeyService) CreateApiKey(createApiKeyCommand *command.CreateApiKeyCommand) (*command.CreateApiKeyCommandResult, error) {
+	if createApiKeyCommand.ExpiresAt != nil && !createApiKeyCommand.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	secret, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	key := entity.API_KEY_PREFIX + secret
+
+	apiKeyEntity := entity.NewApiKey(createApiKeyCommand.UserId, createApiKeyCommand.Name, key, createApiKeyCommand.Scopes, createApiKeyCommand.ExpiresAt)
+
+	validatedApiKey, err := entity.NewValidatedApiKey(apiKeyEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := service.apiKeyRepository.Create(validatedApiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.CreateApiKeyCommandResult{
+		Result: mapper.NewApiKeyResultFromEntity(apiKey),
+		Key:    key,
+	}
+
+	return &result, nil
+}
+
+func (service *ApiKeyService) ListApiKeys(listApiKeysCommand *command.ListApiKeysCommand) (*command.ListApiKeysCommandResult, error) {
+	apiKeys, err := service.apiKeyRepository.FindAllByUserId(listApiKeysCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*common.ApiKeyResult, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		results[i] = mapper.NewApiKeyResultFromEntity(apiKey)
+	}
+
+	result := command.ListApiKeysCommandResult{
+		Result: results,
+	}
+
+	return &result, nil
+}
+
+func (service *ApiKeyService) RevokeApiKey(revokeApiKeyCommand *command.RevokeApiKeyCommand) error {
+	apiKey, err := service.apiKeyRepository.FindById(revokeApiKeyCommand.Id)
+	if err != nil || apiKey.UserId != revokeApiKeyCommand.UserId {
+		return entity.ErrApiKeyNotFound
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil
+	}
+	apiKey.Revoke()
+
+	validatedApiKey, err := entity.NewValidatedApiKey(apiKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.apiKeyRepository.Update(validatedApiKey)
+	return err
+}
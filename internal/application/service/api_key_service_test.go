@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"errors"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApiKeyService_CreateApiKey(t *testing.T) {
+	userId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+
+		var storedHash string
+		mockApiKeyRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(validatedApiKey *entity.ValidatedApiKey) (*entity.ApiKey, error) {
+				storedHash = validatedApiKey.KeyHash
+				return &validatedApiKey.ApiKey, nil
+			})
+
+		service := service.NewApiKeyService(mockApiKeyRepo)
+
+		result, err := service.CreateApiKey(&command.CreateApiKeyCommand{
+			UserId: userId,
+			Name:   "ci",
+			Scopes: []string{entity.SCOPE_API_READ},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.Key, entity.API_KEY_PREFIX))
+		assert.Equal(t, entity.HashApiKey(result.Key), storedHash)
+		assert.Equal(t, result.Key[:entity.API_KEY_PREFIX_LENGTH], result.Result.Prefix)
+		assert.Equal(t, "ci", result.Result.Name)
+	})
+
+	t.Run("failure: unsupported scope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+
+		service := service.NewApiKeyService(mockApiKeyRepo)
+
+		result, err := service.CreateApiKey(&command.CreateApiKeyCommand{
+			UserId: userId,
+			Name:   "ci",
+			Scopes: []string{"admin"},
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: expiry in the past", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+
+		service := service.NewApiKeyService(mockApiKeyRepo)
+
+		expiresAt := time.Now().Add(-time.Hour)
+		result, err := service.CreateApiKey(&command.CreateApiKeyCommand{
+			UserId:    userId,
+			Name:      "ci",
+			ExpiresAt: &expiresAt,
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestApiKeyService_RevokeApiKey(t *testing.T) {
+	userId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+
+		apiKey := entity.NewApiKey(userId, "ci", entity.API_KEY_PREFIX+"secret", nil, nil)
+
+		mockApiKeyRepo.EXPECT().FindById(apiKey.Id).Return(apiKey, nil)
+		mockApiKeyRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(validatedApiKey *entity.ValidatedApiKey) (*entity.ApiKey, error) {
+				assert.NotNil(t, validatedApiKey.RevokedAt)
+				return &validatedApiKey.ApiKey, nil
+			})
+
+		service := service.NewApiKeyService(mockApiKeyRepo)
+
+		err := service.RevokeApiKey(&command.RevokeApiKeyCommand{
+			UserId: userId,
+			Id:     apiKey.Id,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: key of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+
+		apiKey := entity.NewApiKey(uuid.New(), "ci", entity.API_KEY_PREFIX+"secret", nil, nil)
+
+		mockApiKeyRepo.EXPECT().FindById(apiKey.Id).Return(apiKey, nil)
+
+		service := service.NewApiKeyService(mockApiKeyRepo)
+
+		err := service.RevokeApiKey(&command.RevokeApiKeyCommand{
+			UserId: userId,
+			Id:     apiKey.Id,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrApiKeyNotFound)
+	})
+
+	t.Run("failure: unknown key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+
+		id := uuid.New()
+		mockApiKeyRepo.EXPECT().FindById(id).Return(nil, errors.New("record not found"))
+
+		service := service.NewApiKeyService(mockApiKeyRepo)
+
+		err := service.RevokeApiKey(&command.RevokeApiKeyCommand{
+			UserId: userId,
+			Id:     id,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrApiKeyNotFound)
+	})
+}
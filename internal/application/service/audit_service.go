@@ -0,0 +1,88 @@
+package service
+
+import (
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/repository"
+)
+
+// AuditService reads the security audit log written by AuthenticateService.
+type AuditService struct {
+	auditEventRepository repository.AuditEventRepository
+}
+
+func NewAuditService(auditEventRepository repository.AuditEventRepository) *AuditService {
+	return &AuditService{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+// ListAuditEvents returns a page of at most criteria.MAX_LIMIT events across
+// every user.
+func (service *AuditService) ListAuditEvents(listAuditEventsCommand *command.ListAuditEventsCommand) (*command.ListAuditEventsCommandResult, error) {
+	auditEventCriteria := criteria.AuditEventCriteria{}
+	if listAuditEventsCommand.Criteria != nil {
+		auditEventCriteria = *listAuditEventsCommand.Criteria
+	}
+
+	events, err := service.listAuditEvents(&auditEventCriteria)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.ListAuditEventsCommandResult{
+		Result: events,
+	}
+
+	return &result, nil
+}
+
+// ListUserAuditEvents is the security history of a single user. Any user id in
+// the criteria is replaced by the one of the command.
+func (service *AuditService) ListUserAuditEvents(listUserAuditEventsCommand *command.ListUserAuditEventsCommand) (*command.ListUserAuditEventsCommandResult, error) {
+	auditEventCriteria := criteria.AuditEventCriteria{}
+	if listUserAuditEventsCommand.Criteria != nil {
+		auditEventCriteria = *listUserAuditEventsCommand.Criteria
+	}
+	auditEventCriteria.UserId = &listUserAuditEventsCommand.UserId
+
+	events, err := service.listAuditEvents(&auditEventCriteria)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.ListUserAuditEventsCommandResult{
+		Result: events,
+	}
+
+	return &result, nil
+}
+
+func (service *AuditService) listAuditEvents(auditEventCriteria *criteria.AuditEventCriteria) (*common.AuditEventListResult, error) {
+	if auditEventCriteria.Limit <= 0 {
+		auditEventCriteria.Limit = criteria.DEFAULT_LIMIT
+	}
+	auditEventCriteria.Limit = min(auditEventCriteria.Limit, criteria.MAX_LIMIT)
+
+	events, err := service.auditEventRepository.FindAll(auditEventCriteria)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := service.auditEventRepository.Count(auditEventCriteria)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*common.AuditEventResult, len(events))
+	for i, event := range events {
+		results[i] = mapper.NewAuditEventResultFromEntity(event)
+	}
+
+	return &common.AuditEventListResult{
+		Events: results,
+		Total:  total,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"errors"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditService_ListAuditEvents(t *testing.T) {
+	events := []*entity.AuditEvent{
+		entity.NewAuditEvent(entity.AUDIT_ACTION_LOGIN, "john@example.com", "127.0.0.1", "test-agent"),
+		entity.NewAuditEvent(entity.AUDIT_ACTION_REGISTER, "jane@example.com", "127.0.0.1", "test-agent"),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockAuditRepo.EXPECT().
+			FindAll(gomock.Any()).
+			DoAndReturn(func(auditEventCriteria *criteria.AuditEventCriteria) ([]*entity.AuditEvent, error) {
+				assert.Equal(t, criteria.MAX_LIMIT, auditEventCriteria.Limit)
+				return events, nil
+			})
+		mockAuditRepo.EXPECT().Count(gomock.Any()).Return(int64(2), nil)
+
+		service := service.NewAuditService(mockAuditRepo)
+
+		result, err := service.ListAuditEvents(&command.ListAuditEventsCommand{
+			Criteria: &criteria.AuditEventCriteria{Limit: 1000},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Result.Events, 2)
+		assert.Equal(t, int64(2), result.Result.Total)
+		assert.Equal(t, entity.AUDIT_ACTION_LOGIN, result.Result.Events[0].Action)
+	})
+
+	t.Run("failure: repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockAuditRepo.EXPECT().FindAll(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		service := service.NewAuditService(mockAuditRepo)
+
+		_, err := service.ListAuditEvents(&command.ListAuditEventsCommand{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAuditService_ListUserAuditEvents(t *testing.T) {
+	t.Run("success: scoped to the user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		userId := uuid.New()
+		otherUserId := uuid.New()
+		mockAuditRepo.EXPECT().
+			FindAll(gomock.Any()).
+			DoAndReturn(func(auditEventCriteria *criteria.AuditEventCriteria) ([]*entity.AuditEvent, error) {
+				assert.Equal(t, userId, *auditEventCriteria.UserId)
+				assert.Equal(t, criteria.DEFAULT_LIMIT, auditEventCriteria.Limit)
+				return []*entity.AuditEvent{}, nil
+			})
+		mockAuditRepo.EXPECT().Count(gomock.Any()).Return(int64(0), nil)
+
+		service := service.NewAuditService(mockAuditRepo)
+
+		result, err := service.ListUserAuditEvents(&command.ListUserAuditEventsCommand{
+			UserId:   userId,
+			Criteria: &criteria.AuditEventCriteria{UserId: &otherUserId},
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Result.Events)
+	})
+}
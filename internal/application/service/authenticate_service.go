@@ -10,28 +10,47 @@ import (
 	"github/imfropz/go-ddd/internal/domain/entity"
 	"github/imfropz/go-ddd/internal/domain/event"
 	"github/imfropz/go-ddd/internal/domain/repository"
+	"log/slog"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type AuthenticateService struct {
-	eventPublisher   event.EventPublisher
-	valkeyRepository repository.ValkeyRepository
-	userRepository   repository.UserRepository
+	eventPublisher          event.EventPublisher
+	valkeyRepository        repository.ValkeyRepository
+	userRepository          repository.UserRepository
+	auditEventRepository    repository.AuditEventRepository
+	emailVerificationPolicy string
+	lockout                 *loginLockout
+	deletionGracePeriod     time.Duration
 }
 
-func NewAuthenticateService(eventPublisher event.EventPublisher, valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository) *AuthenticateService {
+func NewAuthenticateService(eventPublisher event.EventPublisher, valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository, auditEventRepository repository.AuditEventRepository, emailVerificationPolicy string, lockoutPolicy entity.LoginLockoutPolicy, deletionGracePeriod time.Duration) *AuthenticateService {
 	return &AuthenticateService{
-		eventPublisher:   eventPublisher,
-		valkeyRepository: valkeyRepository,
-		userRepository:   userRepository,
+		eventPublisher:          eventPublisher,
+		valkeyRepository:        valkeyRepository,
+		userRepository:          userRepository,
+		auditEventRepository:    auditEventRepository,
+		emailVerificationPolicy: emailVerificationPolicy,
+		lockout: &loginLockout{
+			eventPublisher:   eventPublisher,
+			valkeyRepository: valkeyRepository,
+			policy:           lockoutPolicy,
+		},
+		deletionGracePeriod: deletionGracePeriod,
 	}
 }
 
-func (service *AuthenticateService) Profile(profileCommand *command.ProfileCommand) (*command.ProfileCommandResult, error) {
+func (service *AuthenticateService) Profile(profileCommand *command.ProfileCommand) (_ *command.ProfileCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_PROFILE_VIEWED, profileCommand.Email, profileCommand.IpAddress, profileCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
 	user, err := service.userRepository.FindByEmail(profileCommand.Email)
 	if err != nil {
 		return nil, err
 	}
+	audit.ActedBy(user)
 
 	result := command.ProfileCommandResult{
 		Result: mapper.NewUserResultFromEntity(user),
@@ -40,7 +59,10 @@ func (service *AuthenticateService) Profile(profileCommand *command.ProfileComma
 	return &result, nil
 }
 
-func (service *AuthenticateService) Register(registerCommand *command.RegisterCommand) (*command.RegisterCommandResult, error) {
+func (service *AuthenticateService) Register(registerCommand *command.RegisterCommand) (_ *command.RegisterCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_REGISTER, registerCommand.Email, registerCommand.IpAddress, registerCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
 	userEntity := entity.NewUser(registerCommand.Name, registerCommand.Email, registerCommand.Password)
 
 	validatedUser, err := entity.NewValidatedUser(userEntity)
@@ -57,24 +79,54 @@ func (service *AuthenticateService) Register(registerCommand *command.RegisterCo
 	if err != nil {
 		return nil, err
 	}
+	audit.ActedBy(user)
+
+	publishLifecycleEvent(service.eventPublisher, entity.USER_REGISTERED, user.Id, entity.NewUserRegisteredEvent(user))
+
+	// The account exists either way; the user can ask for another email.
+	service.publishVerifyEmail(user)
 
 	result := command.RegisterCommandResult{
-		Result: mapper.NewUserResultFromEntity(user),
+		Result:               mapper.NewUserResultFromEntity(user),
+		VerificationRequired: service.emailVerificationPolicy == entity.EMAIL_VERIFICATION_BLOCK,
 	}
 
 	return &result, nil
 }
 
-func (service *AuthenticateService) Login(loginCommand *command.LoginCommand) (*command.LoginCommandResult, error) {
+// Login fails with a *entity.LoginLockedError while the email or the client ip
+// is locked out after too many failed attempts. The failures are cleared by
+// MfaService once the second factor, if any, is through as well.
+func (service *AuthenticateService) Login(loginCommand *command.LoginCommand) (_ *command.LoginCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_LOGIN, loginCommand.Email, loginCommand.IpAddress, loginCommand.UserAgent)
+	defer func() { recordLoginAudit(service.auditEventRepository, audit, err) }()
+
+	counters := service.lockout.passwordCounters(loginCommand.Email, loginCommand.IpAddress)
+	if err := service.lockout.check(counters); err != nil {
+		return nil, err
+	}
+
 	user, err := service.userRepository.FindByEmail(loginCommand.Email)
 	if err != nil {
-		return nil, err
+		audit.Reason = "unknown email"
+		return nil, service.lockout.recordFailure(counters, nil, loginCommand.IpAddress, err)
 	}
+	audit.ForUser(user)
 
 	if err := util.ComparePwd(loginCommand.Password, user.Password); err != nil {
+		audit.Reason = "invalid password"
+		return nil, service.lockout.recordFailure(counters, user, loginCommand.IpAddress, err)
+	}
+	audit.ActedBy(user)
+
+	if err := user.SignInError(); err != nil {
 		return nil, err
 	}
 
+	if !user.EmailVerified && service.emailVerificationPolicy == entity.EMAIL_VERIFICATION_BLOCK {
+		return nil, entity.ErrEmailNotVerified
+	}
+
 	result := command.LoginCommandResult{
 		Result: mapper.NewUserResultFromEntity(user),
 	}
@@ -82,17 +134,37 @@ func (service *AuthenticateService) Login(loginCommand *command.LoginCommand) (*
 	return &result, nil
 }
 
-func (service *AuthenticateService) UpdateProfile(updateProfileCommand *command.UpdateProfileCommand) (*command.UpdateProfileCommandResult, error) {
+// UpdateProfile never changes the email directly. A new email is kept pending
+// until it is confirmed from the new address, see ConfirmEmailChange.
+func (service *AuthenticateService) UpdateProfile(updateProfileCommand *command.UpdateProfileCommand) (_ *command.UpdateProfileCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_PROFILE_UPDATED, "", updateProfileCommand.IpAddress, updateProfileCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
 	old_user, err := service.userRepository.FindById(updateProfileCommand.Id)
 	if err != nil {
 		return nil, err
 	}
+	audit.ActedBy(old_user)
 
-	user := entity.NewUser(updateProfileCommand.Name, updateProfileCommand.Email, old_user.Password)
+	user := entity.NewUser(updateProfileCommand.Name, old_user.Email, old_user.Password)
 	user.Id = old_user.Id
+	user.EmailVerified = old_user.EmailVerified
+	user.VerifiedAt = old_user.VerifiedAt
+
+	pendingEmail := ""
+	if updateProfileCommand.Email != old_user.Email {
+		if updateProfileCommand.Email == "" {
+			return nil, errors.New("email must not be empty")
+		}
+		if _, err := service.userRepository.FindByEmail(updateProfileCommand.Email); err == nil {
+			return nil, entity.ErrEmailTaken
+		}
+		pendingEmail = updateProfileCommand.Email
+	}
 
 	if updateProfileCommand.CurrentPassword != "" {
 		if err := util.ComparePwd(updateProfileCommand.CurrentPassword, old_user.Password); err != nil {
+			audit.Reason = "invalid password"
 			return nil, err
 		}
 
@@ -116,36 +188,143 @@ func (service *AuthenticateService) UpdateProfile(updateProfileCommand *command.
 		return nil, err
 	}
 
+	if updateProfileCommand.CurrentPassword != "" {
+		if err := service.revokeOtherSessions(user.Id, updateProfileCommand.SessionId); err != nil {
+			return nil, err
+		}
+	}
+
+	publishLifecycleEvent(service.eventPublisher, entity.USER_PROFILE_UPDATED, user.Id, entity.NewUserProfileUpdatedEvent(user))
+	if updateProfileCommand.CurrentPassword != "" {
+		publishLifecycleEvent(service.eventPublisher, entity.USER_PASSWORD_CHANGED, user.Id, entity.NewUserPasswordChangedEvent(user, entity.PASSWORD_CHANGE_METHOD_UPDATE))
+	}
+
+	if pendingEmail != "" {
+		if err := service.requestEmailChange(user, pendingEmail); err != nil {
+			return nil, err
+		}
+	}
+
 	result := command.UpdateProfileCommandResult{
-		Result: mapper.NewUserResultFromEntity(user),
+		Result:       mapper.NewUserResultFromEntity(user),
+		PendingEmail: pendingEmail,
 	}
 
 	return &result, nil
 }
 
-func (service *AuthenticateService) ResetPassword(resetPasswordCommand *command.ResetPasswordCommand) (*command.ResetPasswordCommandResult, error) {
-	user, err := service.userRepository.FindByEmail(resetPasswordCommand.Email)
+// ConfirmEmailChange swaps in the pending email. Every session is revoked, so
+// the account has to be signed in again with the new email.
+func (service *AuthenticateService) ConfirmEmailChange(confirmEmailChangeCommand *command.ConfirmEmailChangeCommand) (_ *command.ConfirmEmailChangeCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_EMAIL_CHANGE_CONFIRMED, "", confirmEmailChangeCommand.IpAddress, confirmEmailChangeCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
+	claims, err := util.ValidateConfirmEmailChangeToken(confirmEmailChangeCommand.Token)
 	if err != nil {
+		return nil, entity.ErrEmailChangeInvalid
+	}
+	audit.Email = claims.OldEmail
+
+	ctx := context.Background()
+	key := emailChangeKey(claims.Id)
+	pendingEmail, err := service.valkeyRepository.Get(ctx, key)
+	if err != nil || pendingEmail != claims.NewEmail {
+		return nil, entity.ErrEmailChangeInvalid
+	}
+
+	user, err := service.userRepository.FindById(claims.Id)
+	if err != nil || user.Email != claims.OldEmail {
+		return nil, entity.ErrEmailChangeInvalid
+	}
+	audit.ActedBy(user)
+
+	if _, err := service.userRepository.FindByEmail(claims.NewEmail); err == nil {
+		return nil, entity.ErrEmailTaken
+	}
+
+	if err := user.UpdateEmail(claims.NewEmail); err != nil {
 		return nil, err
 	}
+	user.VerifyEmail()
 
-	token, err := util.GenerateResetPasswordToken(util.ResetPasswordTokenClaims{
-		Email: user.Email,
-	})
+	user, err = service.saveEmailChange(user)
 	if err != nil {
 		return nil, err
 	}
 
-	_1_hour := 60 * 60
-	service.valkeyRepository.Set(context.Background(), fmt.Sprintf("user:%s:%s", user.Id, entity.RESET_PASSWORD), token, _1_hour)
+	service.valkeyRepository.Delete(ctx, key)
+	publishLifecycleEvent(service.eventPublisher, entity.USER_EMAIL_CHANGED, user.Id, entity.NewUserEmailChangedEvent(user, claims.OldEmail))
 
-	event := entity.ResetPasswordEvent{
-		Email: user.Email,
-		Token: token,
-		Exp:   time.Now().Add(time.Hour * time.Duration(1)),
+	result := command.ConfirmEmailChangeCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+// RevertEmailChange is the "this wasn't me" link sent to the old address. It
+// cancels a pending change or restores the old email once it was confirmed,
+// and revokes every session in both cases.
+func (service *AuthenticateService) RevertEmailChange(revertEmailChangeCommand *command.RevertEmailChangeCommand) (_ *command.RevertEmailChangeCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_EMAIL_CHANGE_REVERTED, "", revertEmailChangeCommand.IpAddress, revertEmailChangeCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
+	claims, err := util.ValidateRevertEmailChangeToken(revertEmailChangeCommand.Token)
+	if err != nil {
+		return nil, entity.ErrEmailChangeInvalid
+	}
+	audit.Email = claims.OldEmail
+
+	user, err := service.userRepository.FindById(claims.Id)
+	if err != nil {
+		return nil, entity.ErrEmailChangeInvalid
+	}
+	audit.ActedBy(user)
+
+	ctx := context.Background()
+	switch user.Email {
+	case claims.OldEmail:
+		service.valkeyRepository.Delete(ctx, emailChangeKey(user.Id))
+		if err := service.revokeAllSessions(user.Id); err != nil {
+			return nil, err
+		}
+	case claims.NewEmail:
+		if err := user.UpdateEmail(claims.OldEmail); err != nil {
+			return nil, err
+		}
+		user.VerifyEmail()
+
+		user, err = service.saveEmailChange(user)
+		if err != nil {
+			return nil, err
+		}
+
+		publishLifecycleEvent(service.eventPublisher, entity.USER_EMAIL_CHANGED, user.Id, entity.NewUserEmailChangedEvent(user, claims.NewEmail))
+	default:
+		return nil, entity.ErrEmailChangeInvalid
+	}
+
+	result := command.RevertEmailChangeCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+func (service *AuthenticateService) ResetPassword(resetPasswordCommand *command.ResetPasswordCommand) (_ *command.ResetPasswordCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_PASSWORD_RESET_REQUESTED, resetPasswordCommand.Email, resetPasswordCommand.IpAddress, resetPasswordCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
+	user, err := service.userRepository.FindByEmail(resetPasswordCommand.Email)
+	if err != nil {
+		audit.Reason = "unknown email"
+		return nil, err
 	}
+	audit.ForUser(user)
 
-	service.eventPublisher.PublishWithKey(entity.RESET_PASSWORD, []byte(user.Email), event)
+	if err := publishResetPassword(service.valkeyRepository, service.eventPublisher, user); err != nil {
+		return nil, err
+	}
 
 	result := command.ResetPasswordCommandResult{
 		Result: mapper.NewUserResultFromEntity(user),
@@ -154,16 +333,21 @@ func (service *AuthenticateService) ResetPassword(resetPasswordCommand *command.
 	return &result, nil
 }
 
-func (service *AuthenticateService) ResetPasswordWithToken(resetPasswordWithTokenCommand *command.ResetPasswordWithTokenCommand) (*command.ResetPasswordWithTokenCommandResult, error) {
+func (service *AuthenticateService) ResetPasswordWithToken(resetPasswordWithTokenCommand *command.ResetPasswordWithTokenCommand) (_ *command.ResetPasswordWithTokenCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_PASSWORD_RESET, "", resetPasswordWithTokenCommand.IpAddress, resetPasswordWithTokenCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
 	claims, err := util.ValidateResetPasswordToken(resetPasswordWithTokenCommand.Token)
 	if err != nil {
 		return nil, err
 	}
+	audit.Email = claims.Email
 
 	old_user, err := service.userRepository.FindByEmail(claims.Email)
 	if err != nil {
 		return nil, err
 	}
+	audit.ForUser(old_user)
 
 	cacheToken, err := service.valkeyRepository.Get(context.Background(), fmt.Sprintf("user:%s:%s", old_user.Id, entity.RESET_PASSWORD))
 	if err != nil {
@@ -173,6 +357,7 @@ func (service *AuthenticateService) ResetPasswordWithToken(resetPasswordWithToke
 	if cacheToken != resetPasswordWithTokenCommand.Token {
 		return nil, errors.New("invalid token")
 	}
+	audit.ActedBy(old_user)
 
 	user := entity.NewUser(old_user.Name, old_user.Email, resetPasswordWithTokenCommand.NewPassword)
 	user.Id = old_user.Id
@@ -193,6 +378,10 @@ func (service *AuthenticateService) ResetPasswordWithToken(resetPasswordWithToke
 	}
 
 	service.valkeyRepository.Delete(context.Background(), fmt.Sprintf("user:%s:%s", old_user.Id, entity.RESET_PASSWORD))
+	if err := service.revokeAllSessions(old_user.Id); err != nil {
+		return nil, err
+	}
+	publishLifecycleEvent(service.eventPublisher, entity.USER_PASSWORD_CHANGED, user.Id, entity.NewUserPasswordChangedEvent(user, entity.PASSWORD_CHANGE_METHOD_RESET))
 
 	result := command.ResetPasswordWithTokenCommandResult{
 		Result: mapper.NewUserResultFromEntity(user),
@@ -201,15 +390,418 @@ func (service *AuthenticateService) ResetPasswordWithToken(resetPasswordWithToke
 	return &result, nil
 }
 
-func (service *AuthenticateService) DeleteProfile(deleteProfileCommand *command.DeleteProfileCommand) error {
+func (service *AuthenticateService) VerifyEmail(verifyEmailCommand *command.VerifyEmailCommand) (_ *command.VerifyEmailCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_EMAIL_VERIFIED, "", verifyEmailCommand.IpAddress, verifyEmailCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
+	claims, err := util.ValidateVerifyEmailToken(verifyEmailCommand.Token)
+	if err != nil {
+		return nil, entity.ErrEmailVerificationInvalid
+	}
+	audit.Email = claims.Email
+
+	user, err := service.userRepository.FindById(claims.Id)
+	if err != nil || user.Email != claims.Email {
+		return nil, entity.ErrEmailVerificationInvalid
+	}
+	audit.ActedBy(user)
+
+	if !user.EmailVerified {
+		if user, err = service.markEmailVerified(user); err != nil {
+			return nil, err
+		}
+	}
+
+	result := command.VerifyEmailCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+// ResendVerifyEmail sends a new verification email, at most once per cooldown.
+func (service *AuthenticateService) ResendVerifyEmail(resendVerifyEmailCommand *command.ResendVerifyEmailCommand) (err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_EMAIL_VERIFICATION_RESENT, resendVerifyEmailCommand.Email, resendVerifyEmailCommand.IpAddress, resendVerifyEmailCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
+	user, err := service.userRepository.FindByEmail(resendVerifyEmailCommand.Email)
+	if err != nil {
+		audit.Reason = "unknown email"
+		return err
+	}
+	audit.ForUser(user)
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	ctx := context.Background()
+	key := fmt.Sprintf("user:%s:%s", user.Id, entity.VERIFY_EMAIL)
+	count, err := service.valkeyRepository.Increment(ctx, key)
+	if err != nil {
+		return err
+	}
+	if count == 1 {
+		service.valkeyRepository.Expire(ctx, key, int(entity.EMAIL_VERIFICATION_RESEND_COOLDOWN.Seconds()))
+	}
+	if count > 1 {
+		return errors.New("verification email was sent recently")
+	}
+
+	return service.publishVerifyEmail(user)
+}
+
+// RequestMagicLink emails a link to sign in without a password. Each link
+// works once, but requesting a new one does not invalidate the previous ones.
+func (service *AuthenticateService) RequestMagicLink(requestMagicLinkCommand *command.RequestMagicLinkCommand) (err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_MAGIC_LINK_REQUESTED, requestMagicLinkCommand.Email, requestMagicLinkCommand.IpAddress, requestMagicLinkCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
+	user, err := service.userRepository.FindByEmail(requestMagicLinkCommand.Email)
+	if err != nil {
+		audit.Reason = "unknown email"
+		return err
+	}
+	audit.ForUser(user)
+
+	tokenId := uuid.NewString()
+	token, err := util.GenerateMagicLinkToken(util.MagicLinkTokenClaims{
+		Email:   user.Email,
+		TokenId: tokenId,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Stored as 1 and consumed by incrementing it, which gives 2 exactly once.
+	ttl := int(util.MAGIC_LINK_TOKEN_DURATION.Seconds())
+	if err := service.valkeyRepository.Set(context.Background(), magicLinkKey(tokenId), 1, ttl); err != nil {
+		return err
+	}
+
+	event := entity.MagicLinkEvent{
+		Email: user.Email,
+		Token: token,
+		Exp:   time.Now().Add(util.MAGIC_LINK_TOKEN_DURATION),
+	}
+
+	service.eventPublisher.PublishWithKey(entity.MAGIC_LINK, []byte(user.Email), event)
+
+	return nil
+}
+
+func (service *AuthenticateService) LoginWithMagicLink(loginWithMagicLinkCommand *command.LoginWithMagicLinkCommand) (_ *command.LoginWithMagicLinkCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_LOGIN_MAGIC_LINK, "", loginWithMagicLinkCommand.IpAddress, loginWithMagicLinkCommand.UserAgent)
+	defer func() { recordLoginAudit(service.auditEventRepository, audit, err) }()
+
+	claims, err := util.ValidateMagicLinkToken(loginWithMagicLinkCommand.Token)
+	if err != nil {
+		return nil, entity.ErrMagicLinkInvalid
+	}
+	audit.Email = claims.Email
+
+	ctx := context.Background()
+	key := magicLinkKey(claims.TokenId)
+	count, err := service.valkeyRepository.Increment(ctx, key)
+	service.valkeyRepository.Delete(ctx, key)
+	if err != nil || count != 2 {
+		return nil, entity.ErrMagicLinkInvalid
+	}
+
+	user, err := service.userRepository.FindByEmail(claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	audit.ActedBy(user)
+	if err := user.SignInError(); err != nil {
+		return nil, err
+	}
+
+	// Following the link proves the user owns the address.
+	if !user.EmailVerified {
+		if user, err = service.markEmailVerified(user); err != nil {
+			return nil, err
+		}
+	}
+
+	result := command.LoginWithMagicLinkCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+func (service *AuthenticateService) DeleteProfile(deleteProfileCommand *command.DeleteProfileCommand) (err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_ACCOUNT_DELETED, deleteProfileCommand.Email, deleteProfileCommand.IpAddress, deleteProfileCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
 	user, err := service.userRepository.FindByEmail(deleteProfileCommand.Email)
 	if err != nil {
 		return err
 	}
+	audit.ForUser(user)
 
 	if err := util.ComparePwd(deleteProfileCommand.Password, user.Password); err != nil {
+		audit.Reason = "invalid password"
 		return err
 	}
+	audit.ActedBy(user)
+
+	return scheduleUserDeletion(service.userRepository, service.valkeyRepository, service.eventPublisher, user, service.deletionGracePeriod)
+}
+
+// RestoreAccount undoes a deletion from the link emailed when it was
+// scheduled. The user signs in again afterwards.
+func (service *AuthenticateService) RestoreAccount(restoreAccountCommand *command.RestoreAccountCommand) (_ *command.RestoreAccountCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_ACCOUNT_RESTORED, "", restoreAccountCommand.IpAddress, restoreAccountCommand.UserAgent)
+	defer func() { service.recordAudit(audit, err) }()
+
+	claims, err := util.ValidateRestoreAccountToken(restoreAccountCommand.Token)
+	if err != nil {
+		return nil, entity.ErrRestoreAccountInvalid
+	}
+
+	user, err := service.userRepository.FindById(claims.Id)
+	if err != nil || !user.IsDeleted() || user.DeletedAt.Unix() != claims.DeletedAt.Unix() {
+		return nil, entity.ErrRestoreAccountInvalid
+	}
+	audit.ActedBy(user)
+
+	user.Restore()
+
+	validatedUser, err := entity.NewValidatedUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = service.userRepository.UpdateStatus(validatedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.RestoreAccountCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+// recordAudit appends the event to the audit log. Failing to write it is only
+// logged, it never fails the action being audited.
+func (service *AuthenticateService) recordAudit(audit *entity.AuditEvent, err error) {
+	recordAuditEvent(service.auditEventRepository, audit, err)
+}
+
+// recordLoginAudit only records failed logins. A successful one is recorded
+// by TokenService.IssueToken once the tokens are issued, so a login waiting on
+// its second factor does not show up as signed in.
+func recordLoginAudit(auditEventRepository repository.AuditEventRepository, audit *entity.AuditEvent, err error) {
+	if err != nil {
+		recordAuditEvent(auditEventRepository, audit, err)
+	}
+}
+
+// recordAuditEvent is shared with the other services that sign users in.
+func recordAuditEvent(auditEventRepository repository.AuditEventRepository, audit *entity.AuditEvent, err error) {
+	audit.Conclude(err)
+
+	validatedAudit, err := entity.NewValidatedAuditEvent(audit)
+	if err == nil {
+		_, err = auditEventRepository.Create(validatedAudit)
+	}
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on recording audit event %s: %v", audit.Action, err))
+	}
+}
+
+func (service *AuthenticateService) publishVerifyEmail(user *entity.User) error {
+	token, err := util.GenerateVerifyEmailToken(util.VerifyEmailTokenClaims{
+		Id:    user.Id,
+		Email: user.Email,
+	})
+	if err != nil {
+		return err
+	}
+
+	event := entity.VerifyEmailEvent{
+		Email: user.Email,
+		Name:  user.Name,
+		Token: token,
+		Exp:   time.Now().Add(util.VERIFY_EMAIL_TOKEN_DURATION),
+	}
+
+	return service.eventPublisher.PublishWithKey(entity.VERIFY_EMAIL, []byte(user.Email), event)
+}
+
+func (service *AuthenticateService) markEmailVerified(user *entity.User) (*entity.User, error) {
+	user.VerifyEmail()
+
+	validatedUser, err := entity.NewValidatedUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.userRepository.Update(validatedUser)
+}
+
+func (service *AuthenticateService) requestEmailChange(user *entity.User, newEmail string) error {
+	claims := util.EmailChangeTokenClaims{
+		Id:       user.Id,
+		OldEmail: user.Email,
+		NewEmail: newEmail,
+	}
+
+	confirmToken, err := util.GenerateConfirmEmailChangeToken(claims)
+	if err != nil {
+		return err
+	}
+
+	revertToken, err := util.GenerateRevertEmailChangeToken(claims)
+	if err != nil {
+		return err
+	}
+
+	ttl := int(util.CONFIRM_EMAIL_CHANGE_TOKEN_DURATION.Seconds())
+	if err := service.valkeyRepository.Set(context.Background(), emailChangeKey(user.Id), newEmail, ttl); err != nil {
+		return err
+	}
+
+	event := entity.EmailChangeEvent{
+		Name:         user.Name,
+		OldEmail:     user.Email,
+		NewEmail:     newEmail,
+		ConfirmToken: confirmToken,
+		RevertToken:  revertToken,
+		Exp:          time.Now().Add(util.CONFIRM_EMAIL_CHANGE_TOKEN_DURATION),
+	}
+
+	return service.eventPublisher.PublishWithKey(entity.EMAIL_CHANGE, []byte(user.Email), event)
+}
+
+func (service *AuthenticateService) saveEmailChange(user *entity.User) (*entity.User, error) {
+	validatedUser, err := entity.NewValidatedUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = service.userRepository.Update(validatedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.revokeAllSessions(user.Id); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// publishResetPassword stores a reset password token for the user and emails
+// it. It is shared with the admin forced reset in UserService.
+func publishResetPassword(valkeyRepository repository.ValkeyRepository, eventPublisher event.EventPublisher, user *entity.User) error {
+	token, err := util.GenerateResetPasswordToken(util.ResetPasswordTokenClaims{
+		Email: user.Email,
+	})
+	if err != nil {
+		return err
+	}
+
+	_1_hour := 60 * 60
+	valkeyRepository.Set(context.Background(), fmt.Sprintf("user:%s:%s", user.Id, entity.RESET_PASSWORD), token, _1_hour)
+
+	event := entity.ResetPasswordEvent{
+		Email: user.Email,
+		Token: token,
+		Exp:   time.Now().Add(time.Hour * time.Duration(1)),
+	}
+
+	eventPublisher.PublishWithKey(entity.RESET_PASSWORD, []byte(user.Email), event)
+
+	return nil
+}
+
+// publishLifecycleEvent announces a change that is already saved, so failing to
+// publish it is only logged. It is shared with the purge in UserService.
+func publishLifecycleEvent(eventPublisher event.EventPublisher, topic string, userId uuid.UUID, lifecycleEvent interface{}) {
+	if err := eventPublisher.PublishWithKey(topic, []byte(userId.String()), lifecycleEvent); err != nil {
+		slog.Error(fmt.Sprintf("error on publishing %s event: %v", topic, err))
+	}
+}
+
+// scheduleUserDeletion marks the user deleted, signs them out and emails a link
+// to restore the account before it is purged. It is shared with the admin
+// deletion in UserService.
+func scheduleUserDeletion(userRepository repository.UserRepository, valkeyRepository repository.ValkeyRepository, eventPublisher event.EventPublisher, user *entity.User, gracePeriod time.Duration) error {
+	if !user.IsDeleted() {
+		user.MarkDeleted()
+
+		validatedUser, err := entity.NewValidatedUser(user)
+		if err != nil {
+			return err
+		}
+
+		if user, err = userRepository.UpdateStatus(validatedUser); err != nil {
+			return err
+		}
+	}
+
+	if err := valkeyRepository.Delete(context.Background(), sessionKey(user.Id)); err != nil {
+		return err
+	}
+
+	purgeAt := user.DeletedAt.Add(gracePeriod)
+	token, err := util.GenerateRestoreAccountToken(util.RestoreAccountTokenClaims{
+		Id:        user.Id,
+		DeletedAt: *user.DeletedAt,
+		ExpiresAt: purgeAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	event := entity.UserDeletionScheduledEvent{
+		Email:   user.Email,
+		Name:    user.Name,
+		Token:   token,
+		PurgeAt: purgeAt,
+	}
+
+	return eventPublisher.PublishWithKey(entity.USER_DELETION_SCHEDULED, []byte(user.Email), event)
+}
+
+// revokeAllSessions drops every session of the user, which also invalidates
+// their refresh tokens and the access tokens still in flight.
+func (service *AuthenticateService) revokeAllSessions(userId uuid.UUID) error {
+	return service.valkeyRepository.Delete(context.Background(), sessionKey(userId))
+}
+
+// revokeOtherSessions drops every session of the user but the current one.
+func (service *AuthenticateService) revokeOtherSessions(userId uuid.UUID, currentSessionId uuid.UUID) error {
+	key := sessionKey(userId)
+
+	values, err := service.valkeyRepository.HGetAll(context.Background(), key)
+	if err != nil {
+		return err
+	}
+
+	sessionIds := make([]string, 0, len(values))
+	for sessionId := range values {
+		if sessionId != currentSessionId.String() {
+			sessionIds = append(sessionIds, sessionId)
+		}
+	}
+
+	if len(sessionIds) == 0 {
+		return nil
+	}
+
+	return service.valkeyRepository.HDel(context.Background(), key, sessionIds...)
+}
+
+func emailChangeKey(userId uuid.UUID) string {
+	return fmt.Sprintf("user:%s:%s", userId, entity.EMAIL_CHANGE)
+}
 
-	return service.userRepository.Delete(user.Id)
+func magicLinkKey(tokenId string) string {
+	return fmt.Sprintf("%s:%s", entity.MAGIC_LINK, tokenId)
 }
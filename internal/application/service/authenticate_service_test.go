@@ -8,9 +8,15 @@ import (
 	"github/imfropz/go-ddd/internal/application/service"
 	"github/imfropz/go-ddd/internal/domain/entity"
 	"github/imfropz/go-ddd/internal/domain/mocks"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/crypto/bcrypt"
@@ -28,10 +34,12 @@ func TestAuthenticationService_Profile(t *testing.T) {
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
 		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
 		result, err := service.Profile(&command.ProfileCommand{
 			Email: user.Email,
@@ -50,10 +58,12 @@ func TestAuthenticationService_Profile(t *testing.T) {
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
 		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(nil, errors.New("user not found"))
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
 		_, err := service.Profile(&command.ProfileCommand{
 			Email: user.Email,
@@ -73,10 +83,14 @@ func TestAuthenticationService_Register(t *testing.T) {
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
 		mockUserRepo.EXPECT().Create(gomock.Any()).Return(user, nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.VERIFY_EMAIL, []byte(user.Email), gomock.Any()).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_REGISTERED, []byte(user.Id.String()), gomock.Any()).Return(nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
 		result, err := service.Register(&command.RegisterCommand{
 			Name:     user.Name,
@@ -87,6 +101,44 @@ func TestAuthenticationService_Register(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, result.Result.Name, user.Name)
 		assert.Equal(t, result.Result.Email, user.Email)
+		assert.False(t, result.VerificationRequired)
+	})
+
+	t.Run("success: verification required", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		var event entity.VerifyEmailEvent
+		mockUserRepo.EXPECT().Create(gomock.Any()).Return(user, nil)
+		mockEventPub.EXPECT().
+			PublishWithKey(entity.VERIFY_EMAIL, []byte(user.Email), gomock.Any()).
+			DoAndReturn(func(_ string, _ []byte, e any) error {
+				event = e.(entity.VerifyEmailEvent)
+				return nil
+			})
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_REGISTERED, []byte(user.Id.String()), gomock.Any()).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.Register(&command.RegisterCommand{
+			Name:     user.Name,
+			Email:    user.Email,
+			Password: user.Password,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.VerificationRequired)
+
+		claims, err := util.ValidateVerifyEmailToken(event.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, claims.Id)
+		assert.Equal(t, user.Email, claims.Email)
 	})
 
 	t.Run("failure: empty fields", func(t *testing.T) {
@@ -96,8 +148,10 @@ func TestAuthenticationService_Register(t *testing.T) {
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
 		_, err := service.Register(&command.RegisterCommand{
 			Name:     "",
@@ -121,12 +175,14 @@ func TestAuthenticationService_Login(t *testing.T) {
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
 		mockUserRepo.EXPECT().
 			FindByEmail(user.Email).
 			Return(&dbUser, nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
 		_, err := service.Login(&command.LoginCommand{
 			Email:    user.Email,
@@ -143,12 +199,14 @@ func TestAuthenticationService_Login(t *testing.T) {
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
 		mockUserRepo.EXPECT().
 			FindByEmail(user.Email).
 			Return(&dbUser, nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
 		_, err := service.Login(&command.LoginCommand{
 			Email:    user.Email,
@@ -165,12 +223,14 @@ func TestAuthenticationService_Login(t *testing.T) {
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
 		mockUserRepo.EXPECT().
 			FindByEmail(user.Email).
 			Return(nil, errors.New("user not found"))
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
 		_, err := service.Login(&command.LoginCommand{
 			Email:    user.Email,
@@ -179,302 +239,940 @@ func TestAuthenticationService_Login(t *testing.T) {
 
 		assert.Error(t, err)
 	})
-}
-
-func TestAuthenticationService_UpdateProfile(t *testing.T) {
-	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
-	dbUser := *user
-	dbUser.Password, _ = util.HashPwd(user.Password)
 
-	t.Run("success: full", func(t *testing.T) {
+	t.Run("failure: email not verified", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
-
-		newUser := entity.NewUser("Jane Doe", "example@test.com", "password-correct")
-		dbNewUser := *newUser
-		dbNewUser.Id = user.Id
-		dbNewUser.Password, _ = util.HashPwd(dbNewUser.Password)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
 		mockUserRepo.EXPECT().
-			FindById(user.Id).
+			FindByEmail(user.Email).
 			Return(&dbUser, nil)
-		mockUserRepo.EXPECT().
-			Update(gomock.Any()).
-			Return(&dbNewUser, nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
 
-		result, err := service.UpdateProfile(&command.UpdateProfileCommand{
-			Id:              user.Id,
-			Name:            newUser.Name,
-			Email:           newUser.Email,
-			CurrentPassword: user.Password,
-			NewPassword:     newUser.Password,
+		_, err := service.Login(&command.LoginCommand{
+			Email:    user.Email,
+			Password: user.Password,
 		})
 
-		assert.NoError(t, err)
-		assert.Equal(t, user.Id, result.Result.Id)
-		assert.Equal(t, newUser.Name, result.Result.Name)
-		assert.Equal(t, newUser.Email, result.Result.Email)
-		assert.Equal(t, dbNewUser.Password, result.Result.Password)
+		assert.ErrorIs(t, err, entity.ErrEmailNotVerified)
 	})
 
-	t.Run("success: without updating password", func(t *testing.T) {
+	t.Run("failure: user disabled", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		newUser := entity.NewUser("Jane Doe", "example@test.com", user.Password)
-		dbNewUser := *newUser
-		dbNewUser.Id = user.Id
-		dbNewUser.Password = dbUser.Password
+		disabledUser := dbUser
+		disabledUser.Disable()
 
 		mockUserRepo.EXPECT().
-			FindById(user.Id).
-			Return(&dbUser, nil)
-		mockUserRepo.EXPECT().
-			Update(gomock.Any()).
-			Return(&dbNewUser, nil)
+			FindByEmail(user.Email).
+			Return(&disabledUser, nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
-		result, err := service.UpdateProfile(&command.UpdateProfileCommand{
-			Id:    user.Id,
-			Name:  newUser.Name,
-			Email: newUser.Email,
+		_, err := service.Login(&command.LoginCommand{
+			Email:    user.Email,
+			Password: user.Password,
 		})
 
-		assert.NoError(t, err)
-		assert.Equal(t, newUser.Name, result.Result.Name)
-		assert.Equal(t, newUser.Email, result.Result.Email)
-		assert.Equal(t, dbNewUser.Password, result.Result.Password)
+		assert.ErrorIs(t, err, entity.ErrUserDisabled)
 	})
 
-	t.Run("failure: invalid password", func(t *testing.T) {
+	t.Run("failure: user pending deletion", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		newUser := entity.NewUser("Jane Doe", "example@test.com", "password-correct")
+		deletedUser := dbUser
+		deletedUser.MarkDeleted()
 
 		mockUserRepo.EXPECT().
-			FindById(user.Id).
-			Return(&dbUser, nil)
+			FindByEmail(user.Email).
+			Return(&deletedUser, nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
-		_, err := service.UpdateProfile(&command.UpdateProfileCommand{
-			Id:              user.Id,
-			Name:            newUser.Name,
-			Email:           newUser.Email,
-			CurrentPassword: user.Password + "random",
-			NewPassword:     newUser.Password,
+		_, err := service.Login(&command.LoginCommand{
+			Email:    user.Email,
+			Password: user.Password,
 		})
 
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, entity.ErrUserDeleted)
 	})
+}
 
-	t.Run("failure: empty fields", func(t *testing.T) {
+func TestAuthenticationService_LoginLockout(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	dbUser := *user
+	dbUser.Password, _ = util.HashPwd(user.Password)
+
+	policy := entity.LoginLockoutPolicy{
+		EmailThreshold: 3,
+		IpThreshold:    10,
+		Window:         15 * time.Minute,
+		BaseDuration:   time.Minute,
+		MaxDuration:    time.Hour,
+	}
+	ipAddress := "203.0.113.7"
+	emailFailuresKey := "login-failures:email:" + user.Email
+	emailLockoutKey := entity.LOGIN_LOCKED + ":email:" + user.Email
+	ipFailuresKey := "login-failures:ip:" + ipAddress
+	ipLockoutKey := entity.LOGIN_LOCKED + ":ip:" + ipAddress
+
+	t.Run("success: leaves email failures to the second factor", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		newUser := entity.NewUser("", "", "")
-
-		mockUserRepo.EXPECT().
-			FindById(user.Id).
-			Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return("", errors.New("nil message"))
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), ipLockoutKey).Return("", errors.New("nil message"))
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, policy, time.Hour)
 
-		_, err := service.UpdateProfile(&command.UpdateProfileCommand{
-			Id:              user.Id,
-			Name:            newUser.Name,
-			Email:           newUser.Email,
-			CurrentPassword: user.Password,
-			NewPassword:     newUser.Password,
+		result, err := service.Login(&command.LoginCommand{
+			Email:     user.Email,
+			Password:  user.Password,
+			IpAddress: ipAddress,
 		})
 
-		assert.Error(t, err)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Email, result.Result.Email)
 	})
-}
-
-func TestAuthenticationService_ResetPassword(t *testing.T) {
-	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
-	dbUser := *user
-	dbUser.Password, _ = util.HashPwd(user.Password)
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("failure: locked out", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		mockUserRepo.EXPECT().
-			FindByEmail(user.Email).
-			Return(&dbUser, nil)
-		mockValkeyRepo.EXPECT().
-			Set(gomock.Any(), fmt.Sprintf("user:%s:%s", user.Id, entity.RESET_PASSWORD), gomock.Any(), 60*60). // ttl = 1 hour
-			Return(nil)
-		mockEventPub.EXPECT().PublishWithKey(entity.RESET_PASSWORD, []byte(user.Email), gomock.Any()).
-			Return(nil)
+		until := time.Now().Add(5 * time.Minute).Unix()
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return(strconv.FormatInt(until, 10), nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, policy, time.Hour)
 
-		_, err := service.ResetPassword(&command.ResetPasswordCommand{
-			Email: user.Email,
+		_, err := service.Login(&command.LoginCommand{
+			Email:     user.Email,
+			Password:  user.Password,
+			IpAddress: ipAddress,
 		})
 
-		assert.NoError(t, err)
+		var locked *entity.LoginLockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.ErrorIs(t, err, entity.ErrLoginLocked)
+		assert.InDelta(t, (5 * time.Minute).Seconds(), locked.RetryAfter.Seconds(), 2)
 	})
 
-	t.Run("failure: wrong email", func(t *testing.T) {
+	t.Run("failure: locks out on threshold", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		wrongEmail := "example@test.com"
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return("", errors.New("nil message"))
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), ipLockoutKey).Return("", errors.New("nil message"))
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), emailFailuresKey).Return(int64(3), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), emailFailuresKey, 16*60).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), emailLockoutKey, gomock.Any(), 60).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.LOGIN_LOCKED, []byte(user.Email), gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), ipFailuresKey).Return(int64(3), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), ipFailuresKey, 15*60).Return(nil)
 
-		mockUserRepo.EXPECT().
-			FindByEmail(wrongEmail).
-			Return(nil, errors.New("user not found"))
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, policy, time.Hour)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		_, err := service.Login(&command.LoginCommand{
+			Email:     user.Email,
+			Password:  user.Password + "random",
+			IpAddress: ipAddress,
+		})
 
-		_, err := service.ResetPassword(&command.ResetPasswordCommand{
-			Email: wrongEmail,
+		var locked *entity.LoginLockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.Equal(t, time.Minute, locked.RetryAfter)
+	})
+
+	t.Run("failure: below threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return("", errors.New("nil message"))
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), ipLockoutKey).Return("", errors.New("nil message"))
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(nil, errors.New("record not found"))
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), emailFailuresKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), emailFailuresKey, 15*60).Return(nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), ipFailuresKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), ipFailuresKey, 15*60).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, policy, time.Hour)
+
+		_, err := service.Login(&command.LoginCommand{
+			Email:     user.Email,
+			Password:  user.Password,
+			IpAddress: ipAddress,
 		})
 
 		assert.Error(t, err)
-		assert.Contains(t, "user not found", err.Error())
+		assert.NotErrorIs(t, err, entity.ErrLoginLocked)
+	})
+
+	t.Run("failure: spoofed forwarded for keeps the ip counter", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		spoofed := []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"}
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return("", errors.New("nil message")).Times(len(spoofed))
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), ipLockoutKey).Return("", errors.New("nil message")).Times(len(spoofed))
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(nil, errors.New("record not found")).Times(len(spoofed))
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), emailFailuresKey).Return(int64(1), nil).Times(len(spoofed))
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), emailFailuresKey, 15*60).Return(nil).Times(len(spoofed))
+		for i := range spoofed {
+			mockValkeyRepo.EXPECT().Increment(gomock.Any(), ipFailuresKey).Return(int64(i+1), nil)
+		}
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), ipFailuresKey, 15*60).Return(nil).Times(len(spoofed))
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, policy, time.Hour)
+
+		for _, forwardedFor := range spoofed {
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/login", nil)
+			r.RemoteAddr = ipAddress + ":1234"
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+
+			loginRequest := request.LoginRequest{Email: user.Email, Password: user.Password}
+			_, err := service.Login(loginRequest.ToLoginCommand(request.NewClientInfo(r, nil)))
+
+			assert.Error(t, err)
+		}
 	})
 }
 
-func TestAuthenticationService_ResetPasswordWithToken(t *testing.T) {
+func TestLoginLockoutPolicy_LockoutDuration(t *testing.T) {
+	policy := entity.LoginLockoutPolicy{
+		BaseDuration: time.Minute,
+		MaxDuration:  10 * time.Minute,
+	}
+
+	assert.Equal(t, time.Duration(0), policy.LockoutDuration(4, 5))
+	assert.Equal(t, time.Minute, policy.LockoutDuration(5, 5))
+	assert.Equal(t, 2*time.Minute, policy.LockoutDuration(6, 5))
+	assert.Equal(t, 8*time.Minute, policy.LockoutDuration(8, 5))
+	assert.Equal(t, 10*time.Minute, policy.LockoutDuration(50, 5))
+	assert.Equal(t, time.Duration(0), policy.LockoutDuration(50, 0))
+}
+
+func TestAuthenticationService_LoginAudit(t *testing.T) {
 	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
 	dbUser := *user
 	dbUser.Password, _ = util.HashPwd(user.Password)
 
-	validToken, _ := util.GenerateResetPasswordToken(util.ResetPasswordTokenClaims{
-		Email: user.Email,
-	})
-	invalidToken := "invalid-token"
+	t.Run("success: left to the token issuance", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-	validPasswrd := "valid-password"
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
 
-	resetPasswordTokenKey := fmt.Sprintf("user:%s:%s", user.Id, entity.RESET_PASSWORD)
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
 
-	t.Run("success", func(t *testing.T) {
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.Login(&command.LoginCommand{
+			Email:     user.Email,
+			Password:  user.Password,
+			IpAddress: "127.0.0.1",
+			UserAgent: "test-agent",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: invalid password is recorded without an actor", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
 
-		newUser := entity.NewUser(user.Name, user.Email, validPasswrd)
-		dbNewUser := *newUser
-		dbNewUser.Password, _ = util.HashPwd(newUser.Password)
-
-		mockUserRepo.EXPECT().
-			FindByEmail(user.Email).
-			Return(&dbUser, nil)
-		mockUserRepo.EXPECT().
-			Update(gomock.Any()).
-			Return(&dbNewUser, nil)
-		mockValkeyRepo.EXPECT().Get(gomock.Any(), resetPasswordTokenKey).Return(validToken, nil)
-		mockValkeyRepo.EXPECT().Delete(gomock.Any(), resetPasswordTokenKey).Return(nil)
-
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
+		mockAuditRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(event *entity.ValidatedAuditEvent) (*entity.AuditEvent, error) {
+				assert.Equal(t, entity.AUDIT_OUTCOME_FAILURE, event.Outcome)
+				assert.Equal(t, "invalid password", event.Reason)
+				assert.Equal(t, user.Id, *event.UserId)
+				assert.Nil(t, event.ActorId)
+				return &event.AuditEvent, nil
+			})
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
-		result, err := service.ResetPasswordWithToken(&command.ResetPasswordWithTokenCommand{
-			Token:       validToken,
-			NewPassword: validPasswrd,
+		_, err := service.Login(&command.LoginCommand{
+			Email:    user.Email,
+			Password: user.Password + "random",
 		})
 
-		assert.NoError(t, err)
-		assert.Equal(t, dbNewUser.Password, result.Result.Password)
+		assert.Error(t, err)
 	})
 
-	t.Run("failure: invalid token", func(t *testing.T) {
+	t.Run("failure: unknown email is recorded without a user", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		mockUserRepo.EXPECT().FindByEmail("unknown@example.com").Return(nil, errors.New("user not found"))
+		mockAuditRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(event *entity.ValidatedAuditEvent) (*entity.AuditEvent, error) {
+				assert.Equal(t, entity.AUDIT_OUTCOME_FAILURE, event.Outcome)
+				assert.Equal(t, "unknown email", event.Reason)
+				assert.Equal(t, "unknown@example.com", event.Email)
+				assert.Nil(t, event.UserId)
+				return &event.AuditEvent, nil
+			})
 
-		_, err := service.ResetPasswordWithToken(&command.ResetPasswordWithTokenCommand{
-			Token:       invalidToken,
-			NewPassword: validPasswrd,
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.Login(&command.LoginCommand{
+			Email:    "unknown@example.com",
+			Password: user.Password,
 		})
 
 		assert.Error(t, err)
-		assert.True(t, errors.Is(err, jwt.ErrTokenMalformed))
 	})
 }
 
-func TestAuthenticationService_DeleteProfile(t *testing.T) {
+func TestAuthenticationService_UpdateProfile(t *testing.T) {
 	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
 	dbUser := *user
 	dbUser.Password, _ = util.HashPwd(user.Password)
+	emailChangeKey := fmt.Sprintf("user:%s:%s", user.Id, entity.EMAIL_CHANGE)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+	currentSessionId := uuid.New()
+	otherSessionId := uuid.New()
 
-	invalidPassword := "invalid-password"
-
-	t.Run("success", func(t *testing.T) {
+	t.Run("success: full", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
-		mockUserRepo.EXPECT().Delete(user.Id).Return(nil)
+		newUser := entity.NewUser("Jane Doe", "example@test.com", "password-correct")
+
+		mockUserRepo.EXPECT().
+			FindById(user.Id).
+			Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			FindByEmail(newUser.Email).
+			Return(nil, errors.New("record not found"))
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(u *entity.ValidatedUser) (*entity.User, error) {
+				return &u.User, nil
+			})
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), emailChangeKey, newUser.Email, int(util.CONFIRM_EMAIL_CHANGE_TOKEN_DURATION.Seconds())).
+			Return(nil)
+		mockEventPub.EXPECT().
+			PublishWithKey(entity.EMAIL_CHANGE, []byte(user.Email), gomock.Any()).
+			Return(nil)
+		mockValkeyRepo.EXPECT().
+			HGetAll(gomock.Any(), sessionKey).
+			Return(map[string]string{currentSessionId.String(): "{}", otherSessionId.String(): "{}"}, nil)
+		mockValkeyRepo.EXPECT().
+			HDel(gomock.Any(), sessionKey, otherSessionId.String()).
+			Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_PROFILE_UPDATED, []byte(user.Id.String()), gomock.Any()).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_PASSWORD_CHANGED, []byte(user.Id.String()), gomock.Any()).Return(nil)
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
 
-		err := service.DeleteProfile(&command.DeleteProfileCommand{
-			Email:    user.Email,
-			Password: user.Password,
+		result, err := service.UpdateProfile(&command.UpdateProfileCommand{
+			Id:              user.Id,
+			SessionId:       currentSessionId,
+			Name:            newUser.Name,
+			Email:           newUser.Email,
+			CurrentPassword: user.Password,
+			NewPassword:     newUser.Password,
 		})
 
 		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Id)
+		assert.Equal(t, newUser.Name, result.Result.Name)
+		assert.Equal(t, user.Email, result.Result.Email)
+		assert.Equal(t, newUser.Email, result.PendingEmail)
+		assert.NoError(t, util.ComparePwd(newUser.Password, result.Result.Password))
 	})
 
-	t.Run("failure: invalid password", func(t *testing.T) {
+	t.Run("success: without updating password", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockUserRepo := mocks.NewMockUserRepository(ctrl)
 		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
 		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
 
-		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
+		newUser := entity.NewUser("Jane Doe", user.Email, user.Password)
+		dbNewUser := *newUser
+		dbNewUser.Id = user.Id
+		dbNewUser.Password = dbUser.Password
 
-		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo)
+		mockUserRepo.EXPECT().
+			FindById(user.Id).
+			Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			Return(&dbNewUser, nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_PROFILE_UPDATED, []byte(user.Id.String()), gomock.Any()).Return(nil)
 
-		err := service.DeleteProfile(&command.DeleteProfileCommand{
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.UpdateProfile(&command.UpdateProfileCommand{
+			Id:    user.Id,
+			Name:  newUser.Name,
+			Email: newUser.Email,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newUser.Name, result.Result.Name)
+		assert.Equal(t, newUser.Email, result.Result.Email)
+		assert.Empty(t, result.PendingEmail)
+		assert.Equal(t, dbNewUser.Password, result.Result.Password)
+	})
+
+	t.Run("failure: email taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		otherUser := entity.NewUser("Jane Doe", "example@test.com", "password-correct")
+
+		mockUserRepo.EXPECT().
+			FindById(user.Id).
+			Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			FindByEmail(otherUser.Email).
+			Return(otherUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.UpdateProfile(&command.UpdateProfileCommand{
+			Id:    user.Id,
+			Name:  user.Name,
+			Email: otherUser.Email,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailTaken)
+	})
+
+	t.Run("failure: invalid password", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		newUser := entity.NewUser("Jane Doe", user.Email, "password-correct")
+
+		mockUserRepo.EXPECT().
+			FindById(user.Id).
+			Return(&dbUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.UpdateProfile(&command.UpdateProfileCommand{
+			Id:              user.Id,
+			Name:            newUser.Name,
+			Email:           newUser.Email,
+			CurrentPassword: user.Password + "random",
+			NewPassword:     newUser.Password,
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: empty fields", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		newUser := entity.NewUser("", "", "")
+
+		mockUserRepo.EXPECT().
+			FindById(user.Id).
+			Return(&dbUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.UpdateProfile(&command.UpdateProfileCommand{
+			Id:              user.Id,
+			Name:            newUser.Name,
+			Email:           newUser.Email,
+			CurrentPassword: user.Password,
+			NewPassword:     newUser.Password,
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthenticationService_ConfirmEmailChange(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	newEmail := "example@test.com"
+	claims := util.EmailChangeTokenClaims{
+		Id:       user.Id,
+		OldEmail: user.Email,
+		NewEmail: newEmail,
+	}
+	token, _ := util.GenerateConfirmEmailChangeToken(claims)
+	emailChangeKey := fmt.Sprintf("user:%s:%s", user.Id, entity.EMAIL_CHANGE)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailChangeKey).Return(newEmail, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().FindByEmail(newEmail).Return(nil, errors.New("record not found"))
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(u *entity.ValidatedUser) (*entity.User, error) {
+				return &u.User, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), sessionKey).Return(nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), emailChangeKey).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_EMAIL_CHANGED, []byte(user.Id.String()), gomock.Any()).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.ConfirmEmailChange(&command.ConfirmEmailChangeCommand{
+			Token: token,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newEmail, result.Result.Email)
+		assert.True(t, result.Result.EmailVerified)
+	})
+
+	t.Run("failure: no pending change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailChangeKey).Return("", errors.New("nil message"))
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.ConfirmEmailChange(&command.ConfirmEmailChangeCommand{
+			Token: token,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailChangeInvalid)
+	})
+
+	t.Run("failure: email taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailChangeKey).Return(newEmail, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().FindByEmail(newEmail).Return(entity.NewUser("Jane Doe", newEmail, "password"), nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.ConfirmEmailChange(&command.ConfirmEmailChangeCommand{
+			Token: token,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailTaken)
+	})
+
+	t.Run("failure: revert token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		revertToken, _ := util.GenerateRevertEmailChangeToken(claims)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.ConfirmEmailChange(&command.ConfirmEmailChangeCommand{
+			Token: revertToken,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailChangeInvalid)
+	})
+}
+
+func TestAuthenticationService_RevertEmailChange(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	newEmail := "example@test.com"
+	token, _ := util.GenerateRevertEmailChangeToken(util.EmailChangeTokenClaims{
+		Id:       user.Id,
+		OldEmail: user.Email,
+		NewEmail: newEmail,
+	})
+	emailChangeKey := fmt.Sprintf("user:%s:%s", user.Id, entity.EMAIL_CHANGE)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	t.Run("success: restores confirmed change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		dbUser.Email = newEmail
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(u *entity.ValidatedUser) (*entity.User, error) {
+				return &u.User, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), sessionKey).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_EMAIL_CHANGED, []byte(user.Id.String()), gomock.Any()).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.RevertEmailChange(&command.RevertEmailChangeCommand{
+			Token: token,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Email, result.Result.Email)
+	})
+
+	t.Run("success: cancels pending change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), emailChangeKey).Return(nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), sessionKey).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.RevertEmailChange(&command.RevertEmailChangeCommand{
+			Token: token,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Email, result.Result.Email)
+	})
+
+	t.Run("failure: email changed again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		dbUser.Email = "other@test.com"
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.RevertEmailChange(&command.RevertEmailChangeCommand{
+			Token: token,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailChangeInvalid)
+	})
+}
+
+func TestAuthenticationService_ResetPassword(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	dbUser := *user
+	dbUser.Password, _ = util.HashPwd(user.Password)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockUserRepo.EXPECT().
+			FindByEmail(user.Email).
+			Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), fmt.Sprintf("user:%s:%s", user.Id, entity.RESET_PASSWORD), gomock.Any(), 60*60). // ttl = 1 hour
+			Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.RESET_PASSWORD, []byte(user.Email), gomock.Any()).
+			Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.ResetPassword(&command.ResetPasswordCommand{
+			Email: user.Email,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: wrong email", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		wrongEmail := "example@test.com"
+
+		mockUserRepo.EXPECT().
+			FindByEmail(wrongEmail).
+			Return(nil, errors.New("user not found"))
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.ResetPassword(&command.ResetPasswordCommand{
+			Email: wrongEmail,
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, "user not found", err.Error())
+	})
+}
+
+func TestAuthenticationService_ResetPasswordWithToken(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	dbUser := *user
+	dbUser.Password, _ = util.HashPwd(user.Password)
+
+	validToken, _ := util.GenerateResetPasswordToken(util.ResetPasswordTokenClaims{
+		Email: user.Email,
+	})
+	invalidToken := "invalid-token"
+
+	validPasswrd := "valid-password"
+
+	resetPasswordTokenKey := fmt.Sprintf("user:%s:%s", user.Id, entity.RESET_PASSWORD)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		newUser := entity.NewUser(user.Name, user.Email, validPasswrd)
+		dbNewUser := *newUser
+		dbNewUser.Password, _ = util.HashPwd(newUser.Password)
+
+		mockUserRepo.EXPECT().
+			FindByEmail(user.Email).
+			Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			Return(&dbNewUser, nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), resetPasswordTokenKey).Return(validToken, nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), resetPasswordTokenKey).Return(nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_PASSWORD_CHANGED, []byte(dbNewUser.Id.String()), gomock.Any()).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.ResetPasswordWithToken(&command.ResetPasswordWithTokenCommand{
+			Token:       validToken,
+			NewPassword: validPasswrd,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, dbNewUser.Password, result.Result.Password)
+	})
+
+	t.Run("failure: invalid token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.ResetPasswordWithToken(&command.ResetPasswordWithTokenCommand{
+			Token:       invalidToken,
+			NewPassword: validPasswrd,
+		})
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, jwt.ErrTokenMalformed))
+	})
+}
+
+func TestAuthenticationService_DeleteProfile(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	dbUser := *user
+	dbUser.Password, _ = util.HashPwd(user.Password)
+
+	invalidPassword := "invalid-password"
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			UpdateStatus(gomock.Any()).
+			DoAndReturn(func(user *entity.ValidatedUser) (*entity.User, error) {
+				assert.True(t, user.IsDeleted())
+				return &user.User, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_DELETION_SCHEDULED, []byte(user.Email), gomock.Any()).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		err := service.DeleteProfile(&command.DeleteProfileCommand{
+			Email:    user.Email,
+			Password: user.Password,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: invalid password", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		err := service.DeleteProfile(&command.DeleteProfileCommand{
 			Email:    user.Email,
 			Password: invalidPassword,
 		})
@@ -483,3 +1181,394 @@ func TestAuthenticationService_DeleteProfile(t *testing.T) {
 		assert.True(t, errors.Is(err, bcrypt.ErrMismatchedHashAndPassword))
 	})
 }
+
+func TestAuthenticationService_RestoreAccount(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	user.MarkDeleted()
+	token, _ := util.GenerateRestoreAccountToken(util.RestoreAccountTokenClaims{
+		Id:        user.Id,
+		DeletedAt: *user.DeletedAt,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			UpdateStatus(gomock.Any()).
+			DoAndReturn(func(u *entity.ValidatedUser) (*entity.User, error) {
+				return &u.User, nil
+			})
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.RestoreAccount(&command.RestoreAccountCommand{
+			Token: token,
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Result.DeletedAt)
+	})
+
+	t.Run("failure: deleted again since", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		deletedAt := user.DeletedAt.Add(time.Minute)
+		dbUser.DeletedAt = &deletedAt
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.RestoreAccount(&command.RestoreAccountCommand{
+			Token: token,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrRestoreAccountInvalid)
+	})
+
+	t.Run("failure: invalid token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.RestoreAccount(&command.RestoreAccountCommand{
+			Token: "invalid-token",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrRestoreAccountInvalid)
+	})
+}
+
+func TestAuthenticationService_RequestMagicLink(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		var storedKey string
+		var event entity.MagicLinkEvent
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(user, nil)
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), gomock.Any(), 1, 15*60).
+			DoAndReturn(func(_ any, key string, _ any, _ int) error {
+				storedKey = key
+				return nil
+			})
+		mockEventPub.EXPECT().
+			PublishWithKey(entity.MAGIC_LINK, []byte(user.Email), gomock.Any()).
+			DoAndReturn(func(_ string, _ []byte, e any) error {
+				event = e.(entity.MagicLinkEvent)
+				return nil
+			})
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		err := service.RequestMagicLink(&command.RequestMagicLinkCommand{
+			Email: user.Email,
+		})
+
+		assert.NoError(t, err)
+
+		claims, err := util.ValidateMagicLinkToken(event.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Email, claims.Email)
+		assert.Equal(t, fmt.Sprintf("%s:%s", entity.MAGIC_LINK, claims.TokenId), storedKey)
+	})
+
+	t.Run("failure: unknown email", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockUserRepo.EXPECT().FindByEmail("example@test.com").Return(nil, errors.New("user not found"))
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		err := service.RequestMagicLink(&command.RequestMagicLinkCommand{
+			Email: "example@test.com",
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthenticationService_LoginWithMagicLink(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	token, _ := util.GenerateMagicLinkToken(util.MagicLinkTokenClaims{
+		Email:   user.Email,
+		TokenId: "token-id",
+	})
+	key := fmt.Sprintf("%s:%s", entity.MAGIC_LINK, "token-id")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), key).Return(int64(2), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), key).Return(nil)
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(u *entity.ValidatedUser) (*entity.User, error) {
+				assert.True(t, u.EmailVerified)
+				return &u.User, nil
+			})
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.LoginWithMagicLink(&command.LoginWithMagicLinkCommand{
+			Token: token,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Id)
+	})
+
+	t.Run("failure: link already used", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), key).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), key).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.LoginWithMagicLink(&command.LoginWithMagicLinkCommand{
+			Token: token,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMagicLinkInvalid)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: reset password token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		resetToken, _ := util.GenerateResetPasswordToken(util.ResetPasswordTokenClaims{
+			Email: user.Email,
+		})
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_OFF, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.LoginWithMagicLink(&command.LoginWithMagicLinkCommand{
+			Token: resetToken,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMagicLinkInvalid)
+	})
+}
+
+func TestAuthenticationService_VerifyEmail(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	token, _ := util.GenerateVerifyEmailToken(util.VerifyEmailTokenClaims{
+		Id:    user.Id,
+		Email: user.Email,
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(u *entity.ValidatedUser) (*entity.User, error) {
+				return &u.User, nil
+			})
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.VerifyEmail(&command.VerifyEmailCommand{
+			Token: token,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Result.EmailVerified)
+		assert.NotNil(t, result.Result.VerifiedAt)
+	})
+
+	t.Run("failure: email changed since", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		dbUser := *user
+		dbUser.Email = "new@example.com"
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
+
+		result, err := service.VerifyEmail(&command.VerifyEmailCommand{
+			Token: token,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailVerificationInvalid)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: magic link token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		magicLinkToken, _ := util.GenerateMagicLinkToken(util.MagicLinkTokenClaims{
+			Email:   user.Email,
+			TokenId: "token-id",
+		})
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
+
+		_, err := service.VerifyEmail(&command.VerifyEmailCommand{
+			Token: magicLinkToken,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailVerificationInvalid)
+	})
+}
+
+func TestAuthenticationService_ResendVerifyEmail(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	cooldownKey := fmt.Sprintf("user:%s:%s", user.Id, entity.VERIFY_EMAIL)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(user, nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), cooldownKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), cooldownKey, 60).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.VERIFY_EMAIL, []byte(user.Email), gomock.Any()).Return(nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
+
+		err := service.ResendVerifyEmail(&command.ResendVerifyEmailCommand{
+			Email: user.Email,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: cooldown", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(user, nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), cooldownKey).Return(int64(2), nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
+
+		err := service.ResendVerifyEmail(&command.ResendVerifyEmailCommand{
+			Email: user.Email,
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("success: already verified", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		verifiedUser := *user
+		verifiedUser.VerifyEmail()
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(&verifiedUser, nil)
+
+		service := service.NewAuthenticateService(mockEventPub, mockValkeyRepo, mockUserRepo, mockAuditRepo, entity.EMAIL_VERIFICATION_BLOCK, entity.LoginLockoutPolicy{}, time.Hour)
+
+		err := service.ResendVerifyEmail(&command.ResendVerifyEmailCommand{
+			Email: user.Email,
+		})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/event"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportService hands users a copy of everything held about them. The
+// export is built by a worker listening for data-export-requested events, and
+// the archive can be downloaded through an emailed link until it expires. A
+// user has one export at a time, kept in valkey for as long as the link lives.
+type DataExportService struct {
+	eventPublisher           event.EventPublisher
+	valkeyRepository         repository.ValkeyRepository
+	userRepository           repository.UserRepository
+	apiKeyRepository         repository.ApiKeyRepository
+	oauthClientRepository    repository.OAuthClientRepository
+	passkeyRepository        repository.PasskeyRepository
+	totpCredentialRepository repository.TotpCredentialRepository
+	recoveryCodeRepository   repository.RecoveryCodeRepository
+	roleRepository           repository.RoleRepository
+	auditEventRepository     repository.AuditEventRepository
+	sessionService           interfaces.SessionService
+	fileStorage              repository.FileStorage
+	linkDuration             time.Duration
+}
+
+func NewDataExportService(eventPublisher event.EventPublisher, valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository, apiKeyRepository repository.ApiKeyRepository, oauthClientRepository repository.OAuthClientRepository, passkeyRepository repository.PasskeyRepository, totpCredentialRepository repository.TotpCredentialRepository, recoveryCodeRepository repository.RecoveryCodeRepository, roleRepository repository.RoleRepository, auditEventRepository repository.AuditEventRepository, sessionService interfaces.SessionService, fileStorage repository.FileStorage, linkDuration time.Duration) *DataExportService {
+	return &DataExportService{
+		eventPublisher:           eventPublisher,
+		valkeyRepository:         valkeyRepository,
+		userRepository:           userRepository,
+		apiKeyRepository:         apiKeyRepository,
+		oauthClientRepository:    oauthClientRepository,
+		passkeyRepository:        passkeyRepository,
+		totpCredentialRepository: totpCredentialRepository,
+		recoveryCodeRepository:   recoveryCodeRepository,
+		roleRepository:           roleRepository,
+		auditEventRepository:     auditEventRepository,
+		sessionService:           sessionService,
+		fileStorage:              fileStorage,
+		linkDuration:             linkDuration,
+	}
+}
+
+// RequestDataExport starts a new export, replacing the one that is ready, if
+// any. Only one export can be in progress at a time.
+func (service *DataExportService) RequestDataExport(requestDataExportCommand *command.RequestDataExportCommand) (*command.RequestDataExportCommandResult, error) {
+	previous, err := service.findDataExport(requestDataExportCommand.UserId)
+	if err == nil {
+		if previous.IsPending() {
+			return nil, entity.ErrDataExportInProgress
+		}
+		service.fileStorage.Delete(previous.FileName())
+	} else if !errors.Is(err, entity.ErrDataExportNotFound) {
+		return nil, err
+	}
+
+	export := entity.NewDataExport(requestDataExportCommand.UserId)
+	if err := service.saveDataExport(export); err != nil {
+		return nil, err
+	}
+
+	event := entity.DataExportRequestedEvent{
+		Id:     export.Id,
+		UserId: export.UserId,
+	}
+	if err := service.eventPublisher.PublishWithKey(entity.DATA_EXPORT_REQUESTED, []byte(export.UserId.String()), event); err != nil {
+		return nil, err
+	}
+
+	result := command.RequestDataExportCommandResult{
+		Result: mapper.NewDataExportResultFromEntity(export),
+	}
+
+	return &result, nil
+}
+
+func (service *DataExportService) GetDataExport(getDataExportCommand *command.GetDataExportCommand) (*command.GetDataExportCommandResult, error) {
+	export, err := service.findDataExport(getDataExportCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.GetDataExportCommandResult{
+		Result: mapper.NewDataExportResultFromEntity(export),
+	}
+
+	return &result, nil
+}
+
+// ProcessDataExport builds the archive and emails the download link. Events
+// for an export that was replaced or has expired in the meantime are skipped.
+func (service *DataExportService) ProcessDataExport(processDataExportCommand *command.ProcessDataExportCommand) error {
+	export, err := service.findDataExport(processDataExportCommand.UserId)
+	if errors.Is(err, entity.ErrDataExportNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if export.Id != processDataExportCommand.Id || !export.IsPending() {
+		return nil
+	}
+
+	user, err := service.userRepository.FindById(export.UserId)
+	if err != nil {
+		return service.failDataExport(export, err)
+	}
+
+	archive, err := service.buildArchive(user)
+	if err != nil {
+		return service.failDataExport(export, err)
+	}
+
+	if err := service.fileStorage.Save(export.FileName(), archive); err != nil {
+		return service.failDataExport(export, err)
+	}
+
+	export.Complete(time.Now().Add(service.linkDuration))
+	if err := service.saveDataExport(export); err != nil {
+		return err
+	}
+
+	token, err := util.GenerateDataExportToken(util.DataExportTokenClaims{
+		Id:        export.Id,
+		UserId:    export.UserId,
+		ExpiresAt: *export.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	event := entity.DataExportReadyEvent{
+		Email: user.Email,
+		Name:  user.Name,
+		Token: token,
+		Exp:   *export.ExpiresAt,
+	}
+
+	return service.eventPublisher.PublishWithKey(entity.DATA_EXPORT_READY, []byte(user.Email), event)
+}
+
+// DownloadDataExport opens the archive the emailed link points to. The link
+// stops working once the export expires or a newer one is requested.
+func (service *DataExportService) DownloadDataExport(downloadDataExportCommand *command.DownloadDataExportCommand) (*command.DownloadDataExportCommandResult, error) {
+	claims, err := util.ValidateDataExportToken(downloadDataExportCommand.Token)
+	if err != nil {
+		return nil, entity.ErrDataExportInvalid
+	}
+
+	export, err := service.findDataExport(claims.UserId)
+	if err != nil || export.Id != claims.Id || !export.IsReady() {
+		return nil, entity.ErrDataExportInvalid
+	}
+
+	content, err := service.fileStorage.Open(export.FileName())
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.DownloadDataExportCommandResult{
+		Result: &common.DataExportFileResult{
+			Name:    fmt.Sprintf("data-export-%s.zip", export.CreatedAt.UTC().Format("2006-01-02")),
+			Content: content,
+		},
+	}
+
+	return &result, nil
+}
+
+// PurgeExpiredDataExports removes the archives whose link has expired.
+func (service *DataExportService) PurgeExpiredDataExports() (int, error) {
+	return service.fileStorage.DeleteBefore(time.Now().Add(-service.linkDuration))
+}
+
+// dataExportProfile is the user as exported, without the password hash.
+type dataExportProfile struct {
+	Id            uuid.UUID
+	Name          string
+	Email         string
+	EmailVerified bool
+	VerifiedAt    *time.Time
+	DisabledAt    *time.Time
+	DeletedAt     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// buildArchive writes one JSON file per kind of data into a zip archive.
+// Secrets such as password and key hashes or the TOTP secret are left out.
+func (service *DataExportService) buildArchive(user *entity.User) ([]byte, error) {
+	sessions, err := service.sessionService.ListSessions(&command.ListSessionsCommand{
+		UserId: user.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := service.roleRepository.FindByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	roleResults := make([]*common.RoleResult, len(roles))
+	for i, role := range roles {
+		roleResults[i] = mapper.NewRoleResultFromEntity(role)
+	}
+
+	apiKeys, err := service.apiKeyRepository.FindAllByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyResults := make([]*common.ApiKeyResult, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		apiKeyResults[i] = mapper.NewApiKeyResultFromEntity(apiKey)
+	}
+
+	passkeys, err := service.passkeyRepository.FindAllByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	passkeyResults := make([]*common.PasskeyResult, len(passkeys))
+	for i, passkey := range passkeys {
+		passkeyResults[i] = mapper.NewPasskeyResultFromEntity(passkey)
+	}
+
+	oauthClients, err := service.oauthClientRepository.FindAllByOwnerId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	oauthClientResults := make([]*common.OAuthClientResult, len(oauthClients))
+	for i, client := range oauthClients {
+		oauthClientResults[i] = mapper.NewOAuthClientResultFromEntity(client)
+	}
+
+	totpEnabled, err := service.totpCredentialRepository.ExistsByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodesRemaining, err := service.recoveryCodeRepository.CountUnusedByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	auditEvents, err := service.auditEventRepository.FindAll(&criteria.AuditEventCriteria{
+		UserId: &user.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	auditEventResults := make([]*common.AuditEventResult, len(auditEvents))
+	for i, auditEvent := range auditEvents {
+		auditEventResults[i] = mapper.NewAuditEventResultFromEntity(auditEvent)
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", dataExportProfile{
+			Id:            user.Id,
+			Name:          user.Name,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			VerifiedAt:    user.VerifiedAt,
+			DisabledAt:    user.DisabledAt,
+			DeletedAt:     user.DeletedAt,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+		}},
+		{"sessions.json", sessions.Result},
+		{"roles.json", roleResults},
+		{"api_keys.json", apiKeyResults},
+		{"passkeys.json", passkeyResults},
+		{"oauth_clients.json", oauthClientResults},
+		{"mfa.json", common.MfaStatusResult{
+			TotpEnabled:            totpEnabled,
+			RecoveryCodesRemaining: recoveryCodesRemaining,
+		}},
+		{"audit.json", auditEventResults},
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (service *DataExportService) failDataExport(export *entity.DataExport, cause error) error {
+	export.Fail()
+	if err := service.saveDataExport(export); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+func (service *DataExportService) findDataExport(userId uuid.UUID) (*entity.DataExport, error) {
+	value, err := service.valkeyRepository.Get(context.Background(), dataExportKey(userId))
+	if err != nil || value == "" {
+		return nil, entity.ErrDataExportNotFound
+	}
+
+	var export entity.DataExport
+	if err := json.Unmarshal([]byte(value), &export); err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+func (service *DataExportService) saveDataExport(export *entity.DataExport) error {
+	value, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	return service.valkeyRepository.Set(context.Background(), dataExportKey(export.UserId), value, int(service.linkDuration.Seconds()))
+}
+
+func dataExportKey(userId uuid.UUID) string {
+	return fmt.Sprintf("user:%s:%s", userId, entity.DATA_EXPORT)
+}
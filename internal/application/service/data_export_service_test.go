@@ -0,0 +1,279 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type dataExportMocks struct {
+	eventPub     *mocks.MockEventPublisher
+	valkeyRepo   *mocks.MockValkeyRepository
+	userRepo     *mocks.MockUserRepository
+	apiKeyRepo   *mocks.MockApiKeyRepository
+	clientRepo   *mocks.MockOAuthClientRepository
+	passkeyRepo  *mocks.MockPasskeyRepository
+	totpRepo     *mocks.MockTotpCredentialRepository
+	recoveryRepo *mocks.MockRecoveryCodeRepository
+	roleRepo     *mocks.MockRoleRepository
+	auditRepo    *mocks.MockAuditEventRepository
+	fileStorage  *mocks.MockFileStorage
+}
+
+func newDataExportService(ctrl *gomock.Controller) (*service.DataExportService, *dataExportMocks) {
+	m := dataExportMocks{
+		eventPub:     mocks.NewMockEventPublisher(ctrl),
+		valkeyRepo:   mocks.NewMockValkeyRepository(ctrl),
+		userRepo:     mocks.NewMockUserRepository(ctrl),
+		apiKeyRepo:   mocks.NewMockApiKeyRepository(ctrl),
+		clientRepo:   mocks.NewMockOAuthClientRepository(ctrl),
+		passkeyRepo:  mocks.NewMockPasskeyRepository(ctrl),
+		totpRepo:     mocks.NewMockTotpCredentialRepository(ctrl),
+		recoveryRepo: mocks.NewMockRecoveryCodeRepository(ctrl),
+		roleRepo:     mocks.NewMockRoleRepository(ctrl),
+		auditRepo:    mocks.NewMockAuditEventRepository(ctrl),
+		fileStorage:  mocks.NewMockFileStorage(ctrl),
+	}
+
+	sessionService := service.NewSessionService(m.valkeyRepo)
+
+	return service.NewDataExportService(m.eventPub, m.valkeyRepo, m.userRepo, m.apiKeyRepo, m.clientRepo, m.passkeyRepo, m.totpRepo, m.recoveryRepo, m.roleRepo, m.auditRepo, sessionService, m.fileStorage, time.Hour), &m
+}
+
+func TestDataExportService_RequestDataExport(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+	exportKey := fmt.Sprintf("user:%s:%s", user.Id, entity.DATA_EXPORT)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newDataExportService(ctrl)
+
+		previous := entity.NewDataExport(user.Id)
+		previous.Complete(time.Now().Add(time.Hour))
+		previousValue, _ := json.Marshal(previous)
+
+		m.valkeyRepo.EXPECT().Get(gomock.Any(), exportKey).Return(string(previousValue), nil)
+		m.fileStorage.EXPECT().Delete(previous.FileName()).Return(nil)
+		m.valkeyRepo.EXPECT().Set(gomock.Any(), exportKey, gomock.Any(), int(time.Hour.Seconds())).Return(nil)
+		m.eventPub.EXPECT().PublishWithKey(entity.DATA_EXPORT_REQUESTED, []byte(user.Id.String()), gomock.Any()).Return(nil)
+
+		result, err := service.RequestDataExport(&command.RequestDataExportCommand{
+			UserId: user.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, previous.Id, result.Result.Id)
+		assert.Equal(t, entity.DATA_EXPORT_STATUS_PENDING, result.Result.Status)
+	})
+
+	t.Run("failure: export in progress", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newDataExportService(ctrl)
+
+		pendingValue, _ := json.Marshal(entity.NewDataExport(user.Id))
+		m.valkeyRepo.EXPECT().Get(gomock.Any(), exportKey).Return(string(pendingValue), nil)
+
+		_, err := service.RequestDataExport(&command.RequestDataExportCommand{
+			UserId: user.Id,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrDataExportInProgress)
+	})
+}
+
+func TestDataExportService_ProcessDataExport(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+	exportKey := fmt.Sprintf("user:%s:%s", user.Id, entity.DATA_EXPORT)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	export := entity.NewDataExport(user.Id)
+	exportValue, _ := json.Marshal(export)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newDataExportService(ctrl)
+
+		session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+		sessionValue, _ := json.Marshal(session)
+
+		login := entity.NewAuditEvent(entity.AUDIT_ACTION_LOGIN, user.Email, "127.0.0.1", "test-agent")
+		login.ActedBy(user)
+		login.Conclude(nil)
+
+		var archive []byte
+		m.valkeyRepo.EXPECT().Get(gomock.Any(), exportKey).Return(string(exportValue), nil)
+		m.userRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		m.valkeyRepo.EXPECT().HGetAll(gomock.Any(), sessionKey).Return(map[string]string{session.Id.String(): string(sessionValue)}, nil)
+		m.roleRepo.EXPECT().FindByUserId(user.Id).Return([]*entity.Role{}, nil)
+		m.apiKeyRepo.EXPECT().FindAllByUserId(user.Id).Return([]*entity.ApiKey{}, nil)
+		m.passkeyRepo.EXPECT().FindAllByUserId(user.Id).Return([]*entity.Passkey{}, nil)
+		m.clientRepo.EXPECT().FindAllByOwnerId(user.Id).Return([]*entity.OAuthClient{}, nil)
+		m.totpRepo.EXPECT().ExistsByUserId(user.Id).Return(true, nil)
+		m.recoveryRepo.EXPECT().CountUnusedByUserId(user.Id).Return(8, nil)
+		m.auditRepo.EXPECT().FindAll(gomock.Any()).Return([]*entity.AuditEvent{login}, nil)
+		m.fileStorage.EXPECT().
+			Save(export.FileName(), gomock.Any()).
+			DoAndReturn(func(name string, data []byte) error {
+				archive = data
+				return nil
+			})
+		m.valkeyRepo.EXPECT().
+			Set(gomock.Any(), exportKey, gomock.Any(), int(time.Hour.Seconds())).
+			DoAndReturn(func(_ interface{}, _ string, value interface{}, _ int) error {
+				var saved entity.DataExport
+				json.Unmarshal(value.([]byte), &saved)
+				assert.True(t, saved.IsReady())
+				return nil
+			})
+		m.eventPub.EXPECT().PublishWithKey(entity.DATA_EXPORT_READY, []byte(user.Email), gomock.Any()).Return(nil)
+
+		err := service.ProcessDataExport(&command.ProcessDataExportCommand{
+			Id:     export.Id,
+			UserId: user.Id,
+		})
+
+		assert.NoError(t, err)
+
+		reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		assert.NoError(t, err)
+
+		files := map[string]string{}
+		for _, file := range reader.File {
+			content, _ := file.Open()
+			data, _ := io.ReadAll(content)
+			files[file.Name] = string(data)
+		}
+		assert.Contains(t, files["profile.json"], user.Email)
+		assert.NotContains(t, files["profile.json"], user.Password)
+		assert.Contains(t, files["sessions.json"], session.Id.String())
+		assert.Contains(t, files, "mfa.json")
+		assert.Contains(t, files["audit.json"], login.Id.String())
+	})
+
+	t.Run("success: replaced export is skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newDataExportService(ctrl)
+
+		newerValue, _ := json.Marshal(entity.NewDataExport(user.Id))
+		m.valkeyRepo.EXPECT().Get(gomock.Any(), exportKey).Return(string(newerValue), nil)
+
+		err := service.ProcessDataExport(&command.ProcessDataExportCommand{
+			Id:     export.Id,
+			UserId: user.Id,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: repository error marks the export failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newDataExportService(ctrl)
+
+		m.valkeyRepo.EXPECT().Get(gomock.Any(), exportKey).Return(string(exportValue), nil)
+		m.userRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		m.valkeyRepo.EXPECT().HGetAll(gomock.Any(), sessionKey).Return(map[string]string{}, nil)
+		m.roleRepo.EXPECT().FindByUserId(user.Id).Return(nil, errors.New("connection refused"))
+		m.valkeyRepo.EXPECT().
+			Set(gomock.Any(), exportKey, gomock.Any(), int(time.Hour.Seconds())).
+			DoAndReturn(func(_ interface{}, _ string, value interface{}, _ int) error {
+				var saved entity.DataExport
+				json.Unmarshal(value.([]byte), &saved)
+				assert.Equal(t, entity.DATA_EXPORT_STATUS_FAILED, saved.Status)
+				return nil
+			})
+
+		err := service.ProcessDataExport(&command.ProcessDataExportCommand{
+			Id:     export.Id,
+			UserId: user.Id,
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDataExportService_DownloadDataExport(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+	exportKey := fmt.Sprintf("user:%s:%s", user.Id, entity.DATA_EXPORT)
+
+	export := entity.NewDataExport(user.Id)
+	export.Complete(time.Now().Add(time.Hour))
+	exportValue, _ := json.Marshal(export)
+
+	token, _ := util.GenerateDataExportToken(util.DataExportTokenClaims{
+		Id:        export.Id,
+		UserId:    user.Id,
+		ExpiresAt: *export.ExpiresAt,
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newDataExportService(ctrl)
+
+		m.valkeyRepo.EXPECT().Get(gomock.Any(), exportKey).Return(string(exportValue), nil)
+		m.fileStorage.EXPECT().Open(export.FileName()).Return(io.NopCloser(bytes.NewReader([]byte("archive"))), nil)
+
+		result, err := service.DownloadDataExport(&command.DownloadDataExportCommand{
+			Token: token,
+		})
+
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(result.Result.Content)
+		assert.Equal(t, "archive", string(data))
+	})
+
+	t.Run("failure: export was replaced", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newDataExportService(ctrl)
+
+		newer := entity.NewDataExport(user.Id)
+		newer.Complete(time.Now().Add(time.Hour))
+		newerValue, _ := json.Marshal(newer)
+		m.valkeyRepo.EXPECT().Get(gomock.Any(), exportKey).Return(string(newerValue), nil)
+
+		_, err := service.DownloadDataExport(&command.DownloadDataExportCommand{
+			Token: token,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrDataExportInvalid)
+	})
+
+	t.Run("failure: invalid token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _ := newDataExportService(ctrl)
+
+		_, err := service.DownloadDataExport(&command.DownloadDataExportCommand{
+			Token: "invalid-token",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrDataExportInvalid)
+	})
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/event"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type loginCounter struct {
+	name      string
+	threshold int
+	// account counters belong to a single user, who is told when they lock.
+	account bool
+}
+
+// loginLockout locks out whoever fails to authenticate too often, following
+// the backoff of entity.LoginLockoutPolicy. Failures to reach Valkey are
+// ignored rather than blocking sign-ins.
+type loginLockout struct {
+	eventPublisher   event.EventPublisher
+	valkeyRepository repository.ValkeyRepository
+	policy           entity.LoginLockoutPolicy
+}
+
+// passwordCounters are shared by every endpoint checking a password, so
+// guessing it elsewhere than on the login does not get around the lockout.
+func (lockout *loginLockout) passwordCounters(email string, ipAddress string) []loginCounter {
+	counters := make([]loginCounter, 0, 2)
+	if lockout.policy.EmailThreshold > 0 {
+		counters = append(counters, loginCounter{
+			name:      "email:" + strings.ToLower(email),
+			threshold: lockout.policy.EmailThreshold,
+			account:   true,
+		})
+	}
+	if lockout.policy.IpThreshold > 0 && ipAddress != "" {
+		counters = append(counters, loginCounter{
+			name:      "ip:" + ipAddress,
+			threshold: lockout.policy.IpThreshold,
+		})
+	}
+	return counters
+}
+
+// mfaCounters count the wrong second factors sent for a user, whatever the
+// challenge they were sent for.
+func (lockout *loginLockout) mfaCounters(userId uuid.UUID) []loginCounter {
+	if lockout.policy.EmailThreshold <= 0 {
+		return nil
+	}
+	return []loginCounter{{
+		name:      "mfa:" + userId.String(),
+		threshold: lockout.policy.EmailThreshold,
+		account:   true,
+	}}
+}
+
+func (lockout *loginLockout) check(counters []loginCounter) error {
+	ctx := context.Background()
+	for _, counter := range counters {
+		value, err := lockout.valkeyRepository.Get(ctx, loginLockoutKey(counter.name))
+		if err != nil {
+			continue
+		}
+
+		until, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if retryAfter := time.Until(time.Unix(until, 0)); retryAfter > 0 {
+			return &entity.LoginLockedError{RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// recordFailure counts a failed attempt against every counter and locks out
+// those reaching their threshold. Unknown emails are counted the same way so
+// the lockout does not reveal who is registered.
+func (lockout *loginLockout) recordFailure(counters []loginCounter, user *entity.User, ipAddress string, cause error) error {
+	ctx := context.Background()
+	var locked *entity.LoginLockedError
+
+	for _, counter := range counters {
+		key := loginFailuresKey(counter.name)
+		failures, err := lockout.valkeyRepository.Increment(ctx, key)
+		if err != nil {
+			continue
+		}
+
+		duration := lockout.policy.LockoutDuration(failures, counter.threshold)
+		lockout.valkeyRepository.Expire(ctx, key, int((lockout.policy.Window + duration).Seconds()))
+		if duration <= 0 {
+			continue
+		}
+
+		until := time.Now().Add(duration)
+		lockout.valkeyRepository.Set(ctx, loginLockoutKey(counter.name), strconv.FormatInt(until.Unix(), 10), int(duration.Seconds()))
+		if locked == nil || duration > locked.RetryAfter {
+			locked = &entity.LoginLockedError{RetryAfter: duration}
+		}
+
+		if counter.account && user != nil {
+			event := entity.LoginLockedEvent{
+				Email:     user.Email,
+				Name:      user.Name,
+				IpAddress: ipAddress,
+				Until:     until,
+			}
+			lockout.eventPublisher.PublishWithKey(entity.LOGIN_LOCKED, []byte(user.Email), event)
+		}
+	}
+
+	if locked != nil {
+		return locked
+	}
+	return cause
+}
+
+// reset clears the account counters only. The ip counter is left to expire,
+// otherwise signing in to one account would clear the failures of an ip
+// guessing passwords of others.
+func (lockout *loginLockout) reset(counters []loginCounter) {
+	for _, counter := range counters {
+		if counter.account {
+			lockout.valkeyRepository.Delete(context.Background(), loginFailuresKey(counter.name), loginLockoutKey(counter.name))
+		}
+	}
+}
+
+func loginFailuresKey(name string) string {
+	return fmt.Sprintf("login-failures:%s", name)
+}
+
+func loginLockoutKey(name string) string {
+	return fmt.Sprintf("%s:%s", entity.LOGIN_LOCKED, name)
+}
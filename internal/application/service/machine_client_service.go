@@ -0,0 +1,78 @@
+package service
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+)
+
+// MachineClientService manages the clients used by backend services. It is not
+// exposed over http; operators register clients with cmd/machine-client.
+type MachineClientService struct {
+	machineClientRepository repository.MachineClientRepository
+}
+
+func NewMachineClientService(machineClientRepository repository.MachineClientRepository) *MachineClientService {
+	return &MachineClientService{
+		machineClientRepository: machineClientRepository,
+	}
+}
+
+// RegisterMachineClient creates a client with a random id and secret. The plain
+// secret is only returned here; just its hash is stored.
+func (service *MachineClientService) RegisterMachineClient(registerMachineClientCommand *command.RegisterMachineClientCommand) (*command.RegisterMachineClientCommandResult, error) {
+	clientId, err := util.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	clientSecret, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	secretHash, err := util.HashPwd(clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	clientEntity := entity.NewMachineClient(clientId, secretHash, registerMachineClientCommand.Name, registerMachineClientCommand.Scopes)
+
+	validatedClient, err := entity.NewValidatedMachineClient(clientEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := service.machineClientRepository.Create(validatedClient)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.RegisterMachineClientCommandResult{
+		Result:       mapper.NewMachineClientResultFromEntity(client),
+		ClientSecret: clientSecret,
+	}
+
+	return &result, nil
+}
+
+func (service *MachineClientService) ListMachineClients() (*command.ListMachineClientsCommandResult, error) {
+	clients, err := service.machineClientRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*common.MachineClientResult, len(clients))
+	for i, client := range clients {
+		results[i] = mapper.NewMachineClientResultFromEntity(client)
+	}
+
+	result := command.ListMachineClientsCommandResult{
+		Result: results,
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,18 @@
+package service_test
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"os"
+	"testing"
+)
+
+// TestMain installs a throwaway keyring, as cmd/main.go does at startup.
+func TestMain(m *testing.M) {
+	keyring, err := util.NewEphemeralKeyring()
+	if err != nil {
+		panic(err)
+	}
+	util.SetKeyring(keyring)
+
+	os.Exit(m.Run())
+}
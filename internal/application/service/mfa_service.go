@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/event"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MfaService struct {
+	eventPublisher           event.EventPublisher
+	valkeyRepository         repository.ValkeyRepository
+	userRepository           repository.UserRepository
+	totpCredentialRepository repository.TotpCredentialRepository
+	recoveryCodeRepository   repository.RecoveryCodeRepository
+	issuer                   string
+	lockout                  *loginLockout
+}
+
+func NewMfaService(eventPublisher event.EventPublisher, valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository, totpCredentialRepository repository.TotpCredentialRepository, recoveryCodeRepository repository.RecoveryCodeRepository, issuer string, lockoutPolicy entity.LoginLockoutPolicy) *MfaService {
+	return &MfaService{
+		eventPublisher:           eventPublisher,
+		valkeyRepository:         valkeyRepository,
+		userRepository:           userRepository,
+		totpCredentialRepository: totpCredentialRepository,
+		recoveryCodeRepository:   recoveryCodeRepository,
+		issuer:                   issuer,
+		lockout: &loginLockout{
+			eventPublisher:   eventPublisher,
+			valkeyRepository: valkeyRepository,
+			policy:           lockoutPolicy,
+		},
+	}
+}
+
+// EnrollTotp starts an enrollment. The secret is kept aside until the user
+// proves their authenticator works with ConfirmTotp.
+func (service *MfaService) EnrollTotp(enrollTotpCommand *command.EnrollTotpCommand) (*command.EnrollTotpCommandResult, error) {
+	enabled, err := service.totpCredentialRepository.ExistsByUserId(enrollTotpCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, entity.ErrMfaAlreadyEnabled
+	}
+
+	secret, err := util.GenerateTotpSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.valkeyRepository.Set(context.Background(), totpEnrollmentKey(enrollTotpCommand.UserId), secret, int(entity.TOTP_ENROLLMENT_DURATION.Seconds())); err != nil {
+		return nil, err
+	}
+
+	result := command.EnrollTotpCommandResult{
+		Result: &common.TotpEnrollmentResult{
+			Secret: secret,
+			Uri:    util.TotpUri(service.issuer, enrollTotpCommand.Email, secret),
+		},
+	}
+
+	return &result, nil
+}
+
+// ConfirmTotp turns MFA on and hands out the first set of recovery codes.
+func (service *MfaService) ConfirmTotp(confirmTotpCommand *command.ConfirmTotpCommand) (*command.ConfirmTotpCommandResult, error) {
+	secret, err := service.valkeyRepository.Get(context.Background(), totpEnrollmentKey(confirmTotpCommand.UserId))
+	if err != nil || secret == "" {
+		return nil, entity.ErrMfaEnrollmentExpired
+	}
+
+	if err := service.verifyTotp(confirmTotpCommand.UserId, secret, confirmTotpCommand.Code); err != nil {
+		return nil, err
+	}
+
+	validatedCredential, err := entity.NewValidatedTotpCredential(entity.NewTotpCredential(confirmTotpCommand.UserId, secret))
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := service.replaceRecoveryCodes(confirmTotpCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := service.totpCredentialRepository.Create(validatedCredential); err != nil {
+		return nil, err
+	}
+
+	service.valkeyRepository.Delete(context.Background(), totpEnrollmentKey(confirmTotpCommand.UserId))
+
+	result := command.ConfirmTotpCommandResult{
+		Result: &common.RecoveryCodesResult{Codes: codes},
+	}
+
+	return &result, nil
+}
+
+// DisableTotp fails with a *entity.LoginLockedError while the user is locked
+// out, wrong passwords counting the same as on the login.
+func (service *MfaService) DisableTotp(disableTotpCommand *command.DisableTotpCommand) error {
+	user, err := service.userRepository.FindById(disableTotpCommand.UserId)
+	if err != nil {
+		return err
+	}
+
+	counters := service.lockout.passwordCounters(user.Email, disableTotpCommand.IpAddress)
+	if err := service.lockout.check(counters); err != nil {
+		return err
+	}
+
+	if err := util.ComparePwd(disableTotpCommand.Password, user.Password); err != nil {
+		return service.lockout.recordFailure(counters, user, disableTotpCommand.IpAddress, err)
+	}
+
+	enabled, err := service.totpCredentialRepository.ExistsByUserId(user.Id)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return entity.ErrMfaNotEnabled
+	}
+
+	if err := service.totpCredentialRepository.DeleteByUserId(user.Id); err != nil {
+		return err
+	}
+
+	return service.recoveryCodeRepository.DeleteByUserId(user.Id)
+}
+
+func (service *MfaService) GetMfaStatus(getMfaStatusCommand *command.GetMfaStatusCommand) (*command.GetMfaStatusCommandResult, error) {
+	enabled, err := service.totpCredentialRepository.ExistsByUserId(getMfaStatusCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := 0
+	if enabled {
+		remaining, err = service.recoveryCodeRepository.CountUnusedByUserId(getMfaStatusCommand.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := command.GetMfaStatusCommandResult{
+		Result: &common.MfaStatusResult{
+			TotpEnabled:            enabled,
+			RecoveryCodesRemaining: remaining,
+		},
+	}
+
+	return &result, nil
+}
+
+// CreateMfaChallenge is called once the password was checked. Users without
+// a second factor need no challenge; the others get a short lived token to
+// send back along with their code. The failed password attempts are only
+// cleared once the user is through, so a stolen password alone does not reset
+// the lockout.
+func (service *MfaService) CreateMfaChallenge(createMfaChallengeCommand *command.CreateMfaChallengeCommand) (*command.CreateMfaChallengeCommandResult, error) {
+	userId := createMfaChallengeCommand.User.Id
+
+	enabled, err := service.totpCredentialRepository.ExistsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		if createMfaChallengeCommand.Method == entity.LOGIN_METHOD_PASSWORD {
+			service.lockout.reset(service.lockout.passwordCounters(createMfaChallengeCommand.User.Email, ""))
+		}
+		return &command.CreateMfaChallengeCommandResult{
+			Result: &common.MfaChallengeResult{Required: false},
+		}, nil
+	}
+
+	challengeId := uuid.NewString()
+	mfaToken, err := util.GenerateMfaChallengeToken(util.MfaChallengeTokenClaims{
+		Id:          userId,
+		ChallengeId: challengeId,
+		Method:      createMfaChallengeCommand.Method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := int(util.MFA_CHALLENGE_TOKEN_DURATION.Seconds())
+	if err := service.valkeyRepository.Set(context.Background(), mfaChallengeKey(challengeId), userId.String(), ttl); err != nil {
+		return nil, err
+	}
+
+	result := command.CreateMfaChallengeCommandResult{
+		Result: &common.MfaChallengeResult{
+			Required:  true,
+			MfaToken:  mfaToken,
+			ExpiresAt: time.Now().Add(util.MFA_CHALLENGE_TOKEN_DURATION),
+		},
+	}
+
+	return &result, nil
+}
+
+// VerifyMfaChallenge completes the login with either a TOTP or a recovery
+// code. A challenge can only be completed once and is dropped after too many
+// wrong codes. Wrong codes also count against the user across challenges and
+// fail with a *entity.LoginLockedError once they are locked out.
+func (service *MfaService) VerifyMfaChallenge(verifyMfaChallengeCommand *command.VerifyMfaChallengeCommand) (*command.VerifyMfaChallengeCommandResult, error) {
+	claims, err := util.ValidateMfaChallengeToken(verifyMfaChallengeCommand.MfaToken)
+	if err != nil {
+		return nil, entity.ErrMfaChallengeInvalid
+	}
+
+	ctx := context.Background()
+	key := mfaChallengeKey(claims.ChallengeId)
+	attemptsKey := key + ":attempts"
+
+	userId, err := service.valkeyRepository.Get(ctx, key)
+	if err != nil || userId != claims.Id.String() {
+		return nil, entity.ErrMfaChallengeInvalid
+	}
+
+	counters := service.lockout.mfaCounters(claims.Id)
+	if err := service.lockout.check(counters); err != nil {
+		return nil, err
+	}
+
+	attempts, err := service.valkeyRepository.Increment(ctx, attemptsKey)
+	if err != nil {
+		return nil, err
+	}
+	if attempts == 1 {
+		service.valkeyRepository.Expire(ctx, attemptsKey, int(util.MFA_CHALLENGE_TOKEN_DURATION.Seconds()))
+	}
+	if attempts > entity.MFA_CHALLENGE_MAX_ATTEMPTS {
+		service.valkeyRepository.Delete(ctx, key, attemptsKey)
+		return nil, entity.ErrMfaChallengeInvalid
+	}
+
+	user, err := service.userRepository.FindById(claims.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.verifySecondFactor(user.Id, verifyMfaChallengeCommand); err != nil {
+		if errors.Is(err, entity.ErrMfaInvalidCode) || errors.Is(err, entity.ErrRecoveryCodeInvalid) {
+			return nil, service.lockout.recordFailure(counters, user, verifyMfaChallengeCommand.IpAddress, err)
+		}
+		return nil, err
+	}
+
+	service.valkeyRepository.Delete(ctx, key, attemptsKey)
+	service.lockout.reset(counters)
+	if claims.Method == entity.LOGIN_METHOD_PASSWORD {
+		service.lockout.reset(service.lockout.passwordCounters(user.Email, ""))
+	}
+
+	result := command.VerifyMfaChallengeCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+		Method: claims.Method,
+	}
+
+	return &result, nil
+}
+
+func (service *MfaService) verifySecondFactor(userId uuid.UUID, verifyMfaChallengeCommand *command.VerifyMfaChallengeCommand) error {
+	if verifyMfaChallengeCommand.RecoveryCode != "" {
+		return service.useRecoveryCode(userId, verifyMfaChallengeCommand.RecoveryCode)
+	}
+
+	credential, err := service.totpCredentialRepository.FindByUserId(userId)
+	if err != nil {
+		return entity.ErrMfaChallengeInvalid
+	}
+
+	return service.verifyTotp(userId, credential.Secret, verifyMfaChallengeCommand.Code)
+}
+
+// RegenerateRecoveryCodes invalidates the remaining codes and issues a new
+// set. The user is alerted by email in case it was not them. Wrong passwords
+// count toward the lockout as on the login.
+func (service *MfaService) RegenerateRecoveryCodes(regenerateRecoveryCodesCommand *command.RegenerateRecoveryCodesCommand) (*command.RegenerateRecoveryCodesCommandResult, error) {
+	user, err := service.userRepository.FindById(regenerateRecoveryCodesCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := service.lockout.passwordCounters(user.Email, regenerateRecoveryCodesCommand.IpAddress)
+	if err := service.lockout.check(counters); err != nil {
+		return nil, err
+	}
+
+	if err := util.ComparePwd(regenerateRecoveryCodesCommand.Password, user.Password); err != nil {
+		return nil, service.lockout.recordFailure(counters, user, regenerateRecoveryCodesCommand.IpAddress, err)
+	}
+
+	enabled, err := service.totpCredentialRepository.ExistsByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, entity.ErrMfaNotEnabled
+	}
+
+	codes, err := service.replaceRecoveryCodes(user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	event := entity.RecoveryCodesRegeneratedEvent{
+		Email: user.Email,
+		Name:  user.Name,
+		Time:  time.Now(),
+	}
+
+	service.eventPublisher.PublishWithKey(entity.RECOVERY_CODES_REGENERATED, []byte(user.Email), event)
+
+	result := command.RegenerateRecoveryCodesCommandResult{
+		Result: &common.RecoveryCodesResult{Codes: codes},
+	}
+
+	return &result, nil
+}
+
+func (service *MfaService) replaceRecoveryCodes(userId uuid.UUID) ([]string, error) {
+	codes := make([]string, entity.RECOVERY_CODE_COUNT)
+	validatedCodes := make([]*entity.ValidatedRecoveryCode, entity.RECOVERY_CODE_COUNT)
+	for i := range codes {
+		code, err := util.RandomRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		hashed, err := util.HashPwd(entity.NormalizeRecoveryCode(code))
+		if err != nil {
+			return nil, err
+		}
+
+		validatedCode, err := entity.NewValidatedRecoveryCode(entity.NewRecoveryCode(userId, hashed))
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i] = code
+		validatedCodes[i] = validatedCode
+	}
+
+	if err := service.recoveryCodeRepository.ReplaceAllByUserId(userId, validatedCodes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// useRecoveryCode burns the matching code. Marking it used only succeeds once,
+// so the same code sent twice at the same time is still accepted only once.
+func (service *MfaService) useRecoveryCode(userId uuid.UUID, code string) error {
+	codes, err := service.recoveryCodeRepository.FindUnusedByUserId(userId)
+	if err != nil {
+		return err
+	}
+
+	normalized := entity.NormalizeRecoveryCode(code)
+	for _, recoveryCode := range codes {
+		if util.ComparePwd(normalized, recoveryCode.CodeHash) != nil {
+			continue
+		}
+
+		used, err := service.recoveryCodeRepository.MarkUsed(recoveryCode.Id)
+		if err != nil {
+			return err
+		}
+		if !used {
+			break
+		}
+		return nil
+	}
+
+	return entity.ErrRecoveryCodeInvalid
+}
+
+// acceptTotpStepScript moves the last accepted step of the user forward, or
+// refuses a step that is not past it, in one go so two requests sending the
+// same code at the same time cannot both pass.
+const acceptTotpStepScript = `
+local last = redis.call('GET', KEYS[1])
+if last and tonumber(ARGV[1]) <= tonumber(last) then
+	return 0
+end
+
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[2])
+return 1
+`
+
+// verifyTotp accepts each code only once, and no code older than the last one
+// accepted, so a code seen over someone's shoulder cannot be used again within
+// its validity window.
+func (service *MfaService) verifyTotp(userId uuid.UUID, secret string, code string) error {
+	step, ok := util.ValidateTotp(secret, code, time.Now())
+	if !ok {
+		return entity.ErrMfaInvalidCode
+	}
+
+	// A code stays valid for the steps around it, so the step is remembered as
+	// long; past that any older step fails to validate anyway.
+	reply, err := service.valkeyRepository.Eval(context.Background(), acceptTotpStepScript, []string{totpLastStepKey(userId)},
+		step, (2*util.TOTP_SKEW+1)*util.TOTP_PERIOD)
+	if err != nil {
+		return err
+	}
+	if reply != int64(1) {
+		return entity.ErrMfaInvalidCode
+	}
+
+	return nil
+}
+
+func totpEnrollmentKey(userId uuid.UUID) string {
+	return fmt.Sprintf("user:%s:%s", userId, entity.TOTP_ENROLLMENT)
+}
+
+func totpLastStepKey(userId uuid.UUID) string {
+	return fmt.Sprintf("user:%s:%s", userId, entity.TOTP_LAST_STEP)
+}
+
+func mfaChallengeKey(challengeId string) string {
+	return fmt.Sprintf("%s:%s", entity.MFA_CHALLENGE, challengeId)
+}
@@ -0,0 +1,892 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMfaService_EnrollTotp(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	enrollmentKey := fmt.Sprintf("user:%s:%s", user.Id, entity.TOTP_ENROLLMENT)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		var storedSecret string
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(false, nil)
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), enrollmentKey, gomock.Any(), 10*60).
+			DoAndReturn(func(_ any, _ string, value any, _ int) error {
+				storedSecret = value.(string)
+				return nil
+			})
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.EnrollTotp(&command.EnrollTotpCommand{
+			UserId: user.Id,
+			Email:  user.Email,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, storedSecret, result.Result.Secret)
+		assert.True(t, strings.HasPrefix(result.Result.Uri, "otpauth://totp/go-ddd:test@example.com?"))
+	})
+
+	t.Run("failure: already enabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(true, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.EnrollTotp(&command.EnrollTotpCommand{
+			UserId: user.Id,
+			Email:  user.Email,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaAlreadyEnabled)
+		assert.Nil(t, result)
+	})
+}
+
+func TestMfaService_ConfirmTotp(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	enrollmentKey := fmt.Sprintf("user:%s:%s", user.Id, entity.TOTP_ENROLLMENT)
+	secret, _ := util.GenerateTotpSecret()
+	step := util.TotpStep(time.Now())
+	code, _ := util.TotpCode(secret, step)
+	lastStepKey := fmt.Sprintf("user:%s:%s", user.Id, entity.TOTP_LAST_STEP)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), enrollmentKey).Return(secret, nil)
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), []string{lastStepKey}, step, 90).Return(int64(1), nil)
+		mockRecoveryCodeRepo.EXPECT().
+			ReplaceAllByUserId(user.Id, gomock.Len(entity.RECOVERY_CODE_COUNT)).
+			Return(nil)
+		mockTotpRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(credential *entity.ValidatedTotpCredential) (*entity.TotpCredential, error) {
+				assert.Equal(t, user.Id, credential.UserId)
+				assert.Equal(t, secret, credential.Secret)
+				return &credential.TotpCredential, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), enrollmentKey).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.ConfirmTotp(&command.ConfirmTotpCommand{
+			UserId: user.Id,
+			Code:   code,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Result.Codes, entity.RECOVERY_CODE_COUNT)
+	})
+
+	t.Run("failure: wrong code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), enrollmentKey).Return(secret, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.ConfirmTotp(&command.ConfirmTotpCommand{
+			UserId: user.Id,
+			Code:   "12345",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaInvalidCode)
+	})
+
+	t.Run("failure: code already used", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), enrollmentKey).Return(secret, nil)
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), []string{lastStepKey}, step, 90).Return(int64(0), nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.ConfirmTotp(&command.ConfirmTotpCommand{
+			UserId: user.Id,
+			Code:   code,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaInvalidCode)
+	})
+
+	t.Run("failure: no pending enrollment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), enrollmentKey).Return("", errors.New("valkey nil message"))
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.ConfirmTotp(&command.ConfirmTotpCommand{
+			UserId: user.Id,
+			Code:   code,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaEnrollmentExpired)
+	})
+}
+
+func TestMfaService_DisableTotp(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	dbUser := *user
+	dbUser.Password, _ = util.HashPwd(user.Password)
+
+	policy := entity.LoginLockoutPolicy{
+		EmailThreshold: 3,
+		Window:         15 * time.Minute,
+		BaseDuration:   time.Minute,
+		MaxDuration:    time.Hour,
+	}
+	emailFailuresKey := "login-failures:email:" + user.Email
+	emailLockoutKey := entity.LOGIN_LOCKED + ":email:" + user.Email
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(true, nil)
+		mockTotpRepo.EXPECT().DeleteByUserId(user.Id).Return(nil)
+		mockRecoveryCodeRepo.EXPECT().DeleteByUserId(user.Id).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		err := service.DisableTotp(&command.DisableTotpCommand{
+			UserId:   user.Id,
+			Password: "correct-password",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: wrong password", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		err := service.DisableTotp(&command.DisableTotpCommand{
+			UserId:   user.Id,
+			Password: "wrong-password",
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: wrong password locks out on threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return("", errors.New("nil message"))
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), emailFailuresKey).Return(int64(3), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), emailFailuresKey, 16*60).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), emailLockoutKey, gomock.Any(), 60).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.LOGIN_LOCKED, []byte(user.Email), gomock.Any()).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", policy)
+
+		err := service.DisableTotp(&command.DisableTotpCommand{
+			UserId:   user.Id,
+			Password: "wrong-password",
+		})
+
+		var locked *entity.LoginLockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.Equal(t, time.Minute, locked.RetryAfter)
+	})
+
+	t.Run("failure: locked out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		until := time.Now().Add(5 * time.Minute).Unix()
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return(strconv.FormatInt(until, 10), nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", policy)
+
+		err := service.DisableTotp(&command.DisableTotpCommand{
+			UserId:   user.Id,
+			Password: "correct-password",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrLoginLocked)
+	})
+}
+
+func TestMfaService_CreateMfaChallenge(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	t.Run("success: no second factor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(false, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.CreateMfaChallenge(&command.CreateMfaChallengeCommand{
+			User: mapper.NewUserResultFromEntity(user),
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Result.Required)
+		assert.Empty(t, result.Result.MfaToken)
+	})
+
+	t.Run("success: no second factor clears password failures", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		emailFailuresKey := "login-failures:email:" + user.Email
+		emailLockoutKey := entity.LOGIN_LOCKED + ":email:" + user.Email
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(false, nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), emailFailuresKey, emailLockoutKey).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{EmailThreshold: 3})
+
+		result, err := service.CreateMfaChallenge(&command.CreateMfaChallengeCommand{
+			User:   mapper.NewUserResultFromEntity(user),
+			Method: entity.LOGIN_METHOD_PASSWORD,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Result.Required)
+	})
+
+	t.Run("success: challenge", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(true, nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), gomock.Any(), user.Id.String(), 5*60).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.CreateMfaChallenge(&command.CreateMfaChallengeCommand{
+			User: mapper.NewUserResultFromEntity(user),
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Result.Required)
+
+		claims, err := util.ValidateMfaChallengeToken(result.Result.MfaToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, claims.Id)
+	})
+
+	t.Run("failure: repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(false, errors.New("connection refused"))
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.CreateMfaChallenge(&command.CreateMfaChallengeCommand{
+			User: mapper.NewUserResultFromEntity(user),
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestMfaService_VerifyMfaChallenge(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	secret, _ := util.GenerateTotpSecret()
+	credential := entity.NewTotpCredential(user.Id, secret)
+	step := util.TotpStep(time.Now())
+	code, _ := util.TotpCode(secret, step)
+	lastStepKey := fmt.Sprintf("user:%s:%s", user.Id, entity.TOTP_LAST_STEP)
+
+	mfaToken, _ := util.GenerateMfaChallengeToken(util.MfaChallengeTokenClaims{
+		Id:          user.Id,
+		ChallengeId: "challenge-id",
+		Method:      entity.LOGIN_METHOD_MAGIC_LINK,
+	})
+	challengeKey := fmt.Sprintf("%s:%s", entity.MFA_CHALLENGE, "challenge-id")
+	attemptsKey := challengeKey + ":attempts"
+
+	policy := entity.LoginLockoutPolicy{
+		EmailThreshold: 3,
+		Window:         15 * time.Minute,
+		BaseDuration:   time.Minute,
+		MaxDuration:    time.Hour,
+	}
+	mfaFailuresKey := fmt.Sprintf("login-failures:mfa:%s", user.Id)
+	mfaLockoutKey := fmt.Sprintf("%s:mfa:%s", entity.LOGIN_LOCKED, user.Id)
+
+	var recoveryCodes []*entity.RecoveryCode
+	for _, code := range []string{"k7m2p-x9qra", "abcde-fghjk"} {
+		hashed, _ := util.HashPwd(entity.NormalizeRecoveryCode(code))
+		recoveryCodes = append(recoveryCodes, entity.NewRecoveryCode(user.Id, hashed))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), attemptsKey, 5*60).Return(nil)
+		mockTotpRepo.EXPECT().FindByUserId(user.Id).Return(credential, nil)
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), []string{lastStepKey}, step, 90).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), challengeKey, attemptsKey).Return(nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken: mfaToken,
+			Code:     code,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Id)
+		assert.Equal(t, entity.LOGIN_METHOD_MAGIC_LINK, result.Method)
+	})
+
+	t.Run("success: clears password and mfa failures", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		passwordMfaToken, _ := util.GenerateMfaChallengeToken(util.MfaChallengeTokenClaims{
+			Id:          user.Id,
+			ChallengeId: "challenge-id",
+			Method:      entity.LOGIN_METHOD_PASSWORD,
+		})
+		emailFailuresKey := "login-failures:email:" + user.Email
+		emailLockoutKey := entity.LOGIN_LOCKED + ":email:" + user.Email
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), mfaLockoutKey).Return("", errors.New("nil message"))
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), attemptsKey, 5*60).Return(nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockRecoveryCodeRepo.EXPECT().FindUnusedByUserId(user.Id).Return(recoveryCodes, nil)
+		mockRecoveryCodeRepo.EXPECT().MarkUsed(recoveryCodes[0].Id).Return(true, nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), challengeKey, attemptsKey).Return(nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), mfaFailuresKey, mfaLockoutKey).Return(nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), emailFailuresKey, emailLockoutKey).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", policy)
+
+		result, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken:     passwordMfaToken,
+			RecoveryCode: "k7m2p-x9qra",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.LOGIN_METHOD_PASSWORD, result.Method)
+	})
+
+	t.Run("failure: wrong code locks out on threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), mfaLockoutKey).Return("", errors.New("nil message"))
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(2), nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockRecoveryCodeRepo.EXPECT().FindUnusedByUserId(user.Id).Return(recoveryCodes, nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), mfaFailuresKey).Return(int64(3), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), mfaFailuresKey, 16*60).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), mfaLockoutKey, gomock.Any(), 60).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.LOGIN_LOCKED, []byte(user.Email), gomock.Any()).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", policy)
+
+		result, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken:     mfaToken,
+			RecoveryCode: "zzzzz-zzzzz",
+		})
+
+		var locked *entity.LoginLockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.Equal(t, time.Minute, locked.RetryAfter)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: locked out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		until := time.Now().Add(5 * time.Minute).Unix()
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), mfaLockoutKey).Return(strconv.FormatInt(until, 10), nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", policy)
+
+		_, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken: mfaToken,
+			Code:     code,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrLoginLocked)
+	})
+
+	t.Run("failure: wrong code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(2), nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockTotpRepo.EXPECT().FindByUserId(user.Id).Return(credential, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken: mfaToken,
+			Code:     "12345",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaInvalidCode)
+		assert.Nil(t, result)
+	})
+
+	t.Run("success: recovery code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), attemptsKey, 5*60).Return(nil)
+		mockRecoveryCodeRepo.EXPECT().FindUnusedByUserId(user.Id).Return(recoveryCodes, nil)
+		mockRecoveryCodeRepo.EXPECT().MarkUsed(recoveryCodes[1].Id).Return(true, nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), challengeKey, attemptsKey).Return(nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken:     mfaToken,
+			RecoveryCode: "ABCDE FGHJK",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Id)
+	})
+
+	t.Run("failure: recovery code already used", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(2), nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockRecoveryCodeRepo.EXPECT().FindUnusedByUserId(user.Id).Return(recoveryCodes, nil)
+		mockRecoveryCodeRepo.EXPECT().MarkUsed(recoveryCodes[1].Id).Return(false, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken:     mfaToken,
+			RecoveryCode: "abcde-fghjk",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrRecoveryCodeInvalid)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: unknown recovery code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(2), nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockRecoveryCodeRepo.EXPECT().FindUnusedByUserId(user.Id).Return(recoveryCodes, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken:     mfaToken,
+			RecoveryCode: "zzzzz-zzzzz",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrRecoveryCodeInvalid)
+	})
+
+	t.Run("failure: too many attempts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return(user.Id.String(), nil)
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), attemptsKey).Return(int64(entity.MFA_CHALLENGE_MAX_ATTEMPTS+1), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), challengeKey, attemptsKey).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken: mfaToken,
+			Code:     code,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaChallengeInvalid)
+	})
+
+	t.Run("failure: challenge already completed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), challengeKey).Return("", errors.New("valkey nil message"))
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken: mfaToken,
+			Code:     code,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaChallengeInvalid)
+	})
+
+	t.Run("failure: access token instead of challenge", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		accessToken, _ := util.GenerateAccessToken(util.AccessTokenClaims{
+			Id:    user.Id,
+			Name:  user.Name,
+			Email: user.Email,
+		})
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.VerifyMfaChallenge(&command.VerifyMfaChallengeCommand{
+			MfaToken: accessToken,
+			Code:     code,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaChallengeInvalid)
+	})
+}
+
+func TestMfaService_RegenerateRecoveryCodes(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	dbUser := *user
+	dbUser.Password, _ = util.HashPwd(user.Password)
+
+	policy := entity.LoginLockoutPolicy{
+		EmailThreshold: 3,
+		Window:         15 * time.Minute,
+		BaseDuration:   time.Minute,
+		MaxDuration:    time.Hour,
+	}
+	emailFailuresKey := "login-failures:email:" + user.Email
+	emailLockoutKey := entity.LOGIN_LOCKED + ":email:" + user.Email
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		var storedCodes []*entity.ValidatedRecoveryCode
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(true, nil)
+		mockRecoveryCodeRepo.EXPECT().
+			ReplaceAllByUserId(user.Id, gomock.Any()).
+			DoAndReturn(func(_ any, codes []*entity.ValidatedRecoveryCode) error {
+				storedCodes = codes
+				return nil
+			})
+		mockEventPub.EXPECT().PublishWithKey(entity.RECOVERY_CODES_REGENERATED, []byte(user.Email), gomock.Any()).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.RegenerateRecoveryCodes(&command.RegenerateRecoveryCodesCommand{
+			UserId:   user.Id,
+			Password: "correct-password",
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Result.Codes, entity.RECOVERY_CODE_COUNT)
+		assert.Len(t, storedCodes, entity.RECOVERY_CODE_COUNT)
+		for i, code := range result.Result.Codes {
+			assert.NoError(t, util.ComparePwd(entity.NormalizeRecoveryCode(code), storedCodes[i].CodeHash))
+		}
+	})
+
+	t.Run("failure: mfa not enabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(false, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		result, err := service.RegenerateRecoveryCodes(&command.RegenerateRecoveryCodesCommand{
+			UserId:   user.Id,
+			Password: "correct-password",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrMfaNotEnabled)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: wrong password", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", entity.LoginLockoutPolicy{})
+
+		_, err := service.RegenerateRecoveryCodes(&command.RegenerateRecoveryCodesCommand{
+			UserId:   user.Id,
+			Password: "wrong-password",
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: wrong password below threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return("", errors.New("nil message"))
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), emailFailuresKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), emailFailuresKey, 15*60).Return(nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", policy)
+
+		_, err := service.RegenerateRecoveryCodes(&command.RegenerateRecoveryCodesCommand{
+			UserId:   user.Id,
+			Password: "wrong-password",
+		})
+
+		assert.ErrorIs(t, err, bcrypt.ErrMismatchedHashAndPassword)
+	})
+
+	t.Run("failure: locked out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+		mockRecoveryCodeRepo := mocks.NewMockRecoveryCodeRepository(ctrl)
+
+		until := time.Now().Add(5 * time.Minute).Unix()
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), emailLockoutKey).Return(strconv.FormatInt(until, 10), nil)
+
+		service := service.NewMfaService(mockEventPub, mockValkeyRepo, mockUserRepo, mockTotpRepo, mockRecoveryCodeRepo, "go-ddd", policy)
+
+		result, err := service.RegenerateRecoveryCodes(&command.RegenerateRecoveryCodesCommand{
+			UserId:   user.Id,
+			Password: "correct-password",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrLoginLocked)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,664 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type OAuthService struct {
+	valkeyRepository        repository.ValkeyRepository
+	userRepository          repository.UserRepository
+	oauthClientRepository   repository.OAuthClientRepository
+	oauthConsentRepository  repository.OAuthConsentRepository
+	machineClientRepository repository.MachineClientRepository
+	sessionService          interfaces.SessionService
+	tokenService            interfaces.TokenService
+	issuer                  string
+}
+
+func NewOAuthService(valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository, oauthClientRepository repository.OAuthClientRepository, oauthConsentRepository repository.OAuthConsentRepository, machineClientRepository repository.MachineClientRepository, sessionService interfaces.SessionService, tokenService interfaces.TokenService, issuer string) *OAuthService {
+	return &OAuthService{
+		valkeyRepository:        valkeyRepository,
+		userRepository:          userRepository,
+		oauthClientRepository:   oauthClientRepository,
+		oauthConsentRepository:  oauthConsentRepository,
+		machineClientRepository: machineClientRepository,
+		sessionService:          sessionService,
+		tokenService:            tokenService,
+		issuer:                  issuer,
+	}
+}
+
+// RegisterClient creates a client owned by the calling user. The plain client
+// secret is only returned here; just its hash is stored.
+func (service *OAuthService) RegisterClient(registerOAuthClientCommand *command.RegisterOAuthClientCommand) (*command.RegisterOAuthClientCommandResult, error) {
+	clientId, err := util.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientSecret, secretHash string
+	if !registerOAuthClientCommand.Public {
+		clientSecret, err = util.RandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+
+		secretHash, err = util.HashPwd(clientSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clientEntity := entity.NewOAuthClient(
+		registerOAuthClientCommand.OwnerId,
+		clientId,
+		secretHash,
+		registerOAuthClientCommand.Name,
+		registerOAuthClientCommand.RedirectUris,
+		registerOAuthClientCommand.Scopes,
+	)
+
+	validatedClient, err := entity.NewValidatedOAuthClient(clientEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := service.oauthClientRepository.Create(validatedClient)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.RegisterOAuthClientCommandResult{
+		Result:       mapper.NewOAuthClientResultFromEntity(client),
+		ClientSecret: clientSecret,
+	}
+
+	return &result, nil
+}
+
+func (service *OAuthService) ListClients(listOAuthClientsCommand *command.ListOAuthClientsCommand) (*command.ListOAuthClientsCommandResult, error) {
+	clients, err := service.oauthClientRepository.FindAllByOwnerId(listOAuthClientsCommand.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*common.OAuthClientResult, len(clients))
+	for i, client := range clients {
+		results[i] = mapper.NewOAuthClientResultFromEntity(client)
+	}
+
+	result := command.ListOAuthClientsCommandResult{
+		Result: results,
+	}
+
+	return &result, nil
+}
+
+// Authorize issues a single-use authorization code bound to the client, the
+// redirect uri and the PKCE challenge. Until the client and redirect uri are
+// known to be valid no result is returned, so the caller must not redirect;
+// after that, errors come with a result whose redirect uri the error should be
+// reported to. When the user has not yet granted the client every requested
+// scope, the result asks for consent instead of carrying a code.
+func (service *OAuthService) Authorize(authorizeCommand *command.AuthorizeCommand) (*command.AuthorizeCommandResult, error) {
+	client, err := service.oauthClientRepository.FindByClientId(authorizeCommand.ClientId)
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "unknown client")
+	}
+
+	if !client.HasRedirectUri(authorizeCommand.RedirectUri) {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_REQUEST, "redirect_uri is not registered for this client")
+	}
+
+	result := command.AuthorizeCommandResult{
+		RedirectUri: authorizeCommand.RedirectUri,
+		State:       authorizeCommand.State,
+	}
+
+	if authorizeCommand.ResponseType != "code" {
+		return &result, entity.NewOAuthError(entity.OAUTH_UNSUPPORTED_RESPONSE_TYPE, "only the code response type is supported")
+	}
+
+	scopes := entity.ParseScope(authorizeCommand.Scope)
+	if !client.AllowsScopes(scopes) {
+		return &result, entity.NewOAuthError(entity.OAUTH_INVALID_SCOPE, "requested scope is not allowed for this client")
+	}
+
+	if authorizeCommand.CodeChallenge == "" || authorizeCommand.CodeChallengeMethod != "S256" {
+		return &result, entity.NewOAuthError(entity.OAUTH_INVALID_REQUEST, "a S256 code_challenge is required")
+	}
+
+	consent, err := service.oauthConsentRepository.FindByUserIdAndClientId(authorizeCommand.UserId, client.ClientId)
+	if err != nil || !consent.Covers(scopes) {
+		return service.requestConsent(client, authorizeCommand, scopes, &result)
+	}
+
+	return service.issueAuthorizationCode(client, authorizeCommand, scopes, &result)
+}
+
+// Consent answers the consent asked for by Authorize. The request can only be
+// answered once and only by the user it was asked of.
+func (service *OAuthService) Consent(oauthConsentCommand *command.OAuthConsentCommand) (*command.AuthorizeCommandResult, error) {
+	reply, err := service.valkeyRepository.Eval(context.Background(), takeConsentRequestScript,
+		[]string{oauthConsentRequestKey(oauthConsentCommand.ConsentId)})
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to load consent request")
+	}
+
+	value, _ := reply.(string)
+	var authorizeCommand command.AuthorizeCommand
+	if value == "" || json.Unmarshal([]byte(value), &authorizeCommand) != nil || authorizeCommand.UserId != oauthConsentCommand.UserId {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_REQUEST, "consent request is invalid or expired")
+	}
+	authorizeCommand.SessionId = oauthConsentCommand.SessionId
+
+	client, err := service.oauthClientRepository.FindByClientId(authorizeCommand.ClientId)
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "unknown client")
+	}
+
+	result := command.AuthorizeCommandResult{
+		RedirectUri: authorizeCommand.RedirectUri,
+		State:       authorizeCommand.State,
+	}
+
+	if !oauthConsentCommand.Approved {
+		return &result, entity.NewOAuthError(entity.OAUTH_ACCESS_DENIED, "the user denied the request")
+	}
+
+	scopes := entity.ParseScope(authorizeCommand.Scope)
+	consent, err := service.oauthConsentRepository.FindByUserIdAndClientId(authorizeCommand.UserId, client.ClientId)
+	if err != nil {
+		consent = entity.NewOAuthConsent(authorizeCommand.UserId, client.ClientId, []string{})
+	}
+	consent.Grant(scopes)
+
+	validatedConsent, err := entity.NewValidatedOAuthConsent(consent)
+	if err != nil {
+		return &result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to store consent")
+	}
+
+	if _, err := service.oauthConsentRepository.Save(validatedConsent); err != nil {
+		return &result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to store consent")
+	}
+
+	return service.issueAuthorizationCode(client, &authorizeCommand, scopes, &result)
+}
+
+// takeConsentRequestScript reads and deletes the consent request in one step.
+const takeConsentRequestScript = `
+local value = redis.call('GET', KEYS[1])
+if not value then
+	return ''
+end
+
+redis.call('DEL', KEYS[1])
+return value
+`
+
+func (service *OAuthService) requestConsent(client *entity.OAuthClient, authorizeCommand *command.AuthorizeCommand, scopes []string, result *command.AuthorizeCommandResult) (*command.AuthorizeCommandResult, error) {
+	consentId, err := util.RandomToken(32)
+	if err != nil {
+		return result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to request consent")
+	}
+
+	value, err := json.Marshal(authorizeCommand)
+	if err != nil {
+		return result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to request consent")
+	}
+
+	ttl := int(entity.OAUTH_CONSENT_REQUEST_DURATION.Seconds())
+	if err := service.valkeyRepository.Set(context.Background(), oauthConsentRequestKey(consentId), value, ttl); err != nil {
+		return result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to request consent")
+	}
+
+	result.ConsentRequired = true
+	result.ConsentId = consentId
+	result.ClientName = client.Name
+	result.Scopes = scopes
+
+	return result, nil
+}
+
+func (service *OAuthService) issueAuthorizationCode(client *entity.OAuthClient, authorizeCommand *command.AuthorizeCommand, scopes []string, result *command.AuthorizeCommandResult) (*command.AuthorizeCommandResult, error) {
+	// auth_time is when the user signed in, which is when the session began.
+	session, err := service.sessionService.GetSession(&command.GetSessionCommand{
+		UserId:    authorizeCommand.UserId,
+		SessionId: authorizeCommand.SessionId,
+	})
+	if err != nil {
+		return result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "session is no longer active")
+	}
+
+	code, err := util.RandomToken(32)
+	if err != nil {
+		return result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to generate authorization code")
+	}
+
+	authorizationCode := entity.NewAuthorizationCode(
+		code,
+		client.ClientId,
+		authorizeCommand.UserId,
+		authorizeCommand.RedirectUri,
+		strings.Join(scopes, " "),
+		authorizeCommand.CodeChallenge,
+		authorizeCommand.Nonce,
+		session.Result.CreatedAt,
+		entity.AUTHORIZATION_CODE_DURATION,
+	)
+
+	value, err := json.Marshal(authorizationCode)
+	if err != nil {
+		return result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to store authorization code")
+	}
+
+	ttl := int(entity.AUTHORIZATION_CODE_DURATION.Seconds())
+	if err := service.valkeyRepository.Set(context.Background(), authorizationCodeKey(code), value, ttl); err != nil {
+		return result, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to store authorization code")
+	}
+
+	result.Code = code
+
+	return result, nil
+}
+
+func (service *OAuthService) Token(oauthTokenCommand *command.OAuthTokenCommand) (*command.OAuthTokenCommandResult, error) {
+	// Machine clients live apart from the clients users register, so the
+	// client credentials grant authenticates against them instead.
+	if oauthTokenCommand.GrantType == entity.GRANT_TYPE_CLIENT_CREDENTIALS {
+		token, err := service.clientCredentials(oauthTokenCommand)
+		if err != nil {
+			return nil, err
+		}
+
+		return &command.OAuthTokenCommandResult{Result: token}, nil
+	}
+
+	client, err := service.authenticateClient(oauthTokenCommand.ClientId, oauthTokenCommand.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	var token *common.TokenResult
+	switch oauthTokenCommand.GrantType {
+	case entity.GRANT_TYPE_AUTHORIZATION_CODE:
+		token, err = service.exchangeAuthorizationCode(client, oauthTokenCommand)
+	case entity.GRANT_TYPE_REFRESH_TOKEN:
+		token, err = service.refreshToken(client, oauthTokenCommand)
+	default:
+		err = entity.NewOAuthError(entity.OAUTH_UNSUPPORTED_GRANT_TYPE, fmt.Sprintf("grant type %q is not supported", oauthTokenCommand.GrantType))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.OAuthTokenCommandResult{
+		Result: token,
+	}
+
+	return &result, nil
+}
+
+// Introspect is meant for resource servers, so only confidential clients, user
+// registered or machine, may call it.
+func (service *OAuthService) Introspect(introspectCommand *command.IntrospectCommand) (*command.IntrospectCommandResult, error) {
+	if _, err := service.authenticateAnyClient(introspectCommand.ClientId, introspectCommand.ClientSecret, false); err != nil {
+		return nil, err
+	}
+
+	introspection, err := service.tokenService.IntrospectToken(&command.IntrospectTokenCommand{
+		Token:         introspectCommand.Token,
+		TokenTypeHint: introspectCommand.TokenTypeHint,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.IntrospectCommandResult{
+		Result: introspection.Result,
+	}
+
+	return &result, nil
+}
+
+// Revoke lets a client give up a token issued to it. Public clients may revoke
+// their own tokens with only their client id.
+func (service *OAuthService) Revoke(revokeCommand *command.RevokeCommand) error {
+	clientId, err := service.authenticateAnyClient(revokeCommand.ClientId, revokeCommand.ClientSecret, true)
+	if err != nil {
+		return err
+	}
+
+	return service.tokenService.RevokeToken(&command.RevokeTokenCommand{
+		Token:         revokeCommand.Token,
+		TokenTypeHint: revokeCommand.TokenTypeHint,
+		ClientId:      clientId,
+	})
+}
+
+// UserInfo returns the claims about the user that the token was granted. Tokens
+// issued to the user directly are not limited by scope.
+func (service *OAuthService) UserInfo(userInfoCommand *command.UserInfoCommand) (*command.UserInfoCommandResult, error) {
+	user, err := service.userRepository.FindById(userInfoCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := entity.ParseScope(userInfoCommand.Scope)
+	firstParty := userInfoCommand.ClientId == ""
+
+	userInfo := common.UserInfoResult{
+		Subject: user.Id,
+	}
+	if firstParty || slices.Contains(scopes, entity.SCOPE_PROFILE) {
+		userInfo.Name = user.Name
+	}
+	if firstParty || slices.Contains(scopes, entity.SCOPE_EMAIL) {
+		userInfo.Email = user.Email
+		userInfo.EmailVerified = user.EmailVerified
+	}
+
+	result := command.UserInfoCommandResult{
+		Result: &userInfo,
+	}
+
+	return &result, nil
+}
+
+func (service *OAuthService) authenticateClient(clientId string, clientSecret string) (*entity.OAuthClient, error) {
+	client, err := service.oauthClientRepository.FindByClientId(clientId)
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "client authentication failed")
+	}
+
+	if err := verifyClientSecret(client, clientSecret); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func verifyClientSecret(client *entity.OAuthClient, clientSecret string) error {
+	if client.IsPublic() {
+		if clientSecret != "" {
+			return entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "public clients must not send a secret")
+		}
+		return nil
+	}
+
+	if err := util.ComparePwd(clientSecret, client.SecretHash); err != nil {
+		return entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "client authentication failed")
+	}
+
+	return nil
+}
+
+func (service *OAuthService) exchangeAuthorizationCode(client *entity.OAuthClient, oauthTokenCommand *command.OAuthTokenCommand) (*common.TokenResult, error) {
+	authorizationCode, err := service.redeemAuthorizationCode(oauthTokenCommand.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if authorizationCode.IsExpired() || authorizationCode.ClientId != client.ClientId {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "authorization code is invalid or expired")
+	}
+
+	if authorizationCode.RedirectUri != oauthTokenCommand.RedirectUri {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "redirect_uri does not match the authorization request")
+	}
+
+	challenge := util.S256(oauthTokenCommand.CodeVerifier)
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(authorizationCode.CodeChallenge)) != 1 {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "code_verifier does not match the code_challenge")
+	}
+
+	user, err := service.userRepository.FindById(authorizationCode.UserId)
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "resource owner no longer exists")
+	}
+
+	token, err := service.tokenService.IssueToken(&command.IssueTokenCommand{
+		User:      mapper.NewUserResultFromEntity(user),
+		Device:    client.Name,
+		IpAddress: oauthTokenCommand.IpAddress,
+		UserAgent: oauthTokenCommand.UserAgent,
+		ClientId:  client.ClientId,
+		Scope:     authorizationCode.Scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.recordAuthorizationCodeGrant(oauthTokenCommand.Code, user.Id, token.Result.SessionId); err != nil {
+		return nil, err
+	}
+
+	scopes := entity.ParseScope(authorizationCode.Scope)
+	if slices.Contains(scopes, entity.SCOPE_OPENID) {
+		claims := util.IdTokenClaims{
+			Issuer:   service.issuer,
+			Subject:  user.Id,
+			Audience: client.ClientId,
+			Nonce:    authorizationCode.Nonce,
+			AuthTime: authorizationCode.AuthTime,
+		}
+		if slices.Contains(scopes, entity.SCOPE_PROFILE) {
+			claims.Name = user.Name
+		}
+		if slices.Contains(scopes, entity.SCOPE_EMAIL) {
+			claims.Email = user.Email
+			claims.EmailVerified = user.EmailVerified
+		}
+
+		token.Result.IdToken, err = util.GenerateIdToken(claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return token.Result, nil
+}
+
+// redeemAuthorizationCodeScript takes the code out and leaves a marker in its
+// place in one step, so a code is redeemed at most once. Presenting the code
+// again flags the marker and returns the grant it was redeemed for.
+const redeemAuthorizationCodeScript = `
+local redeemed = redis.call('GET', KEYS[2])
+if redeemed then
+	redis.call('SET', KEYS[2], 'reused', 'KEEPTTL')
+	return {'reused', redeemed}
+end
+
+local code = redis.call('GET', KEYS[1])
+if not code then
+	return {'invalid', ''}
+end
+
+redis.call('DEL', KEYS[1])
+redis.call('SET', KEYS[2], 'pending', 'EX', ARGV[1])
+return {'redeemed', code}
+`
+
+// recordAuthorizationCodeGrantScript stores the grant issued for a redeemed
+// code, unless the code was presented again while it was being issued.
+const recordAuthorizationCodeGrantScript = `
+if redis.call('GET', KEYS[1]) ~= 'pending' then
+	return 0
+end
+
+redis.call('SET', KEYS[1], ARGV[1], 'KEEPTTL')
+return 1
+`
+
+// redeemAuthorizationCode consumes the code. A code presented a second time is
+// treated as stolen, as RFC 6749 section 4.1.2 suggests, and the session issued
+// for it is revoked along with its tokens.
+func (service *OAuthService) redeemAuthorizationCode(code string) (*entity.AuthorizationCode, error) {
+	reply, err := service.valkeyRepository.Eval(context.Background(), redeemAuthorizationCodeScript,
+		[]string{authorizationCodeKey(code), redeemedAuthorizationCodeKey(code)}, int(util.REFRESH_TOKEN_DURATION.Seconds()))
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to redeem authorization code")
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to redeem authorization code")
+	}
+	status, _ := values[0].(string)
+	value, _ := values[1].(string)
+
+	switch status {
+	case "redeemed":
+		var authorizationCode entity.AuthorizationCode
+		if err := json.Unmarshal([]byte(value), &authorizationCode); err != nil {
+			return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "authorization code is invalid or expired")
+		}
+		return &authorizationCode, nil
+	case "reused":
+		if userId, sessionId, ok := parseAuthorizationCodeGrant(value); ok {
+			service.sessionService.RevokeSession(&command.RevokeSessionCommand{
+				UserId:    userId,
+				SessionId: sessionId,
+			})
+		}
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "authorization code has already been used")
+	default:
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "authorization code is invalid or expired")
+	}
+}
+
+// recordAuthorizationCodeGrant remembers the session issued for the code so a
+// later reuse can revoke it. When the code was reused in the meantime, the
+// session just issued is revoked instead.
+func (service *OAuthService) recordAuthorizationCodeGrant(code string, userId uuid.UUID, sessionId uuid.UUID) error {
+	reply, err := service.valkeyRepository.Eval(context.Background(), recordAuthorizationCodeGrantScript,
+		[]string{redeemedAuthorizationCodeKey(code)}, fmt.Sprintf("%s:%s", userId, sessionId))
+	if err == nil && reply == int64(1) {
+		return nil
+	}
+
+	service.sessionService.RevokeSession(&command.RevokeSessionCommand{
+		UserId:    userId,
+		SessionId: sessionId,
+	})
+	if err != nil {
+		return entity.NewOAuthError(entity.OAUTH_SERVER_ERROR, "unable to redeem authorization code")
+	}
+	return entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "authorization code has already been used")
+}
+
+func parseAuthorizationCodeGrant(value string) (uuid.UUID, uuid.UUID, bool) {
+	rawUserId, rawSessionId, ok := strings.Cut(value, ":")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+	sessionId, err := uuid.Parse(rawSessionId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userId, sessionId, true
+}
+
+func (service *OAuthService) refreshToken(client *entity.OAuthClient, oauthTokenCommand *command.OAuthTokenCommand) (*common.TokenResult, error) {
+	token, err := service.tokenService.RefreshToken(&command.RefreshTokenCommand{
+		RefreshToken: oauthTokenCommand.RefreshToken,
+		ClientId:     client.ClientId,
+	})
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_GRANT, "refresh token is invalid, expired or revoked")
+	}
+
+	return token.Result, nil
+}
+
+func (service *OAuthService) authenticateMachineClient(clientId string, clientSecret string) (*entity.MachineClient, error) {
+	client, err := service.machineClientRepository.FindByClientId(clientId)
+	if err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "client authentication failed")
+	}
+
+	if err := util.ComparePwd(clientSecret, client.SecretHash); err != nil {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "client authentication failed")
+	}
+
+	return client, nil
+}
+
+// authenticateAnyClient authenticates either a client registered by a user or
+// a machine client, returning its client id.
+func (service *OAuthService) authenticateAnyClient(clientId string, clientSecret string, allowPublic bool) (string, error) {
+	if client, err := service.oauthClientRepository.FindByClientId(clientId); err == nil {
+		if client.IsPublic() && !allowPublic {
+			return "", entity.NewOAuthError(entity.OAUTH_INVALID_CLIENT, "public clients are not allowed")
+		}
+		if err := verifyClientSecret(client, clientSecret); err != nil {
+			return "", err
+		}
+		return client.ClientId, nil
+	}
+
+	client, err := service.authenticateMachineClient(clientId, clientSecret)
+	if err != nil {
+		return "", err
+	}
+
+	return client.ClientId, nil
+}
+
+func (service *OAuthService) clientCredentials(oauthTokenCommand *command.OAuthTokenCommand) (*common.TokenResult, error) {
+	client, err := service.authenticateMachineClient(oauthTokenCommand.ClientId, oauthTokenCommand.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a scope parameter the client gets every scope it is allowed.
+	scopes := entity.ParseScope(oauthTokenCommand.Scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	if !client.AllowsScopes(scopes) {
+		return nil, entity.NewOAuthError(entity.OAUTH_INVALID_SCOPE, "requested scope is not allowed for this client")
+	}
+
+	token, err := service.tokenService.IssueMachineToken(&command.IssueMachineTokenCommand{
+		Client: mapper.NewMachineClientResultFromEntity(client),
+		Scope:  strings.Join(scopes, " "),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token.Result, nil
+}
+
+func authorizationCodeKey(code string) string {
+	return fmt.Sprintf("%s:%s", entity.AUTHORIZATION_CODE, code)
+}
+
+func oauthConsentRequestKey(consentId string) string {
+	return fmt.Sprintf("%s:%s", entity.OAUTH_CONSENT_REQUEST, consentId)
+}
+
+func redeemedAuthorizationCodeKey(code string) string {
+	return fmt.Sprintf("%s:%s:redeemed", entity.AUTHORIZATION_CODE, code)
+}
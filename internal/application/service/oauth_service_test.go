@@ -0,0 +1,860 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOAuthService_RegisterClient(t *testing.T) {
+	ownerId := uuid.New()
+
+	t.Run("success: confidential client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		var storedSecretHash string
+		mockOAuthClientRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(client *entity.ValidatedOAuthClient) (*entity.OAuthClient, error) {
+				storedSecretHash = client.SecretHash
+				return &client.OAuthClient, nil
+			})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.RegisterClient(&command.RegisterOAuthClientCommand{
+			OwnerId:      ownerId,
+			Name:         "Example App",
+			RedirectUris: []string{"https://example.com/callback"},
+			Scopes:       []string{entity.SCOPE_PROFILE},
+		})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Result.ClientId)
+		assert.False(t, result.Result.Public)
+		assert.NotEmpty(t, result.ClientSecret)
+		assert.NoError(t, util.ComparePwd(result.ClientSecret, storedSecretHash))
+	})
+
+	t.Run("failure: insecure redirect uri", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		_, err := service.RegisterClient(&command.RegisterOAuthClientCommand{
+			OwnerId:      ownerId,
+			Name:         "Example App",
+			RedirectUris: []string{"http://example.com/callback"},
+			Public:       true,
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOAuthService_Authorize(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	client := entity.NewOAuthClient(uuid.New(), "client-id", "", "Example App", []string{"https://example.com/callback"}, []string{entity.SCOPE_OPENID, entity.SCOPE_PROFILE, entity.SCOPE_EMAIL})
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	consent := entity.NewOAuthConsent(user.Id, client.ClientId, []string{entity.SCOPE_OPENID, entity.SCOPE_PROFILE, entity.SCOPE_EMAIL})
+
+	authorizeCommand := func() *command.AuthorizeCommand {
+		return &command.AuthorizeCommand{
+			UserId:              user.Id,
+			SessionId:           session.Id,
+			ResponseType:        "code",
+			ClientId:            client.ClientId,
+			RedirectUri:         "https://example.com/callback",
+			Scope:               "openid profile email",
+			State:               "state",
+			CodeChallenge:       util.S256("verifier"),
+			CodeChallengeMethod: "S256",
+			Nonce:               "nonce",
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+		mockOAuthConsentRepo.EXPECT().FindByUserIdAndClientId(user.Id, client.ClientId).Return(consent, nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+
+		var storedKey string
+		var storedCode entity.AuthorizationCode
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), gomock.Any(), gomock.Any(), 60).
+			DoAndReturn(func(_ any, key string, value any, _ int) error {
+				storedKey = key
+				return json.Unmarshal(value.([]byte), &storedCode)
+			})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.Authorize(authorizeCommand())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "state", result.State)
+		assert.Equal(t, fmt.Sprintf("%s:%s", entity.AUTHORIZATION_CODE, result.Code), storedKey)
+		assert.Equal(t, user.Id, storedCode.UserId)
+		assert.Equal(t, "openid profile email", storedCode.Scope)
+		assert.Equal(t, "nonce", storedCode.Nonce)
+		assert.True(t, session.CreatedAt.Equal(storedCode.AuthTime))
+	})
+
+	t.Run("success: asks for consent to new scopes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		partialConsent := entity.NewOAuthConsent(user.Id, client.ClientId, []string{entity.SCOPE_OPENID})
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+		mockOAuthConsentRepo.EXPECT().FindByUserIdAndClientId(user.Id, client.ClientId).Return(partialConsent, nil)
+
+		var storedKey string
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), gomock.Any(), gomock.Any(), 600).
+			DoAndReturn(func(_ any, key string, _ any, _ int) error {
+				storedKey = key
+				return nil
+			})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.Authorize(authorizeCommand())
+
+		assert.NoError(t, err)
+		assert.True(t, result.ConsentRequired)
+		assert.Empty(t, result.Code)
+		assert.Equal(t, "Example App", result.ClientName)
+		assert.Equal(t, []string{entity.SCOPE_OPENID, entity.SCOPE_PROFILE, entity.SCOPE_EMAIL}, result.Scopes)
+		assert.Equal(t, fmt.Sprintf("%s:%s", entity.OAUTH_CONSENT_REQUEST, result.ConsentId), storedKey)
+	})
+
+	t.Run("failure: unregistered redirect uri", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		cmd := authorizeCommand()
+		cmd.RedirectUri = "https://attacker.example/callback"
+
+		result, err := service.Authorize(cmd)
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+
+	t.Run("failure: missing code challenge", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		cmd := authorizeCommand()
+		cmd.CodeChallenge = ""
+
+		result, err := service.Authorize(cmd)
+
+		assert.NotNil(t, result)
+		assert.Equal(t, "https://example.com/callback", result.RedirectUri)
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_REQUEST, oauthErr.Code)
+	})
+}
+
+func TestOAuthService_Consent(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	client := entity.NewOAuthClient(uuid.New(), "client-id", "", "Example App", []string{"https://example.com/callback"}, []string{entity.SCOPE_OPENID, entity.SCOPE_PROFILE})
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	consentRequestKey := []string{fmt.Sprintf("%s:%s", entity.OAUTH_CONSENT_REQUEST, "consent-id")}
+	consentRequest, _ := json.Marshal(&command.AuthorizeCommand{
+		UserId:              user.Id,
+		SessionId:           session.Id,
+		ResponseType:        "code",
+		ClientId:            client.ClientId,
+		RedirectUri:         "https://example.com/callback",
+		Scope:               "openid profile",
+		State:               "state",
+		CodeChallenge:       util.S256("verifier"),
+		CodeChallengeMethod: "S256",
+	})
+
+	consentCommand := func(approved bool) *command.OAuthConsentCommand {
+		return &command.OAuthConsentCommand{
+			UserId:    user.Id,
+			SessionId: session.Id,
+			ConsentId: "consent-id",
+			Approved:  approved,
+		}
+	}
+
+	t.Run("success: approved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), consentRequestKey).Return(string(consentRequest), nil)
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+		mockOAuthConsentRepo.EXPECT().FindByUserIdAndClientId(user.Id, client.ClientId).Return(nil, errors.New("record not found"))
+
+		var savedConsent *entity.ValidatedOAuthConsent
+		mockOAuthConsentRepo.EXPECT().
+			Save(gomock.Any()).
+			DoAndReturn(func(consent *entity.ValidatedOAuthConsent) (*entity.OAuthConsent, error) {
+				savedConsent = consent
+				return &consent.OAuthConsent, nil
+			})
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), 60).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.Consent(consentCommand(true))
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Code)
+		assert.Equal(t, "state", result.State)
+		assert.Equal(t, user.Id, savedConsent.UserId)
+		assert.Equal(t, []string{entity.SCOPE_OPENID, entity.SCOPE_PROFILE}, savedConsent.Scopes)
+	})
+
+	t.Run("failure: denied", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), consentRequestKey).Return(string(consentRequest), nil)
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.Consent(consentCommand(false))
+
+		assert.Equal(t, "https://example.com/callback", result.RedirectUri)
+		assert.Empty(t, result.Code)
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_ACCESS_DENIED, oauthErr.Code)
+	})
+
+	t.Run("failure: asked of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), consentRequestKey).Return(string(consentRequest), nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		cmd := consentCommand(true)
+		cmd.UserId = uuid.New()
+
+		result, err := service.Consent(cmd)
+
+		assert.Nil(t, result)
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_REQUEST, oauthErr.Code)
+	})
+}
+
+func TestOAuthService_Token(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	secretHash, _ := util.HashPwd("client-secret")
+	client := entity.NewOAuthClient(uuid.New(), "client-id", secretHash, "Example App", []string{"https://example.com/callback"}, []string{entity.SCOPE_OPENID, entity.SCOPE_PROFILE})
+
+	authTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	authorizationCode := entity.NewAuthorizationCode("code", client.ClientId, user.Id, "https://example.com/callback", "openid profile", util.S256("verifier"), "nonce", authTime, entity.AUTHORIZATION_CODE_DURATION)
+	authorizationCodeValue, _ := json.Marshal(authorizationCode)
+	authorizationCodeKeys := []string{fmt.Sprintf("%s:%s", entity.AUTHORIZATION_CODE, "code"), fmt.Sprintf("%s:%s:redeemed", entity.AUTHORIZATION_CODE, "code")}
+	redeemedKeys := authorizationCodeKeys[1:]
+
+	tokenCommand := func() *command.OAuthTokenCommand {
+		return &command.OAuthTokenCommand{
+			GrantType:    entity.GRANT_TYPE_AUTHORIZATION_CODE,
+			ClientId:     client.ClientId,
+			ClientSecret: "client-secret",
+			Code:         "code",
+			RedirectUri:  "https://example.com/callback",
+			CodeVerifier: "verifier",
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), authorizationCodeKeys, 2*60*60).
+			Return([]interface{}{"redeemed", string(authorizationCodeValue)}, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockValkeyRepo.EXPECT().HSet(gomock.Any(), sessionKey, gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), sessionKey, 2*60*60).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), 2*60*60).Return(nil)
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), redeemedKeys, gomock.Any()).Return(int64(1), nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.Token(tokenCommand())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "openid profile", result.Result.Scope)
+
+		claims, err := util.ValidateAccessToken(result.Result.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, claims.Id)
+		assert.Equal(t, client.ClientId, claims.ClientId)
+		assert.Equal(t, "openid profile", claims.Scope)
+
+		keyring := util.CurrentKeyring()
+		idToken, err := jwt.Parse(result.Result.IdToken, keyring.Keyfunc, jwt.WithValidMethods(keyring.ValidMethods()))
+		assert.NoError(t, err)
+
+		idClaims := idToken.Claims.(jwt.MapClaims)
+		assert.Equal(t, "https://auth.example.com", idClaims["iss"])
+		assert.Equal(t, user.Id.String(), idClaims["sub"])
+		assert.Equal(t, client.ClientId, idClaims["aud"])
+		assert.Equal(t, "nonce", idClaims["nonce"])
+		assert.Equal(t, float64(authTime.Unix()), idClaims["auth_time"])
+		assert.Equal(t, user.Name, idClaims["name"])
+		assert.NotContains(t, idClaims, "email")
+	})
+
+	t.Run("failure: code verifier mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), authorizationCodeKeys, 2*60*60).
+			Return([]interface{}{"redeemed", string(authorizationCodeValue)}, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		cmd := tokenCommand()
+		cmd.CodeVerifier = "wrong-verifier"
+
+		_, err := service.Token(cmd)
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_GRANT, oauthErr.Code)
+	})
+
+	t.Run("failure: code reused revokes the issued session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		session := entity.NewSession(user.Id, client.Name, "127.0.0.1", "test-agent")
+		sessionValue, _ := json.Marshal(session)
+		sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), authorizationCodeKeys, 2*60*60).
+			Return([]interface{}{"reused", fmt.Sprintf("%s:%s", user.Id, session.Id)}, nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, session.Id.String()).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		_, err := service.Token(tokenCommand())
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_GRANT, oauthErr.Code)
+	})
+
+	t.Run("failure: code reused while issuing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), authorizationCodeKeys, 2*60*60).
+			Return([]interface{}{"redeemed", string(authorizationCodeValue)}, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+
+		var sessionValue string
+		mockValkeyRepo.EXPECT().HSet(gomock.Any(), sessionKey, gomock.Any()).
+			DoAndReturn(func(_ any, _ string, values map[string]interface{}) error {
+				for _, value := range values {
+					sessionValue = string(value.([]byte))
+				}
+				return nil
+			})
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), sessionKey, 2*60*60).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), 2*60*60).Return(nil)
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), redeemedKeys, gomock.Any()).Return(int64(0), nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, gomock.Any()).DoAndReturn(func(_ any, _ string, _ string) (string, error) {
+			return sessionValue, nil
+		})
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, gomock.Any()).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		_, err := service.Token(tokenCommand())
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_GRANT, oauthErr.Code)
+	})
+
+	t.Run("failure: wrong client secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		cmd := tokenCommand()
+		cmd.ClientSecret = "wrong-secret"
+
+		_, err := service.Token(cmd)
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_CLIENT, oauthErr.Code)
+	})
+}
+
+func TestOAuthService_ClientCredentials(t *testing.T) {
+	secretHash, _ := util.HashPwd("client-secret")
+	client := entity.NewMachineClient("machine-client-id", secretHash, "billing-service", []string{entity.SCOPE_USERS_READ})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockMachineClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.Token(&command.OAuthTokenCommand{
+			GrantType:    entity.GRANT_TYPE_CLIENT_CREDENTIALS,
+			ClientId:     client.ClientId,
+			ClientSecret: "client-secret",
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Result.RefreshToken)
+		assert.Equal(t, entity.SCOPE_USERS_READ, result.Result.Scope)
+
+		claims, err := util.ValidateAccessToken(result.Result.AccessToken)
+		assert.NoError(t, err)
+		assert.True(t, claims.IsMachine())
+		assert.Equal(t, client.Id, claims.Id)
+		assert.Equal(t, client.ClientId, claims.ClientId)
+	})
+
+	t.Run("failure: scope not allowed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockMachineClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		_, err := service.Token(&command.OAuthTokenCommand{
+			GrantType:    entity.GRANT_TYPE_CLIENT_CREDENTIALS,
+			ClientId:     client.ClientId,
+			ClientSecret: "client-secret",
+			Scope:        "users:read users:write",
+		})
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_SCOPE, oauthErr.Code)
+	})
+
+	t.Run("failure: wrong client secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockMachineClientRepo.EXPECT().FindByClientId(client.ClientId).Return(client, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		_, err := service.Token(&command.OAuthTokenCommand{
+			GrantType:    entity.GRANT_TYPE_CLIENT_CREDENTIALS,
+			ClientId:     client.ClientId,
+			ClientSecret: "wrong-secret",
+		})
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_CLIENT, oauthErr.Code)
+	})
+}
+
+func TestOAuthService_Introspect(t *testing.T) {
+	publicClient := entity.NewOAuthClient(uuid.New(), "public-client-id", "", "Example App", []string{"https://example.com/callback"}, []string{entity.SCOPE_OPENID})
+
+	secretHash, _ := util.HashPwd("client-secret")
+	machineClient := entity.NewMachineClient("machine-client-id", secretHash, "billing-service", []string{entity.SCOPE_USERS_READ})
+
+	t.Run("success: invalid token is inactive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(machineClient.ClientId).Return(nil, errors.New("record not found"))
+		mockMachineClientRepo.EXPECT().FindByClientId(machineClient.ClientId).Return(machineClient, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.Introspect(&command.IntrospectCommand{
+			ClientId:     machineClient.ClientId,
+			ClientSecret: "client-secret",
+			Token:        "not-a-token",
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Result.Active)
+	})
+
+	t.Run("failure: public client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockOAuthClientRepo.EXPECT().FindByClientId(publicClient.ClientId).Return(publicClient, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		_, err := service.Introspect(&command.IntrospectCommand{
+			ClientId: publicClient.ClientId,
+			Token:    "not-a-token",
+		})
+
+		var oauthErr *entity.OAuthError
+		assert.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, entity.OAUTH_INVALID_CLIENT, oauthErr.Code)
+	})
+}
+
+func TestOAuthService_UserInfo(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	t.Run("success: limited to granted scopes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		result, err := service.UserInfo(&command.UserInfoCommand{
+			UserId:   user.Id,
+			ClientId: "client-id",
+			Scope:    "openid email",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Subject)
+		assert.Equal(t, user.Email, result.Result.Email)
+		assert.Empty(t, result.Result.Name)
+	})
+
+	t.Run("failure: user not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockOAuthClientRepo := mocks.NewMockOAuthClientRepository(ctrl)
+		mockOAuthConsentRepo := mocks.NewMockOAuthConsentRepository(ctrl)
+		mockMachineClientRepo := mocks.NewMockMachineClientRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(nil, errors.New("record not found"))
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		tokenService := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+		service := service.NewOAuthService(mockValkeyRepo, mockUserRepo, mockOAuthClientRepo, mockOAuthConsentRepo, mockMachineClientRepo, sessionService, tokenService, "https://auth.example.com")
+
+		_, err := service.UserInfo(&command.UserInfoCommand{
+			UserId: user.Id,
+		})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const DEFAULT_PASSKEY_NAME = "Passkey"
+
+type PasskeyService struct {
+	valkeyRepository        repository.ValkeyRepository
+	userRepository          repository.UserRepository
+	passkeyRepository       repository.PasskeyRepository
+	auditEventRepository    repository.AuditEventRepository
+	relyingParty            util.WebauthnRelyingParty
+	emailVerificationPolicy string
+}
+
+func NewPasskeyService(valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository, passkeyRepository repository.PasskeyRepository, auditEventRepository repository.AuditEventRepository, relyingParty util.WebauthnRelyingParty, emailVerificationPolicy string) *PasskeyService {
+	return &PasskeyService{
+		valkeyRepository:        valkeyRepository,
+		userRepository:          userRepository,
+		passkeyRepository:       passkeyRepository,
+		auditEventRepository:    auditEventRepository,
+		relyingParty:            relyingParty,
+		emailVerificationPolicy: emailVerificationPolicy,
+	}
+}
+
+func (service *PasskeyService) BeginPasskeyRegistration(beginPasskeyRegistrationCommand *command.BeginPasskeyRegistrationCommand) (*command.BeginPasskeyRegistrationCommandResult, error) {
+	user, err := service.userRepository.FindById(beginPasskeyRegistrationCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := service.passkeyRepository.FindAllByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := int(entity.WEBAUTHN_CEREMONY_DURATION.Seconds())
+	if err := service.valkeyRepository.Set(context.Background(), passkeyRegistrationKey(user.Id), challenge, ttl); err != nil {
+		return nil, err
+	}
+
+	// Listing the existing credentials keeps an authenticator from being
+	// registered twice.
+	excludeCredentials := make([]*common.PasskeyResult, len(passkeys))
+	for i, passkey := range passkeys {
+		excludeCredentials[i] = mapper.NewPasskeyResultFromEntity(passkey)
+	}
+
+	result := command.BeginPasskeyRegistrationCommandResult{
+		Result: &common.PasskeyRegistrationOptionsResult{
+			Challenge:          challenge,
+			RelyingPartyId:     service.relyingParty.Id,
+			RelyingPartyName:   service.relyingParty.Name,
+			UserHandle:         user.Id[:],
+			UserName:           user.Email,
+			UserDisplayName:    user.Name,
+			Algorithms:         util.COSE_ALGORITHMS,
+			Timeout:            entity.WEBAUTHN_CEREMONY_DURATION,
+			ExcludeCredentials: excludeCredentials,
+		},
+	}
+
+	return &result, nil
+}
+
+func (service *PasskeyService) FinishPasskeyRegistration(finishPasskeyRegistrationCommand *command.FinishPasskeyRegistrationCommand) (*command.FinishPasskeyRegistrationCommandResult, error) {
+	ctx := context.Background()
+	key := passkeyRegistrationKey(finishPasskeyRegistrationCommand.UserId)
+
+	challenge, err := service.valkeyRepository.Get(ctx, key)
+	if err != nil || challenge == "" {
+		return nil, entity.ErrPasskeyCeremonyExpired
+	}
+	service.valkeyRepository.Delete(ctx, key)
+
+	credential, err := util.VerifyWebauthnRegistration(service.relyingParty, challenge, finishPasskeyRegistrationCommand.ClientDataJSON, finishPasskeyRegistrationCommand.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(credential.CredentialId, finishPasskeyRegistrationCommand.CredentialId) {
+		return nil, fmt.Errorf("credential id does not match the attested credential")
+	}
+
+	if _, err := service.passkeyRepository.FindByCredentialId(credential.CredentialId); err == nil {
+		return nil, entity.ErrPasskeyAlreadyRegistered
+	}
+
+	name := finishPasskeyRegistrationCommand.Name
+	if name == "" {
+		name = DEFAULT_PASSKEY_NAME
+	}
+
+	passkeyEntity := entity.NewPasskey(finishPasskeyRegistrationCommand.UserId, name, credential.CredentialId, credential.PublicKey, credential.SignCount, finishPasskeyRegistrationCommand.Transports)
+
+	validatedPasskey, err := entity.NewValidatedPasskey(passkeyEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, err := service.passkeyRepository.Create(validatedPasskey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.FinishPasskeyRegistrationCommandResult{
+		Result: mapper.NewPasskeyResultFromEntity(passkey),
+	}
+
+	return &result, nil
+}
+
+// BeginPasskeyLogin does not ask who is signing in: passkeys are discoverable
+// credentials, so the authenticator tells us with its response.
+func (service *PasskeyService) BeginPasskeyLogin() (*command.BeginPasskeyLoginCommandResult, error) {
+	challenge, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := int(entity.WEBAUTHN_CEREMONY_DURATION.Seconds())
+	if err := service.valkeyRepository.Set(context.Background(), passkeyLoginKey(challenge), "1", ttl); err != nil {
+		return nil, err
+	}
+
+	result := command.BeginPasskeyLoginCommandResult{
+		Result: &common.PasskeyLoginOptionsResult{
+			Challenge:      challenge,
+			RelyingPartyId: service.relyingParty.Id,
+			Timeout:        entity.WEBAUTHN_CEREMONY_DURATION,
+		},
+	}
+
+	return &result, nil
+}
+
+// FinishPasskeyLogin verifies the assertion. Authenticators are required to
+// verify the user, so no other factor is asked for.
+func (service *PasskeyService) FinishPasskeyLogin(finishPasskeyLoginCommand *command.FinishPasskeyLoginCommand) (_ *command.FinishPasskeyLoginCommandResult, err error) {
+	audit := entity.NewAuditEvent(entity.AUDIT_ACTION_LOGIN_PASSKEY, "", finishPasskeyLoginCommand.IpAddress, finishPasskeyLoginCommand.UserAgent)
+	defer func() { recordLoginAudit(service.auditEventRepository, audit, err) }()
+
+	clientData, err := util.ParseWebauthnClientData(finishPasskeyLoginCommand.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// The challenge is stored as 1 and consumed by incrementing it, which gives
+	// 2 exactly once even for concurrent requests, and 1 if it was never issued.
+	ctx := context.Background()
+	key := passkeyLoginKey(clientData.Challenge)
+	count, err := service.valkeyRepository.Increment(ctx, key)
+	service.valkeyRepository.Delete(ctx, key)
+	if err != nil || count != 2 {
+		return nil, entity.ErrPasskeyCeremonyExpired
+	}
+
+	passkey, err := service.passkeyRepository.FindByCredentialId(finishPasskeyLoginCommand.CredentialId)
+	if err != nil {
+		audit.Reason = "unknown passkey"
+		return nil, entity.ErrPasskeyNotFound
+	}
+	audit.UserId = &passkey.UserId
+
+	if len(finishPasskeyLoginCommand.UserHandle) > 0 && !bytes.Equal(finishPasskeyLoginCommand.UserHandle, passkey.UserId[:]) {
+		return nil, entity.ErrPasskeyNotFound
+	}
+
+	signCount, err := util.VerifyWebauthnAssertion(service.relyingParty, clientData.Challenge, finishPasskeyLoginCommand.ClientDataJSON, finishPasskeyLoginCommand.AuthenticatorData, finishPasskeyLoginCommand.Signature, passkey.PublicKey, passkey.SignCount)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey.Use(signCount)
+	validatedPasskey, err := entity.NewValidatedPasskey(passkey)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := service.passkeyRepository.Update(validatedPasskey); err != nil {
+		return nil, err
+	}
+
+	user, err := service.userRepository.FindById(passkey.UserId)
+	if err != nil {
+		return nil, err
+	}
+	audit.ActedBy(user)
+
+	if err := user.SignInError(); err != nil {
+		return nil, err
+	}
+
+	if !user.EmailVerified && service.emailVerificationPolicy == entity.EMAIL_VERIFICATION_BLOCK {
+		return nil, entity.ErrEmailNotVerified
+	}
+
+	result := command.FinishPasskeyLoginCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+func (service *PasskeyService) ListPasskeys(listPasskeysCommand *command.ListPasskeysCommand) (*command.ListPasskeysCommandResult, error) {
+	passkeys, err := service.passkeyRepository.FindAllByUserId(listPasskeysCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*common.PasskeyResult, len(passkeys))
+	for i, passkey := range passkeys {
+		results[i] = mapper.NewPasskeyResultFromEntity(passkey)
+	}
+
+	result := command.ListPasskeysCommandResult{
+		Result: results,
+	}
+
+	return &result, nil
+}
+
+func (service *PasskeyService) DeletePasskey(deletePasskeyCommand *command.DeletePasskeyCommand) error {
+	passkey, err := service.passkeyRepository.FindById(deletePasskeyCommand.Id)
+	if err != nil || passkey.UserId != deletePasskeyCommand.UserId {
+		return entity.ErrPasskeyNotFound
+	}
+
+	return service.passkeyRepository.Delete(passkey.Id)
+}
+
+func passkeyRegistrationKey(userId uuid.UUID) string {
+	return fmt.Sprintf("user:%s:%s", userId, entity.WEBAUTHN_REGISTRATION)
+}
+
+func passkeyLoginKey(challenge string) string {
+	return fmt.Sprintf("%s:%s", entity.WEBAUTHN_LOGIN, challenge)
+}
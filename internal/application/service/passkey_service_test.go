@@ -0,0 +1,467 @@
+package service_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var relyingParty = util.WebauthnRelyingParty{
+	Id:      "example.com",
+	Name:    "Example",
+	Origins: []string{"https://example.com"},
+}
+
+// passkeyAuthenticator plays an Ed25519 authenticator, with its CBOR written
+// out by hand.
+type passkeyAuthenticator struct {
+	credentialId []byte
+	private      ed25519.PrivateKey
+	coseKey      []byte
+}
+
+func newPasskeyAuthenticator() *passkeyAuthenticator {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	coseKey := append([]byte{0xa4, 0x01, 0x01, 0x03, 0x27, 0x20, 0x06, 0x21, 0x58, 0x20}, public...)
+
+	return &passkeyAuthenticator{
+		credentialId: []byte("credential-id"),
+		private:      private,
+		coseKey:      coseKey,
+	}
+}
+
+func (a *passkeyAuthenticator) authData(attested bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(relyingParty.Id))
+	flags := byte(0x05)
+	if attested {
+		flags |= 0x40
+	}
+	out := append(append([]byte(nil), rpIdHash[:]...), flags, 0, 0, 0, 0)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credentialId)))
+		out = append(out, a.credentialId...)
+		out = append(out, a.coseKey...)
+	}
+	return out
+}
+
+func passkeyClientData(ceremony string, challenge string) []byte {
+	raw, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    "https://example.com",
+	})
+	return raw
+}
+
+func (a *passkeyAuthenticator) attestationObject() []byte {
+	authData := a.authData(true)
+	out := []byte{0xa3, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0}
+	out = append(out, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a', 0x58, byte(len(authData)))
+	return append(out, authData...)
+}
+
+func (a *passkeyAuthenticator) assertion(challenge string) ([]byte, []byte, []byte) {
+	clientDataJSON := passkeyClientData(util.WEBAUTHN_GET, challenge)
+	authData := a.authData(false)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signature := ed25519.Sign(a.private, append(append([]byte(nil), authData...), clientDataHash[:]...))
+	return clientDataJSON, authData, signature
+}
+
+func TestPasskeyService_BeginPasskeyRegistration(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	registrationKey := fmt.Sprintf("user:%s:%s", user.Id, entity.WEBAUTHN_REGISTRATION)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		existing := entity.NewPasskey(user.Id, "Laptop", []byte("existing"), []byte("key"), 0, []string{"internal"})
+
+		var storedChallenge string
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockPasskeyRepo.EXPECT().FindAllByUserId(user.Id).Return([]*entity.Passkey{existing}, nil)
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), registrationKey, gomock.Any(), 5*60).
+			DoAndReturn(func(_ any, _ string, value any, _ int) error {
+				storedChallenge = value.(string)
+				return nil
+			})
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		result, err := service.BeginPasskeyRegistration(&command.BeginPasskeyRegistrationCommand{
+			UserId: user.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, storedChallenge, result.Result.Challenge)
+		assert.Equal(t, "example.com", result.Result.RelyingPartyId)
+		assert.Equal(t, user.Id[:], result.Result.UserHandle)
+		assert.Equal(t, user.Email, result.Result.UserName)
+		assert.Len(t, result.Result.ExcludeCredentials, 1)
+	})
+}
+
+func TestPasskeyService_FinishPasskeyRegistration(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	registrationKey := fmt.Sprintf("user:%s:%s", user.Id, entity.WEBAUTHN_REGISTRATION)
+	authenticator := newPasskeyAuthenticator()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), registrationKey).Return("challenge", nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), registrationKey).Return(nil)
+		mockPasskeyRepo.EXPECT().FindByCredentialId(authenticator.credentialId).Return(nil, errors.New("record not found"))
+		mockPasskeyRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(passkey *entity.ValidatedPasskey) (*entity.Passkey, error) {
+				assert.Equal(t, user.Id, passkey.UserId)
+				assert.Equal(t, authenticator.coseKey, passkey.PublicKey)
+				return &passkey.Passkey, nil
+			})
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		result, err := service.FinishPasskeyRegistration(&command.FinishPasskeyRegistrationCommand{
+			UserId:            user.Id,
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    passkeyClientData(util.WEBAUTHN_CREATE, "challenge"),
+			AttestationObject: authenticator.attestationObject(),
+			Transports:        []string{"internal"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Passkey", result.Result.Name)
+		assert.Equal(t, []string{"internal"}, result.Result.Transports)
+	})
+
+	t.Run("failure: challenge expired", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), registrationKey).Return("", errors.New("valkey nil message"))
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		result, err := service.FinishPasskeyRegistration(&command.FinishPasskeyRegistrationCommand{
+			UserId:            user.Id,
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    passkeyClientData(util.WEBAUTHN_CREATE, "challenge"),
+			AttestationObject: authenticator.attestationObject(),
+		})
+
+		assert.ErrorIs(t, err, entity.ErrPasskeyCeremonyExpired)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: answered another challenge", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), registrationKey).Return("challenge", nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), registrationKey).Return(nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		_, err := service.FinishPasskeyRegistration(&command.FinishPasskeyRegistrationCommand{
+			UserId:            user.Id,
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    passkeyClientData(util.WEBAUTHN_CREATE, "other-challenge"),
+			AttestationObject: authenticator.attestationObject(),
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPasskeyService_FinishPasskeyLogin(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	loginKey := fmt.Sprintf("%s:%s", entity.WEBAUTHN_LOGIN, "challenge")
+	authenticator := newPasskeyAuthenticator()
+	passkey := entity.NewPasskey(user.Id, "Laptop", authenticator.credentialId, authenticator.coseKey, 0, nil)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), loginKey).Return(int64(2), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), loginKey).Return(nil)
+		mockPasskeyRepo.EXPECT().FindByCredentialId(authenticator.credentialId).Return(passkey, nil)
+		mockPasskeyRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(passkey *entity.ValidatedPasskey) (*entity.Passkey, error) {
+				assert.NotNil(t, passkey.LastUsedAt)
+				return &passkey.Passkey, nil
+			})
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		clientDataJSON, authData, signature := authenticator.assertion("challenge")
+		result, err := service.FinishPasskeyLogin(&command.FinishPasskeyLoginCommand{
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        user.Id[:],
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Id)
+	})
+
+	t.Run("failure: email not verified", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), loginKey).Return(int64(2), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), loginKey).Return(nil)
+		mockPasskeyRepo.EXPECT().FindByCredentialId(authenticator.credentialId).Return(passkey, nil)
+		mockPasskeyRepo.EXPECT().Update(gomock.Any()).Return(passkey, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockAuditRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(audit *entity.ValidatedAuditEvent) (*entity.AuditEvent, error) {
+				assert.Equal(t, entity.AUDIT_ACTION_LOGIN_PASSKEY, audit.Action)
+				assert.Equal(t, entity.AUDIT_OUTCOME_FAILURE, audit.Outcome)
+				assert.Equal(t, &user.Id, audit.ActorId)
+				return &audit.AuditEvent, nil
+			})
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_BLOCK)
+
+		clientDataJSON, authData, signature := authenticator.assertion("challenge")
+		_, err := service.FinishPasskeyLogin(&command.FinishPasskeyLoginCommand{
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailNotVerified)
+	})
+
+	t.Run("failure: user disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		disabledUser := *user
+		disabledUser.Disable()
+
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), loginKey).Return(int64(2), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), loginKey).Return(nil)
+		mockPasskeyRepo.EXPECT().FindByCredentialId(authenticator.credentialId).Return(passkey, nil)
+		mockPasskeyRepo.EXPECT().Update(gomock.Any()).Return(passkey, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&disabledUser, nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		clientDataJSON, authData, signature := authenticator.assertion("challenge")
+		_, err := service.FinishPasskeyLogin(&command.FinishPasskeyLoginCommand{
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrUserDisabled)
+	})
+
+	t.Run("failure: challenge was not issued", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), loginKey).Return(int64(1), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), loginKey).Return(nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		clientDataJSON, authData, signature := authenticator.assertion("challenge")
+		result, err := service.FinishPasskeyLogin(&command.FinishPasskeyLoginCommand{
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrPasskeyCeremonyExpired)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: user handle of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), loginKey).Return(int64(2), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), loginKey).Return(nil)
+		mockPasskeyRepo.EXPECT().FindByCredentialId(authenticator.credentialId).Return(passkey, nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		otherUserId := uuid.New()
+		clientDataJSON, authData, signature := authenticator.assertion("challenge")
+		_, err := service.FinishPasskeyLogin(&command.FinishPasskeyLoginCommand{
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        otherUserId[:],
+		})
+
+		assert.ErrorIs(t, err, entity.ErrPasskeyNotFound)
+	})
+
+	t.Run("failure: invalid signature", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		mockValkeyRepo.EXPECT().Increment(gomock.Any(), loginKey).Return(int64(2), nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), loginKey).Return(nil)
+		mockPasskeyRepo.EXPECT().FindByCredentialId(authenticator.credentialId).Return(passkey, nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		clientDataJSON, authData, _ := authenticator.assertion("challenge")
+		_, _, signature := newPasskeyAuthenticator().assertion("challenge")
+		_, err := service.FinishPasskeyLogin(&command.FinishPasskeyLoginCommand{
+			CredentialId:      authenticator.credentialId,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authData,
+			Signature:         signature,
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPasskeyService_DeletePasskey(t *testing.T) {
+	userId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		passkey := entity.NewPasskey(userId, "Laptop", []byte("credential-id"), []byte("key"), 0, nil)
+
+		mockPasskeyRepo.EXPECT().FindById(passkey.Id).Return(passkey, nil)
+		mockPasskeyRepo.EXPECT().Delete(passkey.Id).Return(nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		err := service.DeletePasskey(&command.DeletePasskeyCommand{
+			UserId: userId,
+			Id:     passkey.Id,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: passkey of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).AnyTimes()
+
+		passkey := entity.NewPasskey(uuid.New(), "Laptop", []byte("credential-id"), []byte("key"), 0, nil)
+
+		mockPasskeyRepo.EXPECT().FindById(passkey.Id).Return(passkey, nil)
+
+		service := service.NewPasskeyService(mockValkeyRepo, mockUserRepo, mockPasskeyRepo, mockAuditRepo, relyingParty, entity.EMAIL_VERIFICATION_OFF)
+
+		err := service.DeletePasskey(&command.DeletePasskeyCommand{
+			UserId: userId,
+			Id:     passkey.Id,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrPasskeyNotFound)
+	})
+}
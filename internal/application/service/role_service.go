@@ -0,0 +1,180 @@
+package service
+
+import (
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"slices"
+)
+
+type RoleService struct {
+	userRepository           repository.UserRepository
+	roleRepository           repository.RoleRepository
+	totpCredentialRepository repository.TotpCredentialRepository
+}
+
+func NewRoleService(userRepository repository.UserRepository, roleRepository repository.RoleRepository, totpCredentialRepository repository.TotpCredentialRepository) *RoleService {
+	return &RoleService{
+		userRepository:           userRepository,
+		roleRepository:           roleRepository,
+		totpCredentialRepository: totpCredentialRepository,
+	}
+}
+
+// SeedRoles creates the admin role, or brings its permissions up to date, and
+// assigns it to the configured admin emails that are registered. It runs at
+// startup so the first admin does not have to be assigned by hand.
+func (service *RoleService) SeedRoles(seedRolesCommand *command.SeedRolesCommand) error {
+	seed := entity.NewAdminRole()
+
+	existing, err := service.roleRepository.FindByName(seed.Name)
+	if err == nil {
+		seed.Id = existing.Id
+		seed.CreatedAt = existing.CreatedAt
+	}
+
+	validatedRole, err := entity.NewValidatedRole(seed)
+	if err != nil {
+		return err
+	}
+
+	var role *entity.Role
+	if existing == nil {
+		role, err = service.roleRepository.Create(validatedRole)
+	} else {
+		role, err = service.roleRepository.Update(validatedRole)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, email := range seedRolesCommand.AdminEmails {
+		user, err := service.userRepository.FindByEmail(email)
+		if err != nil {
+			continue
+		}
+		if err := service.roleRepository.AssignToUser(user.Id, role.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (service *RoleService) ListRoles() (*command.ListRolesCommandResult, error) {
+	roles, err := service.roleRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.ListRolesCommandResult{
+		Result: toRoleResults(roles),
+	}
+
+	return &result, nil
+}
+
+func (service *RoleService) ListUserRoles(listUserRolesCommand *command.ListUserRolesCommand) (*command.ListUserRolesCommandResult, error) {
+	if _, err := service.userRepository.FindById(listUserRolesCommand.UserId); err != nil {
+		return nil, err
+	}
+
+	roles, err := service.roleRepository.FindByUserId(listUserRolesCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.ListUserRolesCommandResult{
+		Result: toRoleResults(roles),
+	}
+
+	return &result, nil
+}
+
+func (service *RoleService) AssignRole(assignRoleCommand *command.AssignRoleCommand) error {
+	if _, err := service.userRepository.FindById(assignRoleCommand.UserId); err != nil {
+		return err
+	}
+
+	role, err := service.roleRepository.FindByName(assignRoleCommand.Role)
+	if err != nil {
+		return entity.ErrRoleNotFound
+	}
+
+	return service.roleRepository.AssignToUser(assignRoleCommand.UserId, role.Id)
+}
+
+// UnassignRole refuses to remove the admin role from its last holder, so there
+// is always someone left to manage roles.
+func (service *RoleService) UnassignRole(unassignRoleCommand *command.UnassignRoleCommand) error {
+	role, err := service.roleRepository.FindByName(unassignRoleCommand.Role)
+	if err != nil {
+		return entity.ErrRoleNotFound
+	}
+
+	if role.Name == entity.ROLE_ADMIN {
+		roles, err := service.roleRepository.FindByUserId(unassignRoleCommand.UserId)
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(roles, func(r *entity.Role) bool { return r.Id == role.Id }) {
+			return nil
+		}
+
+		count, err := service.roleRepository.CountUsers(role.Id)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return entity.ErrLastAdmin
+		}
+	}
+
+	return service.roleRepository.UnassignFromUser(unassignRoleCommand.UserId, role.Id)
+}
+
+// ResolvePermissions returns what goes into the user's access tokens. Roles
+// that require MFA are left out until the user has enabled it.
+func (service *RoleService) ResolvePermissions(resolvePermissionsCommand *command.ResolvePermissionsCommand) (*command.ResolvePermissionsCommandResult, error) {
+	roles, err := service.roleRepository.FindByUserId(resolvePermissionsCommand.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	mfaEnabled := false
+	if slices.ContainsFunc(roles, func(r *entity.Role) bool { return r.RequireMfa }) {
+		mfaEnabled, err = service.totpCredentialRepository.ExistsByUserId(resolvePermissionsCommand.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := command.ResolvePermissionsCommandResult{
+		Roles:       []string{},
+		Permissions: []string{},
+	}
+	for _, role := range roles {
+		if role.RequireMfa && !mfaEnabled {
+			continue
+		}
+
+		result.Roles = append(result.Roles, role.Name)
+		for _, permission := range role.Permissions {
+			if !slices.Contains(result.Permissions, permission) {
+				result.Permissions = append(result.Permissions, permission)
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+func toRoleResults(roles []*entity.Role) []*common.RoleResult {
+	results := make([]*common.RoleResult, len(roles))
+	for i, role := range roles {
+		results[i] = mapper.NewRoleResultFromEntity(role)
+	}
+	return results
+}
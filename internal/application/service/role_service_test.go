@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"errors"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRoleService_SeedRoles(t *testing.T) {
+	user := entity.NewUser("John Doe", "admin@example.com", "correct-password")
+
+	t.Run("success: creates admin role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		var created *entity.Role
+		mockRoleRepo.EXPECT().FindByName(entity.ROLE_ADMIN).Return(nil, errors.New("record not found"))
+		mockRoleRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(role *entity.ValidatedRole) (*entity.Role, error) {
+				created = &role.Role
+				return created, nil
+			})
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(user, nil)
+		mockUserRepo.EXPECT().FindByEmail("missing@example.com").Return(nil, errors.New("record not found"))
+		mockRoleRepo.EXPECT().AssignToUser(user.Id, gomock.Any()).Return(nil)
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		err := service.SeedRoles(&command.SeedRolesCommand{
+			AdminEmails: []string{user.Email, "missing@example.com"},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, created.RequireMfa)
+		assert.True(t, created.HasPermission(entity.PERMISSION_ROLES_MANAGE))
+	})
+
+	t.Run("success: updates existing admin role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		existing := entity.NewRole(entity.ROLE_ADMIN, "", nil, false)
+		mockRoleRepo.EXPECT().FindByName(entity.ROLE_ADMIN).Return(existing, nil)
+		mockRoleRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(role *entity.ValidatedRole) (*entity.Role, error) {
+				assert.Equal(t, existing.Id, role.Id)
+				return &role.Role, nil
+			})
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		err := service.SeedRoles(&command.SeedRolesCommand{})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	admin := entity.NewAdminRole()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockRoleRepo.EXPECT().FindByName(entity.ROLE_ADMIN).Return(admin, nil)
+		mockRoleRepo.EXPECT().AssignToUser(user.Id, admin.Id).Return(nil)
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		err := service.AssignRole(&command.AssignRoleCommand{
+			UserId: user.Id,
+			Role:   entity.ROLE_ADMIN,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: unknown role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockRoleRepo.EXPECT().FindByName("owner").Return(nil, errors.New("record not found"))
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		err := service.AssignRole(&command.AssignRoleCommand{
+			UserId: user.Id,
+			Role:   "owner",
+		})
+
+		assert.ErrorIs(t, err, entity.ErrRoleNotFound)
+	})
+}
+
+func TestRoleService_UnassignRole(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	admin := entity.NewAdminRole()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		mockRoleRepo.EXPECT().FindByName(entity.ROLE_ADMIN).Return(admin, nil)
+		mockRoleRepo.EXPECT().FindByUserId(user.Id).Return([]*entity.Role{admin}, nil)
+		mockRoleRepo.EXPECT().CountUsers(admin.Id).Return(int64(2), nil)
+		mockRoleRepo.EXPECT().UnassignFromUser(user.Id, admin.Id).Return(nil)
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		err := service.UnassignRole(&command.UnassignRoleCommand{
+			UserId: user.Id,
+			Role:   entity.ROLE_ADMIN,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: last admin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		mockRoleRepo.EXPECT().FindByName(entity.ROLE_ADMIN).Return(admin, nil)
+		mockRoleRepo.EXPECT().FindByUserId(user.Id).Return([]*entity.Role{admin}, nil)
+		mockRoleRepo.EXPECT().CountUsers(admin.Id).Return(int64(1), nil)
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		err := service.UnassignRole(&command.UnassignRoleCommand{
+			UserId: user.Id,
+			Role:   entity.ROLE_ADMIN,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrLastAdmin)
+	})
+}
+
+func TestRoleService_ResolvePermissions(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	admin := entity.NewAdminRole()
+	support := entity.NewRole("support", "", []string{entity.PERMISSION_USERS_READ}, false)
+
+	t.Run("success: with mfa", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		mockRoleRepo.EXPECT().FindByUserId(user.Id).Return([]*entity.Role{admin, support}, nil)
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(true, nil)
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		result, err := service.ResolvePermissions(&command.ResolvePermissionsCommand{
+			UserId: user.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{entity.ROLE_ADMIN, "support"}, result.Roles)
+		assert.ElementsMatch(t, admin.Permissions, result.Permissions)
+	})
+
+	t.Run("success: admin without mfa", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		mockRoleRepo.EXPECT().FindByUserId(user.Id).Return([]*entity.Role{admin, support}, nil)
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(false, nil)
+
+		service := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+
+		result, err := service.ResolvePermissions(&command.ResolvePermissionsCommand{
+			UserId: user.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"support"}, result.Roles)
+		assert.Equal(t, []string{entity.PERMISSION_USERS_READ}, result.Permissions)
+	})
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Sessions are kept in a single valkey hash per user, keyed by session id, and
+// expire once they have been idle for as long as a refresh token lives.
+type SessionService struct {
+	valkeyRepository repository.ValkeyRepository
+}
+
+func NewSessionService(valkeyRepository repository.ValkeyRepository) *SessionService {
+	return &SessionService{
+		valkeyRepository: valkeyRepository,
+	}
+}
+
+func (service *SessionService) CreateSession(createSessionCommand *command.CreateSessionCommand) (*command.CreateSessionCommandResult, error) {
+	session := entity.NewSession(createSessionCommand.UserId, createSessionCommand.Device, createSessionCommand.IpAddress, createSessionCommand.UserAgent)
+
+	if err := service.saveSession(session); err != nil {
+		return nil, err
+	}
+
+	result := command.CreateSessionCommandResult{
+		Result: mapper.NewSessionResultFromEntity(session),
+	}
+
+	return &result, nil
+}
+
+// touchSessionScript only writes the session back while it is still in the
+// hash, so a touch racing with a revocation cannot bring the session back.
+const touchSessionScript = `
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return 1
+`
+
+func (service *SessionService) TouchSession(touchSessionCommand *command.TouchSessionCommand) error {
+	session, err := service.findSession(touchSessionCommand.UserId, touchSessionCommand.SessionId)
+	if err != nil {
+		return err
+	}
+
+	session.Touch()
+
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	reply, err := service.valkeyRepository.Eval(context.Background(), touchSessionScript, []string{sessionKey(session.UserId)},
+		session.Id.String(), value, int(util.REFRESH_TOKEN_DURATION.Seconds()))
+	if err != nil {
+		return err
+	}
+	if reply != int64(1) {
+		return entity.ErrSessionRevoked
+	}
+
+	return nil
+}
+
+func (service *SessionService) GetSession(getSessionCommand *command.GetSessionCommand) (*command.GetSessionCommandResult, error) {
+	session, err := service.findSession(getSessionCommand.UserId, getSessionCommand.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.GetSessionCommandResult{
+		Result: mapper.NewSessionResultFromEntity(session),
+	}
+
+	return &result, nil
+}
+
+func (service *SessionService) ListSessions(listSessionsCommand *command.ListSessionsCommand) (*command.ListSessionsCommandResult, error) {
+	key := sessionKey(listSessionsCommand.UserId)
+
+	values, err := service.valkeyRepository.HGetAll(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*common.SessionResult, 0, len(values))
+	for sessionId, value := range values {
+		var session entity.Session
+		if err := json.Unmarshal([]byte(value), &session); err != nil || session.IsIdle(util.REFRESH_TOKEN_DURATION) {
+			service.valkeyRepository.HDel(context.Background(), key, sessionId)
+			continue
+		}
+		sessions = append(sessions, mapper.NewSessionResultFromEntity(&session))
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	result := command.ListSessionsCommandResult{
+		Result: sessions,
+	}
+
+	return &result, nil
+}
+
+func (service *SessionService) RevokeSession(revokeSessionCommand *command.RevokeSessionCommand) error {
+	session, err := service.findSession(revokeSessionCommand.UserId, revokeSessionCommand.SessionId)
+	if err != nil {
+		return err
+	}
+
+	return service.valkeyRepository.HDel(context.Background(), sessionKey(session.UserId), session.Id.String())
+}
+
+func (service *SessionService) RevokeOtherSessions(revokeOtherSessionsCommand *command.RevokeOtherSessionsCommand) error {
+	key := sessionKey(revokeOtherSessionsCommand.UserId)
+
+	values, err := service.valkeyRepository.HGetAll(context.Background(), key)
+	if err != nil {
+		return err
+	}
+
+	sessionIds := make([]string, 0, len(values))
+	for sessionId := range values {
+		if sessionId != revokeOtherSessionsCommand.CurrentSessionId.String() {
+			sessionIds = append(sessionIds, sessionId)
+		}
+	}
+
+	if len(sessionIds) == 0 {
+		return nil
+	}
+
+	return service.valkeyRepository.HDel(context.Background(), key, sessionIds...)
+}
+
+func (service *SessionService) findSession(userId uuid.UUID, sessionId uuid.UUID) (*entity.Session, error) {
+	value, err := service.valkeyRepository.HGet(context.Background(), sessionKey(userId), sessionId.String())
+	if err != nil {
+		return nil, entity.ErrSessionRevoked
+	}
+
+	var session entity.Session
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, err
+	}
+
+	if session.IsIdle(util.REFRESH_TOKEN_DURATION) {
+		service.valkeyRepository.HDel(context.Background(), sessionKey(userId), sessionId.String())
+		return nil, entity.ErrSessionRevoked
+	}
+
+	return &session, nil
+}
+
+func (service *SessionService) saveSession(session *entity.Session) error {
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	key := sessionKey(session.UserId)
+	if err := service.valkeyRepository.HSet(context.Background(), key, map[string]interface{}{
+		session.Id.String(): value,
+	}); err != nil {
+		return err
+	}
+
+	return service.valkeyRepository.Expire(context.Background(), key, int(util.REFRESH_TOKEN_DURATION.Seconds()))
+}
+
+func sessionKey(userId uuid.UUID) string {
+	return fmt.Sprintf("user:%s:%s", userId, entity.SESSION)
+}
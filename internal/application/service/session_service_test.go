@@ -0,0 +1,206 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionService_ListSessions(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	laptop := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	laptop.LastSeenAt = time.Now().Add(-time.Minute)
+	laptopValue, _ := json.Marshal(laptop)
+
+	phone := entity.NewSession(user.Id, "phone", "127.0.0.2", "test-agent")
+	phoneValue, _ := json.Marshal(phone)
+
+	idle := entity.NewSession(user.Id, "desktop", "127.0.0.3", "test-agent")
+	idle.LastSeenAt = time.Now().Add(-3 * time.Hour)
+	idleValue, _ := json.Marshal(idle)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGetAll(gomock.Any(), sessionKey).Return(map[string]string{
+			laptop.Id.String(): string(laptopValue),
+			phone.Id.String():  string(phoneValue),
+			idle.Id.String():   string(idleValue),
+		}, nil)
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, idle.Id.String()).Return(nil)
+
+		service := service.NewSessionService(mockValkeyRepo)
+
+		result, err := service.ListSessions(&command.ListSessionsCommand{
+			UserId: user.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Result, 2)
+		assert.Equal(t, phone.Id, result.Result[0].Id)
+		assert.Equal(t, laptop.Id, result.Result[1].Id)
+	})
+}
+
+func TestSessionService_TouchSession(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	session.LastSeenAt = time.Now().Add(-time.Minute)
+	sessionValue, _ := json.Marshal(session)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+
+		var touched entity.Session
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{sessionKey}, session.Id.String(), gomock.Any(), 2*60*60).
+			DoAndReturn(func(_ any, _ string, _ []string, args ...interface{}) (interface{}, error) {
+				json.Unmarshal(args[1].([]byte), &touched)
+				return int64(1), nil
+			})
+
+		service := service.NewSessionService(mockValkeyRepo)
+
+		err := service.TouchSession(&command.TouchSessionCommand{
+			UserId:    user.Id,
+			SessionId: session.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, touched.LastSeenAt.After(session.LastSeenAt))
+	})
+
+	t.Run("failure: revoked during touch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+
+		// The session is revoked between reading it and writing it back; the
+		// script sees it gone from the hash and leaves it gone.
+		sessions := map[string]string{session.Id.String(): string(sessionValue)}
+		mockValkeyRepo.EXPECT().
+			HGet(gomock.Any(), sessionKey, session.Id.String()).
+			DoAndReturn(func(_ any, _ string, field string) (string, error) {
+				value := sessions[field]
+				delete(sessions, field)
+				return value, nil
+			})
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{sessionKey}, session.Id.String(), gomock.Any(), 2*60*60).
+			DoAndReturn(func(_ any, _ string, _ []string, args ...interface{}) (interface{}, error) {
+				field := args[0].(string)
+				if _, ok := sessions[field]; !ok {
+					return int64(0), nil
+				}
+				sessions[field] = string(args[1].([]byte))
+				return int64(1), nil
+			})
+
+		service := service.NewSessionService(mockValkeyRepo)
+
+		err := service.TouchSession(&command.TouchSessionCommand{
+			UserId:    user.Id,
+			SessionId: session.Id,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrSessionRevoked)
+		assert.Empty(t, sessions)
+	})
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, session.Id.String()).Return(nil)
+
+		service := service.NewSessionService(mockValkeyRepo)
+
+		err := service.RevokeSession(&command.RevokeSessionCommand{
+			UserId:    user.Id,
+			SessionId: session.Id,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: unknown session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return("", errors.New("valkey nil message"))
+
+		service := service.NewSessionService(mockValkeyRepo)
+
+		err := service.RevokeSession(&command.RevokeSessionCommand{
+			UserId:    user.Id,
+			SessionId: session.Id,
+		})
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, entity.ErrSessionRevoked))
+	})
+}
+
+func TestSessionService_RevokeOtherSessions(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	current := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	other := entity.NewSession(user.Id, "phone", "127.0.0.2", "test-agent")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGetAll(gomock.Any(), sessionKey).Return(map[string]string{
+			current.Id.String(): "{}",
+			other.Id.String():   "{}",
+		}, nil)
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, other.Id.String()).Return(nil)
+
+		service := service.NewSessionService(mockValkeyRepo)
+
+		err := service.RevokeOtherSessions(&command.RevokeOtherSessionsCommand{
+			UserId:           user.Id,
+			CurrentSessionId: current.Id,
+		})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,578 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/event"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TokenService struct {
+	eventPublisher       event.EventPublisher
+	valkeyRepository     repository.ValkeyRepository
+	userRepository       repository.UserRepository
+	apiKeyRepository     repository.ApiKeyRepository
+	auditEventRepository repository.AuditEventRepository
+	sessionService       interfaces.SessionService
+	roleService          interfaces.RoleService
+}
+
+func NewTokenService(eventPublisher event.EventPublisher, valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository, apiKeyRepository repository.ApiKeyRepository, auditEventRepository repository.AuditEventRepository, sessionService interfaces.SessionService, roleService interfaces.RoleService) *TokenService {
+	return &TokenService{
+		eventPublisher:       eventPublisher,
+		valkeyRepository:     valkeyRepository,
+		userRepository:       userRepository,
+		apiKeyRepository:     apiKeyRepository,
+		auditEventRepository: auditEventRepository,
+		sessionService:       sessionService,
+		roleService:          roleService,
+	}
+}
+
+// loginAuditActions names the audit action of each login method.
+var loginAuditActions = map[string]string{
+	entity.LOGIN_METHOD_PASSWORD:   entity.AUDIT_ACTION_LOGIN,
+	entity.LOGIN_METHOD_MAGIC_LINK: entity.AUDIT_ACTION_LOGIN_MAGIC_LINK,
+	entity.LOGIN_METHOD_PASSKEY:    entity.AUDIT_ACTION_LOGIN_PASSKEY,
+}
+
+// IssueToken starts a session. When the tokens complete a login, after any
+// second factor, the login is audited and published as USER_LOGGED_IN.
+func (service *TokenService) IssueToken(issueTokenCommand *command.IssueTokenCommand) (_ *command.IssueTokenCommandResult, err error) {
+	if method := issueTokenCommand.Method; method != "" {
+		user := issueTokenCommand.User
+		audit := entity.NewAuditEvent(loginAuditActions[method], user.Email, issueTokenCommand.IpAddress, issueTokenCommand.UserAgent)
+		audit.UserId = &user.Id
+		audit.ActorId = &user.Id
+
+		defer func() {
+			recordAuditEvent(service.auditEventRepository, audit, err)
+			if err == nil {
+				publishLifecycleEvent(service.eventPublisher, entity.USER_LOGGED_IN, user.Id, entity.NewUserLoggedInEvent(user.Id, user.Email, method, issueTokenCommand.IpAddress, issueTokenCommand.UserAgent))
+			}
+		}()
+	}
+
+	if issueTokenCommand.User.DeletedAt != nil {
+		return nil, entity.ErrUserDeleted
+	}
+	if issueTokenCommand.User.DisabledAt != nil {
+		return nil, entity.ErrUserDisabled
+	}
+
+	session, err := service.sessionService.CreateSession(&command.CreateSessionCommand{
+		UserId:    issueTokenCommand.User.Id,
+		Device:    issueTokenCommand.Device,
+		IpAddress: issueTokenCommand.IpAddress,
+		UserAgent: issueTokenCommand.UserAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	grant := tokenGrant{
+		sessionId: session.Result.Id,
+		familyId:  uuid.NewString(),
+		clientId:  issueTokenCommand.ClientId,
+		scope:     issueTokenCommand.Scope,
+	}
+	refreshTokenId := uuid.NewString()
+
+	token, err := service.issueToken(issueTokenCommand.User, grant, refreshTokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := int(util.REFRESH_TOKEN_DURATION.Seconds())
+	familyKey := refreshTokenFamilyKey(issueTokenCommand.User.Id, grant.familyId)
+	if err := service.valkeyRepository.Set(context.Background(), familyKey, refreshTokenId, ttl); err != nil {
+		return nil, err
+	}
+
+	result := command.IssueTokenCommandResult{
+		Result: token,
+	}
+
+	return &result, nil
+}
+
+// rotateRefreshTokenScript moves the family on to the new token only if the
+// presented one is still its current token, so of two requests racing with the
+// same token only one can rotate it and the other is seen as a reuse.
+const rotateRefreshTokenScript = `
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return -1
+end
+
+redis.call('SET', KEYS[1], ARGV[2], 'EX', ARGV[3])
+return 1
+`
+
+// RefreshToken rotates a refresh token. Every refresh token belongs to a family
+// whose current token id is kept in valkey; presenting any other token of the
+// family means it was already rotated, so the whole family and its session are
+// revoked.
+func (service *TokenService) RefreshToken(refreshTokenCommand *command.RefreshTokenCommand) (*command.RefreshTokenCommandResult, error) {
+	claims, err := util.ValidateRefreshToken(refreshTokenCommand.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ClientId != refreshTokenCommand.ClientId {
+		return nil, errors.New("refresh token was issued to another client")
+	}
+
+	if err := service.sessionService.TouchSession(&command.TouchSessionCommand{
+		UserId:    claims.Id,
+		SessionId: claims.SessionId,
+	}); err != nil {
+		return nil, err
+	}
+
+	user, err := service.userRepository.FindById(claims.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.SignInError(); err != nil {
+		return nil, err
+	}
+
+	// The new pair is signed before the family is rotated, so a rotated family
+	// always has tokens to go with it.
+	refreshTokenId := uuid.NewString()
+	token, err := service.issueToken(mapper.NewUserResultFromEntity(user), tokenGrant{
+		sessionId: claims.SessionId,
+		familyId:  claims.FamilyId,
+		clientId:  claims.ClientId,
+		scope:     claims.Scope,
+	}, refreshTokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	familyKey := refreshTokenFamilyKey(claims.Id, claims.FamilyId)
+	reply, err := service.valkeyRepository.Eval(context.Background(), rotateRefreshTokenScript, []string{familyKey},
+		claims.TokenId, refreshTokenId, int(util.REFRESH_TOKEN_DURATION.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	switch reply {
+	case int64(1):
+	case int64(-1):
+		service.sessionService.RevokeSession(&command.RevokeSessionCommand{
+			UserId:    claims.Id,
+			SessionId: claims.SessionId,
+		})
+		return nil, entity.ErrRefreshTokenReused
+	default:
+		return nil, entity.ErrRefreshTokenRevoked
+	}
+
+	result := command.RefreshTokenCommandResult{
+		Result: token,
+	}
+
+	return &result, nil
+}
+
+// IssueMachineToken issues an access token to a machine client. There is no
+// session and no refresh token; the client simply asks for a new token.
+func (service *TokenService) IssueMachineToken(issueMachineTokenCommand *command.IssueMachineTokenCommand) (*command.IssueMachineTokenCommandResult, error) {
+	client := issueMachineTokenCommand.Client
+
+	accessToken, err := util.GenerateAccessToken(util.AccessTokenClaims{
+		Id:          client.Id,
+		Name:        client.Name,
+		TokenId:     uuid.NewString(),
+		ClientId:    client.ClientId,
+		Scope:       issueMachineTokenCommand.Scope,
+		SubjectType: util.SUBJECT_TYPE_CLIENT,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.IssueMachineTokenCommandResult{
+		Result: &common.TokenResult{
+			AccessToken: accessToken,
+			ExpiresIn:   int(util.ACCESS_TOKEN_DURATION.Seconds()),
+			Scope:       issueMachineTokenCommand.Scope,
+		},
+	}
+
+	return &result, nil
+}
+
+// ValidateAccessToken verifies the token signature, then makes sure it was not
+// denylisted by a logout and that its session, if it has one, is still active.
+func (service *TokenService) ValidateAccessToken(validateAccessTokenCommand *command.ValidateAccessTokenCommand) (*command.ValidateAccessTokenCommandResult, error) {
+	claims, err := util.ValidateAccessToken(validateAccessTokenCommand.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	denied, err := service.valkeyRepository.Exists(context.Background(), accessTokenDenylistKey(claims.TokenId))
+	if err != nil {
+		return nil, err
+	}
+	if denied {
+		return nil, entity.ErrAccessTokenRevoked
+	}
+
+	if !claims.IsMachine() {
+		if err := service.sessionService.TouchSession(&command.TouchSessionCommand{
+			UserId:    claims.Id,
+			SessionId: claims.SessionId,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	result := command.ValidateAccessTokenCommandResult{
+		Result: &claims,
+	}
+
+	return &result, nil
+}
+
+// ValidateApiKey looks the key up by its hash and builds the same claims an
+// access token would carry, with the key's scopes and without a session.
+func (service *TokenService) ValidateApiKey(validateApiKeyCommand *command.ValidateApiKeyCommand) (*command.ValidateApiKeyCommandResult, error) {
+	if !strings.HasPrefix(validateApiKeyCommand.ApiKey, entity.API_KEY_PREFIX) {
+		return nil, entity.ErrApiKeyInvalid
+	}
+
+	apiKey, err := service.apiKeyRepository.FindByKeyHash(entity.HashApiKey(validateApiKeyCommand.ApiKey))
+	if err != nil || !apiKey.IsActive() {
+		return nil, entity.ErrApiKeyInvalid
+	}
+
+	user, err := service.userRepository.FindById(apiKey.UserId)
+	if err != nil || user.SignInError() != nil {
+		return nil, entity.ErrApiKeyInvalid
+	}
+
+	if apiKey.Use() {
+		if validatedApiKey, err := entity.NewValidatedApiKey(apiKey); err == nil {
+			service.apiKeyRepository.Update(validatedApiKey)
+		}
+	}
+
+	claims := util.AccessTokenClaims{
+		Id:          user.Id,
+		Name:        user.Name,
+		Email:       user.Email,
+		Scope:       strings.Join(apiKey.Scopes, " "),
+		SubjectType: util.SUBJECT_TYPE_USER,
+		ApiKeyId:    apiKey.Id,
+	}
+	if apiKey.ExpiresAt != nil {
+		claims.ExpiresAt = *apiKey.ExpiresAt
+	}
+
+	result := command.ValidateApiKeyCommandResult{
+		Result: &claims,
+	}
+
+	return &result, nil
+}
+
+// IntrospectToken reports whether the token is active without touching its
+// session. The hint only decides which kind of token is tried first.
+func (service *TokenService) IntrospectToken(introspectTokenCommand *command.IntrospectTokenCommand) (*command.IntrospectTokenCommandResult, error) {
+	introspectors := []func(string) (*common.IntrospectionResult, error){service.introspectAccessToken, service.introspectRefreshToken}
+	if introspectTokenCommand.TokenTypeHint == entity.TOKEN_TYPE_HINT_REFRESH_TOKEN {
+		slices.Reverse(introspectors)
+	}
+
+	for _, introspect := range introspectors {
+		introspection, err := introspect(introspectTokenCommand.Token)
+		if err != nil {
+			return nil, err
+		}
+		if introspection != nil {
+			return &command.IntrospectTokenCommandResult{Result: introspection}, nil
+		}
+	}
+
+	result := command.IntrospectTokenCommandResult{
+		Result: &common.IntrospectionResult{Active: false},
+	}
+
+	return &result, nil
+}
+
+// RevokeToken revokes an access or refresh token issued to the client. Revoking
+// a refresh token ends its session, which also invalidates the access tokens
+// issued with it. As RFC 7009 asks, tokens that are invalid, already revoked or
+// issued to another client are silently ignored.
+func (service *TokenService) RevokeToken(revokeTokenCommand *command.RevokeTokenCommand) error {
+	revokers := []func(string, string) (bool, error){service.revokeAccessToken, service.revokeRefreshToken}
+	if revokeTokenCommand.TokenTypeHint == entity.TOKEN_TYPE_HINT_REFRESH_TOKEN {
+		slices.Reverse(revokers)
+	}
+
+	for _, revoke := range revokers {
+		if handled, err := revoke(revokeTokenCommand.Token, revokeTokenCommand.ClientId); handled || err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Logout denylists the access token for the rest of its lifetime, revokes the
+// refresh token family it was paired with and ends the session.
+func (service *TokenService) Logout(logoutCommand *command.LogoutCommand) error {
+	claims := logoutCommand.Claims
+
+	var refreshClaims *util.RefreshTokenClaims
+	if logoutCommand.RefreshToken != "" {
+		validatedClaims, err := util.ValidateRefreshToken(logoutCommand.RefreshToken)
+		if err != nil {
+			return err
+		}
+
+		if validatedClaims.Id != claims.Id || validatedClaims.SessionId != claims.SessionId {
+			return errors.New("refresh token does not belong to this session")
+		}
+		refreshClaims = &validatedClaims
+	}
+
+	if err := service.denylistAccessToken(claims); err != nil {
+		return err
+	}
+
+	if refreshClaims != nil {
+		if err := service.valkeyRepository.Delete(context.Background(), refreshTokenFamilyKey(refreshClaims.Id, refreshClaims.FamilyId)); err != nil {
+			return err
+		}
+	}
+
+	err := service.sessionService.RevokeSession(&command.RevokeSessionCommand{
+		UserId:    claims.Id,
+		SessionId: claims.SessionId,
+	})
+	if err != nil && !errors.Is(err, entity.ErrSessionRevoked) {
+		return err
+	}
+
+	return nil
+}
+
+func (service *TokenService) denylistAccessToken(claims *util.AccessTokenClaims) error {
+	ttl := int(time.Until(claims.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return nil
+	}
+
+	return service.valkeyRepository.Set(context.Background(), accessTokenDenylistKey(claims.TokenId), claims.Id.String(), ttl)
+}
+
+// revokeAccessToken reports whether the token was an access token, whether or
+// not it was issued to the client.
+func (service *TokenService) revokeAccessToken(token string, clientId string) (bool, error) {
+	claims, err := util.ValidateAccessToken(token)
+	if err != nil {
+		return false, nil
+	}
+	if claims.ClientId != clientId {
+		return true, nil
+	}
+
+	return true, service.denylistAccessToken(&claims)
+}
+
+// revokeRefreshToken reports whether the token was a refresh token, whether or
+// not it was issued to the client.
+func (service *TokenService) revokeRefreshToken(token string, clientId string) (bool, error) {
+	claims, err := util.ValidateRefreshToken(token)
+	if err != nil {
+		return false, nil
+	}
+	if claims.ClientId != clientId {
+		return true, nil
+	}
+
+	if err := service.valkeyRepository.Delete(context.Background(), refreshTokenFamilyKey(claims.Id, claims.FamilyId)); err != nil {
+		return true, err
+	}
+
+	err = service.sessionService.RevokeSession(&command.RevokeSessionCommand{
+		UserId:    claims.Id,
+		SessionId: claims.SessionId,
+	})
+	if err != nil && !errors.Is(err, entity.ErrSessionRevoked) {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// introspectAccessToken returns nil when the token is not an active access
+// token.
+func (service *TokenService) introspectAccessToken(token string) (*common.IntrospectionResult, error) {
+	claims, err := util.ValidateAccessToken(token)
+	if err != nil {
+		return nil, nil
+	}
+
+	denied, err := service.valkeyRepository.Exists(context.Background(), accessTokenDenylistKey(claims.TokenId))
+	if err != nil {
+		return nil, err
+	}
+	if denied {
+		return nil, nil
+	}
+
+	if !claims.IsMachine() {
+		if active, err := service.isSessionActive(claims.Id, claims.SessionId); !active || err != nil {
+			return nil, err
+		}
+	}
+
+	return &common.IntrospectionResult{
+		Active:      true,
+		TokenType:   entity.TOKEN_TYPE_HINT_ACCESS_TOKEN,
+		Subject:     claims.Id,
+		SubjectType: claims.SubjectType,
+		Username:    claims.Email,
+		SessionId:   claims.SessionId,
+		TokenId:     claims.TokenId,
+		ClientId:    claims.ClientId,
+		Scope:       claims.Scope,
+		ExpiresAt:   claims.ExpiresAt,
+	}, nil
+}
+
+// introspectRefreshToken returns nil when the token is not the current refresh
+// token of its family. Unlike a refresh, a stale token is not treated as reuse.
+func (service *TokenService) introspectRefreshToken(token string) (*common.IntrospectionResult, error) {
+	claims, err := util.ValidateRefreshToken(token)
+	if err != nil {
+		return nil, nil
+	}
+
+	currentTokenId, err := service.valkeyRepository.Get(context.Background(), refreshTokenFamilyKey(claims.Id, claims.FamilyId))
+	if err != nil || currentTokenId != claims.TokenId {
+		return nil, nil
+	}
+
+	if active, err := service.isSessionActive(claims.Id, claims.SessionId); !active || err != nil {
+		return nil, err
+	}
+
+	return &common.IntrospectionResult{
+		Active:      true,
+		TokenType:   entity.TOKEN_TYPE_HINT_REFRESH_TOKEN,
+		Subject:     claims.Id,
+		SubjectType: util.SUBJECT_TYPE_USER,
+		SessionId:   claims.SessionId,
+		TokenId:     claims.TokenId,
+		ClientId:    claims.ClientId,
+		Scope:       claims.Scope,
+		ExpiresAt:   claims.ExpiresAt,
+	}, nil
+}
+
+func (service *TokenService) isSessionActive(userId uuid.UUID, sessionId uuid.UUID) (bool, error) {
+	_, err := service.sessionService.GetSession(&command.GetSessionCommand{
+		UserId:    userId,
+		SessionId: sessionId,
+	})
+	if errors.Is(err, entity.ErrSessionRevoked) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// tokenGrant is what a token pair is issued for; it is carried over unchanged
+// when the refresh token is rotated.
+type tokenGrant struct {
+	sessionId uuid.UUID
+	familyId  string
+	clientId  string
+	scope     string
+}
+
+// issueToken puts the user's roles and permissions in tokens issued to the
+// user directly. Tokens issued to OAuth clients are limited to their scopes and
+// never carry them. They are resolved again on every refresh. Making the
+// refresh token the current one of its family is left to the caller.
+func (service *TokenService) issueToken(user *common.UserResult, grant tokenGrant, refreshTokenId string) (*common.TokenResult, error) {
+	claims := util.AccessTokenClaims{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		SessionId: grant.sessionId,
+		TokenId:   uuid.NewString(),
+		ClientId:  grant.clientId,
+		Scope:     grant.scope,
+	}
+	if grant.clientId == "" {
+		permissions, err := service.roleService.ResolvePermissions(&command.ResolvePermissionsCommand{
+			UserId: user.Id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		claims.Roles = permissions.Roles
+		claims.Permissions = permissions.Permissions
+	}
+
+	accessToken, err := util.GenerateAccessToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := util.GenerateRefreshToken(util.RefreshTokenClaims{
+		Id:        user.Id,
+		TokenId:   refreshTokenId,
+		FamilyId:  grant.familyId,
+		SessionId: grant.sessionId,
+		ClientId:  grant.clientId,
+		Scope:     grant.scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.TokenResult{
+		SessionId:    grant.sessionId,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(util.ACCESS_TOKEN_DURATION.Seconds()),
+		Scope:        grant.scope,
+	}, nil
+}
+
+func accessTokenDenylistKey(tokenId string) string {
+	return fmt.Sprintf("%s:%s", entity.ACCESS_TOKEN_DENYLIST, tokenId)
+}
+
+func refreshTokenFamilyKey(userId uuid.UUID, familyId string) string {
+	return fmt.Sprintf("user:%s:%s:%s", userId, entity.REFRESH_TOKEN_FAMILY, familyId)
+}
@@ -0,0 +1,1004 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// newRoleService grants no roles, which is all most token tests need.
+func newRoleService(ctrl *gomock.Controller, userRepository *mocks.MockUserRepository) *service.RoleService {
+	mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+	mockRoleRepo.EXPECT().FindByUserId(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	return service.NewRoleService(userRepository, mockRoleRepo, mocks.NewMockTotpCredentialRepository(ctrl))
+}
+
+func TestTokenService_IssueToken(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+		var storedTokenId string
+		mockValkeyRepo.EXPECT().HSet(gomock.Any(), sessionKey, gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), sessionKey, 2*60*60).Return(nil)
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), gomock.Any(), gomock.Any(), 2*60*60). // ttl = 2 hours
+			DoAndReturn(func(_ any, _ string, value any, _ int) error {
+				storedTokenId = value.(string)
+				return nil
+			})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.IssueToken(&command.IssueTokenCommand{
+			User: mapper.NewUserResultFromEntity(user),
+		})
+
+		assert.NoError(t, err)
+
+		claims, err := util.ValidateRefreshToken(result.Result.RefreshToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, claims.Id)
+		assert.Equal(t, storedTokenId, claims.TokenId)
+		assert.Equal(t, result.Result.SessionId, claims.SessionId)
+		assert.NotEmpty(t, claims.FamilyId)
+	})
+
+	t.Run("success: with roles", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		mockTotpRepo := mocks.NewMockTotpCredentialRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockRoleRepo.EXPECT().FindByUserId(user.Id).Return([]*entity.Role{entity.NewAdminRole()}, nil)
+		mockTotpRepo.EXPECT().ExistsByUserId(user.Id).Return(true, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := service.NewRoleService(mockUserRepo, mockRoleRepo, mockTotpRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.IssueToken(&command.IssueTokenCommand{
+			User: mapper.NewUserResultFromEntity(user),
+		})
+
+		assert.NoError(t, err)
+
+		claims, err := util.ValidateAccessToken(result.Result.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{entity.ROLE_ADMIN}, claims.Roles)
+		assert.True(t, claims.HasPermission(entity.PERMISSION_ROLES_MANAGE))
+	})
+
+	t.Run("success: completes a login", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockAuditRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(event *entity.ValidatedAuditEvent) (*entity.AuditEvent, error) {
+				assert.Equal(t, entity.AUDIT_ACTION_LOGIN_MAGIC_LINK, event.Action)
+				assert.Equal(t, entity.AUDIT_OUTCOME_SUCCESS, event.Outcome)
+				assert.Equal(t, user.Id, *event.UserId)
+				assert.Equal(t, user.Id, *event.ActorId)
+				assert.Equal(t, "127.0.0.1", event.IpAddress)
+				assert.Equal(t, "test-agent", event.UserAgent)
+				return &event.AuditEvent, nil
+			})
+		mockEventPub.EXPECT().
+			PublishWithKey(entity.USER_LOGGED_IN, []byte(user.Id.String()), gomock.Any()).
+			DoAndReturn(func(_ string, _ []byte, event interface{}) error {
+				loggedIn := event.(entity.UserLoggedInEvent)
+				assert.NotEqual(t, uuid.Nil, loggedIn.EventId)
+				assert.Equal(t, user.Id, loggedIn.Id)
+				assert.Equal(t, user.Email, loggedIn.Email)
+				assert.Equal(t, entity.LOGIN_METHOD_MAGIC_LINK, loggedIn.Method)
+				return nil
+			})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.IssueToken(&command.IssueTokenCommand{
+			User:      mapper.NewUserResultFromEntity(user),
+			IpAddress: "127.0.0.1",
+			UserAgent: "test-agent",
+			Method:    entity.LOGIN_METHOD_MAGIC_LINK,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("success: audit log failure does not fail the login", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Expire(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockAuditRepo.EXPECT().Create(gomock.Any()).Return(nil, errors.New("connection refused"))
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_LOGGED_IN, []byte(user.Id.String()), gomock.Any()).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.IssueToken(&command.IssueTokenCommand{
+			User:   mapper.NewUserResultFromEntity(user),
+			Method: entity.LOGIN_METHOD_PASSWORD,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: disabled user is recorded as a failed login", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		disabledUser := *user
+		disabledUser.Disable()
+
+		mockAuditRepo.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(event *entity.ValidatedAuditEvent) (*entity.AuditEvent, error) {
+				assert.Equal(t, entity.AUDIT_ACTION_LOGIN_PASSKEY, event.Action)
+				assert.Equal(t, entity.AUDIT_OUTCOME_FAILURE, event.Outcome)
+				return &event.AuditEvent, nil
+			})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.IssueToken(&command.IssueTokenCommand{
+			User:   mapper.NewUserResultFromEntity(&disabledUser),
+			Method: entity.LOGIN_METHOD_PASSKEY,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrUserDisabled)
+	})
+}
+
+func TestTokenService_RefreshToken(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	familyId := "family-id"
+	familyKey := fmt.Sprintf("user:%s:%s:%s", user.Id, entity.REFRESH_TOKEN_FAMILY, familyId)
+
+	refreshToken, _ := util.GenerateRefreshToken(util.RefreshTokenClaims{
+		Id:        user.Id,
+		TokenId:   "token-id",
+		FamilyId:  familyId,
+		SessionId: session.Id,
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{sessionKey}, session.Id.String(), gomock.Any(), 2*60*60).
+			Return(int64(1), nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{familyKey}, "token-id", gomock.Not("token-id"), 2*60*60).
+			Return(int64(1), nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.RefreshToken(&command.RefreshTokenCommand{
+			RefreshToken: refreshToken,
+		})
+
+		assert.NoError(t, err)
+
+		claims, err := util.ValidateRefreshToken(result.Result.RefreshToken)
+		assert.NoError(t, err)
+		assert.Equal(t, familyId, claims.FamilyId)
+		assert.NotEqual(t, "token-id", claims.TokenId)
+	})
+
+	t.Run("failure: reused token revokes family", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{sessionKey}, session.Id.String(), gomock.Any(), 2*60*60).
+			Return(int64(1), nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{familyKey}, "token-id", gomock.Any(), 2*60*60).
+			Return(int64(-1), nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, session.Id.String()).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.RefreshToken(&command.RefreshTokenCommand{
+			RefreshToken: refreshToken,
+		})
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, entity.ErrRefreshTokenReused))
+	})
+
+	t.Run("failure: concurrent refresh rotates once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil).AnyTimes()
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{sessionKey}, session.Id.String(), gomock.Any(), 2*60*60).
+			Return(int64(1), nil).AnyTimes()
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, session.Id.String()).Return(nil).AnyTimes()
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil).Times(2)
+
+		// The script runs atomically in valkey, which the lock stands in for.
+		var mu sync.Mutex
+		current := "token-id"
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{familyKey}, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, _ string, _ []string, args ...interface{}) (interface{}, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if current != args[0] {
+					current = ""
+					return int64(-1), nil
+				}
+				current = args[1].(string)
+				return int64(1), nil
+			}).Times(2)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = service.RefreshToken(&command.RefreshTokenCommand{
+					RefreshToken: refreshToken,
+				})
+			}()
+		}
+		wg.Wait()
+
+		assert.Contains(t, errs, nil)
+		assert.Contains(t, errs, entity.ErrRefreshTokenReused)
+	})
+
+	t.Run("failure: revoked family", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{sessionKey}, session.Id.String(), gomock.Any(), 2*60*60).
+			Return(int64(1), nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{familyKey}, "token-id", gomock.Any(), 2*60*60).
+			Return(int64(0), nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.RefreshToken(&command.RefreshTokenCommand{
+			RefreshToken: refreshToken,
+		})
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, entity.ErrRefreshTokenRevoked))
+	})
+
+	t.Run("failure: revoked session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return("", errors.New("valkey nil message"))
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.RefreshToken(&command.RefreshTokenCommand{
+			RefreshToken: refreshToken,
+		})
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, entity.ErrSessionRevoked))
+	})
+
+	t.Run("failure: invalid token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.RefreshToken(&command.RefreshTokenCommand{
+			RefreshToken: "invalid-token",
+		})
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, jwt.ErrTokenMalformed))
+	})
+}
+
+func TestTokenService_ValidateAccessToken(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	accessToken, _ := util.GenerateAccessToken(util.AccessTokenClaims{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		SessionId: session.Id,
+		TokenId:   "token-id",
+	})
+	denylistKey := fmt.Sprintf("%s:%s", entity.ACCESS_TOKEN_DENYLIST, "token-id")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Exists(gomock.Any(), denylistKey).Return(false, nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{sessionKey}, session.Id.String(), gomock.Any(), 2*60*60).
+			Return(int64(1), nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.ValidateAccessToken(&command.ValidateAccessTokenCommand{
+			AccessToken: accessToken,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Id)
+		assert.Equal(t, session.Id, result.Result.SessionId)
+		assert.Equal(t, "token-id", result.Result.TokenId)
+	})
+
+	t.Run("success: machine token has no session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		machineToken, _ := util.GenerateAccessToken(util.AccessTokenClaims{
+			Id:          uuid.New(),
+			Name:        "billing-service",
+			TokenId:     "machine-token-id",
+			ClientId:    "machine-client-id",
+			Scope:       entity.SCOPE_USERS_READ,
+			SubjectType: util.SUBJECT_TYPE_CLIENT,
+		})
+
+		mockValkeyRepo.EXPECT().Exists(gomock.Any(), fmt.Sprintf("%s:%s", entity.ACCESS_TOKEN_DENYLIST, "machine-token-id")).Return(false, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.ValidateAccessToken(&command.ValidateAccessTokenCommand{
+			AccessToken: machineToken,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Result.IsMachine())
+		assert.Equal(t, "machine-client-id", result.Result.ClientId)
+		assert.Empty(t, result.Result.Email)
+	})
+
+	t.Run("failure: denylisted token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Exists(gomock.Any(), denylistKey).Return(true, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.ValidateAccessToken(&command.ValidateAccessTokenCommand{
+			AccessToken: accessToken,
+		})
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, entity.ErrAccessTokenRevoked))
+	})
+}
+
+func TestTokenService_ValidateApiKey(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+	key := entity.API_KEY_PREFIX + "secret"
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+		apiKey := entity.NewApiKey(user.Id, "ci", key, []string{entity.SCOPE_API_READ}, nil)
+
+		mockApiKeyRepo.EXPECT().FindByKeyHash(entity.HashApiKey(key)).Return(apiKey, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+		mockApiKeyRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(validatedApiKey *entity.ValidatedApiKey) (*entity.ApiKey, error) {
+				assert.NotNil(t, validatedApiKey.LastUsedAt)
+				return &validatedApiKey.ApiKey, nil
+			})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.ValidateApiKey(&command.ValidateApiKeyCommand{
+			ApiKey: key,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, result.Result.Id)
+		assert.Equal(t, user.Email, result.Result.Email)
+		assert.Equal(t, apiKey.Id, result.Result.ApiKeyId)
+		assert.Equal(t, entity.SCOPE_API_READ, result.Result.Scope)
+		assert.True(t, result.Result.IsApiKey())
+	})
+
+	t.Run("success: recently used key is not saved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+		apiKey := entity.NewApiKey(user.Id, "ci", key, nil, nil)
+		lastUsedAt := time.Now().Add(-10 * time.Second)
+		apiKey.LastUsedAt = &lastUsedAt
+
+		mockApiKeyRepo.EXPECT().FindByKeyHash(entity.HashApiKey(key)).Return(apiKey, nil)
+		mockUserRepo.EXPECT().FindById(user.Id).Return(user, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.ValidateApiKey(&command.ValidateApiKeyCommand{
+			ApiKey: key,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: revoked key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+		apiKey := entity.NewApiKey(user.Id, "ci", key, nil, nil)
+		apiKey.Revoke()
+
+		mockApiKeyRepo.EXPECT().FindByKeyHash(entity.HashApiKey(key)).Return(apiKey, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.ValidateApiKey(&command.ValidateApiKeyCommand{
+			ApiKey: key,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrApiKeyInvalid)
+		assert.Nil(t, result)
+	})
+
+	t.Run("failure: expired key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+		expiresAt := time.Now().Add(-time.Hour)
+		apiKey := entity.NewApiKey(user.Id, "ci", key, nil, &expiresAt)
+
+		mockApiKeyRepo.EXPECT().FindByKeyHash(entity.HashApiKey(key)).Return(apiKey, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.ValidateApiKey(&command.ValidateApiKeyCommand{
+			ApiKey: key,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrApiKeyInvalid)
+	})
+
+	t.Run("failure: unknown key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+		mockApiKeyRepo.EXPECT().FindByKeyHash(entity.HashApiKey(key)).Return(nil, errors.New("record not found"))
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		_, err := service.ValidateApiKey(&command.ValidateApiKeyCommand{
+			ApiKey: key,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrApiKeyInvalid)
+	})
+}
+
+func TestTokenService_IntrospectToken(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	accessToken, _ := util.GenerateAccessToken(util.AccessTokenClaims{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		SessionId: session.Id,
+		TokenId:   "token-id",
+		ClientId:  "client-id",
+		Scope:     "openid",
+	})
+	denylistKey := fmt.Sprintf("%s:%s", entity.ACCESS_TOKEN_DENYLIST, "token-id")
+
+	familyKey := fmt.Sprintf("user:%s:%s:%s", user.Id, entity.REFRESH_TOKEN_FAMILY, "family-id")
+	refreshToken, _ := util.GenerateRefreshToken(util.RefreshTokenClaims{
+		Id:        user.Id,
+		TokenId:   "refresh-token-id",
+		FamilyId:  "family-id",
+		SessionId: session.Id,
+		ClientId:  "client-id",
+	})
+
+	t.Run("success: active access token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Exists(gomock.Any(), denylistKey).Return(false, nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.IntrospectToken(&command.IntrospectTokenCommand{
+			Token: accessToken,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Result.Active)
+		assert.Equal(t, entity.TOKEN_TYPE_HINT_ACCESS_TOKEN, result.Result.TokenType)
+		assert.Equal(t, user.Id, result.Result.Subject)
+		assert.Equal(t, "client-id", result.Result.ClientId)
+		assert.Equal(t, "openid", result.Result.Scope)
+	})
+
+	t.Run("success: active refresh token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), familyKey).Return("refresh-token-id", nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.IntrospectToken(&command.IntrospectTokenCommand{
+			Token:         refreshToken,
+			TokenTypeHint: entity.TOKEN_TYPE_HINT_REFRESH_TOKEN,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Result.Active)
+		assert.Equal(t, entity.TOKEN_TYPE_HINT_REFRESH_TOKEN, result.Result.TokenType)
+		assert.False(t, result.Result.ExpiresAt.IsZero())
+	})
+
+	t.Run("failure: denylisted access token is inactive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Exists(gomock.Any(), denylistKey).Return(true, nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.IntrospectToken(&command.IntrospectTokenCommand{
+			Token: accessToken,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Result.Active)
+	})
+
+	t.Run("failure: rotated refresh token is inactive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Get(gomock.Any(), familyKey).Return("newer-token-id", nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		result, err := service.IntrospectToken(&command.IntrospectTokenCommand{
+			Token: refreshToken,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Result.Active)
+	})
+}
+
+func TestTokenService_RevokeToken(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	accessToken, _ := util.GenerateAccessToken(util.AccessTokenClaims{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		SessionId: session.Id,
+		TokenId:   "token-id",
+		ClientId:  "client-id",
+	})
+	denylistKey := fmt.Sprintf("%s:%s", entity.ACCESS_TOKEN_DENYLIST, "token-id")
+
+	familyKey := fmt.Sprintf("user:%s:%s:%s", user.Id, entity.REFRESH_TOKEN_FAMILY, "family-id")
+	refreshToken, _ := util.GenerateRefreshToken(util.RefreshTokenClaims{
+		Id:        user.Id,
+		TokenId:   "refresh-token-id",
+		FamilyId:  "family-id",
+		SessionId: session.Id,
+		ClientId:  "client-id",
+	})
+
+	t.Run("success: access token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Set(gomock.Any(), denylistKey, user.Id.String(), gomock.Any()).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		err := service.RevokeToken(&command.RevokeTokenCommand{
+			Token:    accessToken,
+			ClientId: "client-id",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("success: refresh token ends the session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), familyKey).Return(nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, session.Id.String()).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		err := service.RevokeToken(&command.RevokeTokenCommand{
+			Token:         refreshToken,
+			TokenTypeHint: entity.TOKEN_TYPE_HINT_REFRESH_TOKEN,
+			ClientId:      "client-id",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("success: token of another client is ignored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		err := service.RevokeToken(&command.RevokeTokenCommand{
+			Token:    refreshToken,
+			ClientId: "other-client-id",
+		})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestTokenService_Logout(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "correct-password")
+
+	session := entity.NewSession(user.Id, "laptop", "127.0.0.1", "test-agent")
+	sessionValue, _ := json.Marshal(session)
+	sessionKey := fmt.Sprintf("user:%s:%s", user.Id, entity.SESSION)
+
+	claims := util.AccessTokenClaims{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		SessionId: session.Id,
+		TokenId:   "token-id",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	denylistKey := fmt.Sprintf("%s:%s", entity.ACCESS_TOKEN_DENYLIST, "token-id")
+
+	familyKey := fmt.Sprintf("user:%s:%s:%s", user.Id, entity.REFRESH_TOKEN_FAMILY, "family-id")
+	refreshToken, _ := util.GenerateRefreshToken(util.RefreshTokenClaims{
+		Id:        user.Id,
+		TokenId:   "refresh-token-id",
+		FamilyId:  "family-id",
+		SessionId: session.Id,
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), denylistKey, user.Id.String(), gomock.Cond(func(ttl int) bool { return ttl > 0 && ttl <= 60 })).
+			Return(nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), familyKey).Return(nil)
+		mockValkeyRepo.EXPECT().HGet(gomock.Any(), sessionKey, session.Id.String()).Return(string(sessionValue), nil)
+		mockValkeyRepo.EXPECT().HDel(gomock.Any(), sessionKey, session.Id.String()).Return(nil)
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		err := service.Logout(&command.LogoutCommand{
+			Claims:       &claims,
+			RefreshToken: refreshToken,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: refresh token from another session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockApiKeyRepo := mocks.NewMockApiKeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+		mockAuditRepo := mocks.NewMockAuditEventRepository(ctrl)
+
+		otherRefreshToken, _ := util.GenerateRefreshToken(util.RefreshTokenClaims{
+			Id:        user.Id,
+			TokenId:   "refresh-token-id",
+			FamilyId:  "family-id",
+			SessionId: uuid.New(),
+		})
+
+		sessionService := service.NewSessionService(mockValkeyRepo)
+		roleService := newRoleService(ctrl, mockUserRepo)
+		service := service.NewTokenService(mockEventPub, mockValkeyRepo, mockUserRepo, mockApiKeyRepo, mockAuditRepo, sessionService, roleService)
+
+		err := service.Logout(&command.LogoutCommand{
+			Claims:       &claims,
+			RefreshToken: otherRefreshToken,
+		})
+
+		assert.Error(t, err)
+	})
+}
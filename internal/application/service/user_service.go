@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/mapper"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/event"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PURGE_DELETED_USERS_LOCK_KEY      = "purge-deleted-users:lock"
+	PURGE_DELETED_USERS_LOCK_DURATION = 10 * time.Minute
+	PURGE_DELETED_USERS_BATCH_SIZE    = 100
+)
+
+// UserService serves user lookups to other backend services and user
+// management to admins, as opposed to AuthenticateService which acts on the
+// signed in user.
+type UserService struct {
+	eventPublisher      event.EventPublisher
+	valkeyRepository    repository.ValkeyRepository
+	userRepository      repository.UserRepository
+	deletionGracePeriod time.Duration
+}
+
+func NewUserService(eventPublisher event.EventPublisher, valkeyRepository repository.ValkeyRepository, userRepository repository.UserRepository, deletionGracePeriod time.Duration) *UserService {
+	return &UserService{
+		eventPublisher:      eventPublisher,
+		valkeyRepository:    valkeyRepository,
+		userRepository:      userRepository,
+		deletionGracePeriod: deletionGracePeriod,
+	}
+}
+
+func (service *UserService) GetUser(getUserCommand *command.GetUserCommand) (*command.GetUserCommandResult, error) {
+	user, err := service.userRepository.FindById(getUserCommand.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := command.GetUserCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+// ListUsers returns a page of at most criteria.MAX_LIMIT users. When paging by
+// cursor a full page always gets a next cursor, so the last page can be empty.
+func (service *UserService) ListUsers(listUsersCommand *command.ListUsersCommand) (*command.ListUsersCommandResult, error) {
+	userCriteria := criteria.UserCriteria{}
+	if listUsersCommand.Criteria != nil {
+		userCriteria = *listUsersCommand.Criteria
+	}
+
+	if userCriteria.Limit <= 0 {
+		userCriteria.Limit = criteria.DEFAULT_LIMIT
+	}
+	userCriteria.Limit = min(userCriteria.Limit, criteria.MAX_LIMIT)
+
+	users, err := service.userRepository.FindAll(&userCriteria)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := service.userRepository.Count(&userCriteria)
+	if err != nil {
+		return nil, err
+	}
+
+	nextCursor := ""
+	offset := max(userCriteria.Page-1, 0) * userCriteria.Limit
+	if len(users) == userCriteria.Limit && (userCriteria.Cursor != nil || int64(offset+len(users)) < total) {
+		last := users[len(users)-1]
+		nextCursor = criteria.NewUserCursor(userCriteria.SortColumn(), last.Id, last.Name, last.Email, last.CreatedAt).Encode()
+	}
+
+	results := make([]*common.UserResult, len(users))
+	for i, user := range users {
+		results[i] = mapper.NewUserResultFromEntity(user)
+	}
+
+	result := command.ListUsersCommandResult{
+		Result: &common.UserListResult{
+			Users:      results,
+			Total:      total,
+			NextCursor: nextCursor,
+		},
+	}
+
+	return &result, nil
+}
+
+// UpdateUser changes the name and email without the confirmation users go
+// through themselves. A new email signs the user out everywhere, since the
+// tokens still carry the old one.
+func (service *UserService) UpdateUser(updateUserCommand *command.UpdateUserCommand) (*command.UpdateUserCommandResult, error) {
+	user, err := service.userRepository.FindById(updateUserCommand.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldEmail := user.Email
+	emailChanged := updateUserCommand.Email != oldEmail
+	if emailChanged {
+		if _, err := service.userRepository.FindByEmail(updateUserCommand.Email); err == nil {
+			return nil, entity.ErrEmailTaken
+		}
+		if err := user.UpdateEmail(updateUserCommand.Email); err != nil {
+			return nil, err
+		}
+	}
+	if err := user.UpdateName(updateUserCommand.Name); err != nil {
+		return nil, err
+	}
+
+	validatedUser, err := entity.NewValidatedUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = service.userRepository.Update(validatedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if emailChanged {
+		ctx := context.Background()
+		service.valkeyRepository.Delete(ctx, emailChangeKey(user.Id))
+		if err := service.valkeyRepository.Delete(ctx, sessionKey(user.Id)); err != nil {
+			return nil, err
+		}
+
+		publishLifecycleEvent(service.eventPublisher, entity.USER_EMAIL_CHANGED, user.Id, entity.NewUserEmailChangedEvent(user, oldEmail))
+	}
+
+	result := command.UpdateUserCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+// ForcePasswordReset replaces the password with a random one, signs the user
+// out and emails them a reset link, so the old password stops working at once.
+func (service *UserService) ForcePasswordReset(forcePasswordResetCommand *command.ForcePasswordResetCommand) error {
+	user, err := service.userRepository.FindById(forcePasswordResetCommand.Id)
+	if err != nil {
+		return err
+	}
+
+	password, err := util.HashPwd(uuid.NewString())
+	if err != nil {
+		return err
+	}
+	if err := user.UpdatePassword(password); err != nil {
+		return err
+	}
+
+	validatedUser, err := entity.NewValidatedUser(user)
+	if err != nil {
+		return err
+	}
+
+	if user, err = service.userRepository.Update(validatedUser); err != nil {
+		return err
+	}
+
+	if err := service.valkeyRepository.Delete(context.Background(), sessionKey(user.Id)); err != nil {
+		return err
+	}
+
+	return publishResetPassword(service.valkeyRepository, service.eventPublisher, user)
+}
+
+// DisableUser blocks every way of signing in and revokes the sessions the user
+// already has.
+func (service *UserService) DisableUser(disableUserCommand *command.DisableUserCommand) (*command.DisableUserCommandResult, error) {
+	if disableUserCommand.ActorId == disableUserCommand.Id {
+		return nil, entity.ErrSelfManagement
+	}
+
+	user, err := service.userRepository.FindById(disableUserCommand.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsDisabled() {
+		user.Disable()
+		if user, err = service.updateStatus(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := service.valkeyRepository.Delete(context.Background(), sessionKey(user.Id)); err != nil {
+		return nil, err
+	}
+
+	result := command.DisableUserCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+func (service *UserService) EnableUser(enableUserCommand *command.EnableUserCommand) (*command.EnableUserCommandResult, error) {
+	user, err := service.userRepository.FindById(enableUserCommand.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsDisabled() {
+		user.Enable()
+		if user, err = service.updateStatus(user); err != nil {
+			return nil, err
+		}
+	}
+
+	result := command.EnableUserCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+// DeleteUser schedules the deletion the same way users do it themselves, so
+// the user is told and can restore the account during the grace period.
+func (service *UserService) DeleteUser(deleteUserCommand *command.DeleteUserCommand) error {
+	if deleteUserCommand.ActorId == deleteUserCommand.Id {
+		return entity.ErrSelfManagement
+	}
+
+	user, err := service.userRepository.FindById(deleteUserCommand.Id)
+	if err != nil {
+		return err
+	}
+
+	return scheduleUserDeletion(service.userRepository, service.valkeyRepository, service.eventPublisher, user, service.deletionGracePeriod)
+}
+
+func (service *UserService) RestoreUser(restoreUserCommand *command.RestoreUserCommand) (*command.RestoreUserCommandResult, error) {
+	user, err := service.userRepository.FindById(restoreUserCommand.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsDeleted() {
+		user.Restore()
+		if user, err = service.updateStatus(user); err != nil {
+			return nil, err
+		}
+	}
+
+	result := command.RestoreUserCommandResult{
+		Result: mapper.NewUserResultFromEntity(user),
+	}
+
+	return &result, nil
+}
+
+// releaseLockScript only deletes the lock while it still holds the token it
+// was taken with, so a run that outlived the lock does not release the lock of
+// the instance that took it over.
+const releaseLockScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+// PurgeDeletedUsers removes the users whose grace period is over and publishes
+// a user-deleted event for each. Instances take turns through a lock in
+// valkey, so only one of them purges at a time.
+func (service *UserService) PurgeDeletedUsers() (int, error) {
+	ctx := context.Background()
+	token := uuid.NewString()
+	locked, err := service.valkeyRepository.SetNX(ctx, PURGE_DELETED_USERS_LOCK_KEY, token, int(PURGE_DELETED_USERS_LOCK_DURATION.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer service.valkeyRepository.Eval(ctx, releaseLockScript, []string{PURGE_DELETED_USERS_LOCK_KEY}, token)
+
+	before := time.Now().Add(-service.deletionGracePeriod)
+	purged := 0
+	for {
+		users, err := service.userRepository.FindDeletedBefore(before, PURGE_DELETED_USERS_BATCH_SIZE)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			// The user may have been restored since they were listed.
+			deleted, err := service.userRepository.DeleteIfDeletedBefore(user.Id, before)
+			if err != nil {
+				return purged, err
+			}
+			if !deleted {
+				continue
+			}
+			purged++
+
+			publishLifecycleEvent(service.eventPublisher, entity.USER_DELETED, user.Id, entity.NewUserDeletedEvent(user))
+		}
+
+		if len(users) < PURGE_DELETED_USERS_BATCH_SIZE {
+			return purged, nil
+		}
+	}
+}
+
+func (service *UserService) updateStatus(user *entity.User) (*entity.User, error) {
+	validatedUser, err := entity.NewValidatedUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.userRepository.UpdateStatus(validatedUser)
+}
@@ -0,0 +1,459 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/service"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUserService_ListUsers(t *testing.T) {
+	users := []*entity.User{
+		entity.NewUser("John Doe", "john@example.com", "hashed-password"),
+		entity.NewUser("Jane Doe", "jane@example.com", "hashed-password"),
+	}
+
+	t.Run("success: next cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		userCriteria := criteria.UserCriteria{Sort: criteria.USER_SORT_EMAIL, Limit: 2}
+		mockUserRepo.EXPECT().FindAll(&userCriteria).Return(users, nil)
+		mockUserRepo.EXPECT().Count(&userCriteria).Return(int64(5), nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		result, err := service.ListUsers(&command.ListUsersCommand{
+			Criteria: &userCriteria,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Result.Users, 2)
+		assert.Equal(t, int64(5), result.Result.Total)
+
+		cursor, err := criteria.DecodeUserCursor(result.Result.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, users[1].Id, cursor.Id)
+		assert.Equal(t, users[1].Email, cursor.Value)
+	})
+
+	t.Run("success: last page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		mockUserRepo.EXPECT().
+			FindAll(gomock.Any()).
+			DoAndReturn(func(userCriteria *criteria.UserCriteria) ([]*entity.User, error) {
+				assert.Equal(t, criteria.MAX_LIMIT, userCriteria.Limit)
+				return users, nil
+			})
+		mockUserRepo.EXPECT().Count(gomock.Any()).Return(int64(2), nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		result, err := service.ListUsers(&command.ListUsersCommand{
+			Criteria: &criteria.UserCriteria{Limit: 1000},
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Result.NextCursor)
+	})
+
+	t.Run("failure: repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		mockUserRepo.EXPECT().FindAll(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		_, err := service.ListUsers(&command.ListUsersCommand{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestUserService_UpdateUser(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+
+	t.Run("success: new email signs the user out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		dbUser := *user
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().FindByEmail("new@example.com").Return(nil, errors.New("record not found"))
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(user *entity.ValidatedUser) (*entity.User, error) {
+				return &user.User, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), fmt.Sprintf("user:%s:%s", user.Id, entity.EMAIL_CHANGE)).Return(nil)
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), fmt.Sprintf("user:%s:session", user.Id)).Return(nil)
+		mockEventPub.EXPECT().
+			PublishWithKey(entity.USER_EMAIL_CHANGED, []byte(user.Id.String()), gomock.Any()).
+			DoAndReturn(func(_ string, _ []byte, event interface{}) error {
+				changed := event.(entity.UserEmailChangedEvent)
+				assert.Equal(t, user.Email, changed.OldEmail)
+				assert.Equal(t, "new@example.com", changed.NewEmail)
+				return nil
+			})
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		result, err := service.UpdateUser(&command.UpdateUserCommand{
+			Id:    user.Id,
+			Name:  "Jane Doe",
+			Email: "new@example.com",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane Doe", result.Result.Name)
+		assert.Equal(t, "new@example.com", result.Result.Email)
+	})
+
+	t.Run("failure: email taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		dbUser := *user
+		other := entity.NewUser("Jane Doe", "taken@example.com", "hashed-password")
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().FindByEmail(other.Email).Return(other, nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		_, err := service.UpdateUser(&command.UpdateUserCommand{
+			Id:    user.Id,
+			Name:  user.Name,
+			Email: other.Email,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrEmailTaken)
+	})
+}
+
+func TestUserService_ForcePasswordReset(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		dbUser := *user
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(updated *entity.ValidatedUser) (*entity.User, error) {
+				assert.NotEqual(t, user.Password, updated.Password)
+				return &updated.User, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), fmt.Sprintf("user:%s:session", user.Id)).Return(nil)
+		mockValkeyRepo.EXPECT().
+			Set(gomock.Any(), fmt.Sprintf("user:%s:%s", user.Id, entity.RESET_PASSWORD), gomock.Any(), 60*60).
+			Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.RESET_PASSWORD, []byte(user.Email), gomock.Any()).Return(nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		err := service.ForcePasswordReset(&command.ForcePasswordResetCommand{
+			Id: user.Id,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: user not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		mockUserRepo.EXPECT().FindById(user.Id).Return(nil, errors.New("record not found"))
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		err := service.ForcePasswordReset(&command.ForcePasswordResetCommand{
+			Id: user.Id,
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestUserService_DisableUser(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+	adminId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		dbUser := *user
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			UpdateStatus(gomock.Any()).
+			DoAndReturn(func(user *entity.ValidatedUser) (*entity.User, error) {
+				return &user.User, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), fmt.Sprintf("user:%s:session", user.Id)).Return(nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		result, err := service.DisableUser(&command.DisableUserCommand{
+			ActorId: adminId,
+			Id:      user.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result.Result.DisabledAt)
+	})
+
+	t.Run("failure: self", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		_, err := service.DisableUser(&command.DisableUserCommand{
+			ActorId: user.Id,
+			Id:      user.Id,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrSelfManagement)
+	})
+}
+
+func TestUserService_EnableUser(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		dbUser := *user
+		dbUser.Disable()
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			UpdateStatus(gomock.Any()).
+			DoAndReturn(func(user *entity.ValidatedUser) (*entity.User, error) {
+				return &user.User, nil
+			})
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		result, err := service.EnableUser(&command.EnableUserCommand{
+			Id: user.Id,
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Result.DisabledAt)
+	})
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+	adminId := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		dbUser := *user
+		mockUserRepo.EXPECT().FindById(user.Id).Return(&dbUser, nil)
+		mockUserRepo.EXPECT().
+			UpdateStatus(gomock.Any()).
+			DoAndReturn(func(user *entity.ValidatedUser) (*entity.User, error) {
+				assert.True(t, user.IsDeleted())
+				return &user.User, nil
+			})
+		mockValkeyRepo.EXPECT().Delete(gomock.Any(), fmt.Sprintf("user:%s:session", user.Id)).Return(nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_DELETION_SCHEDULED, []byte(user.Email), gomock.Any()).Return(nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		err := service.DeleteUser(&command.DeleteUserCommand{
+			ActorId: adminId,
+			Id:      user.Id,
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure: self", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		err := service.DeleteUser(&command.DeleteUserCommand{
+			ActorId: user.Id,
+			Id:      user.Id,
+		})
+
+		assert.ErrorIs(t, err, entity.ErrSelfManagement)
+	})
+}
+
+func TestUserService_PurgeDeletedUsers(t *testing.T) {
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+	user.MarkDeleted()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		var lockToken string
+		mockValkeyRepo.EXPECT().
+			SetNX(gomock.Any(), service.PURGE_DELETED_USERS_LOCK_KEY, gomock.Any(), 10*60).
+			DoAndReturn(func(_ any, _ string, value any, _ int) (bool, error) {
+				lockToken = value.(string)
+				return true, nil
+			})
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{service.PURGE_DELETED_USERS_LOCK_KEY}, gomock.Any()).
+			DoAndReturn(func(_ any, _ string, _ []string, args ...any) (any, error) {
+				assert.Equal(t, lockToken, args[0])
+				return int64(1), nil
+			})
+		mockUserRepo.EXPECT().FindDeletedBefore(gomock.Any(), service.PURGE_DELETED_USERS_BATCH_SIZE).Return([]*entity.User{user}, nil)
+		mockUserRepo.EXPECT().DeleteIfDeletedBefore(user.Id, gomock.Any()).Return(true, nil)
+		mockEventPub.EXPECT().PublishWithKey(entity.USER_DELETED, []byte(user.Id.String()), gomock.Any()).Return(nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		purged, err := service.PurgeDeletedUsers()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+	})
+
+	t.Run("success: restored since listed is kept", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		mockValkeyRepo.EXPECT().SetNX(gomock.Any(), service.PURGE_DELETED_USERS_LOCK_KEY, gomock.Any(), 10*60).Return(true, nil)
+		mockValkeyRepo.EXPECT().Eval(gomock.Any(), gomock.Any(), []string{service.PURGE_DELETED_USERS_LOCK_KEY}, gomock.Any()).Return(int64(1), nil)
+		mockUserRepo.EXPECT().FindDeletedBefore(gomock.Any(), service.PURGE_DELETED_USERS_BATCH_SIZE).Return([]*entity.User{user}, nil)
+		mockUserRepo.EXPECT().DeleteIfDeletedBefore(user.Id, gomock.Any()).Return(false, nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		purged, err := service.PurgeDeletedUsers()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, purged)
+	})
+
+	t.Run("success: another instance is purging", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		mockValkeyRepo.EXPECT().SetNX(gomock.Any(), service.PURGE_DELETED_USERS_LOCK_KEY, gomock.Any(), 10*60).Return(false, nil)
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		purged, err := service.PurgeDeletedUsers()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, purged)
+	})
+
+	t.Run("failure: delete error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockEventPub := mocks.NewMockEventPublisher(ctrl)
+
+		var lockToken string
+		mockValkeyRepo.EXPECT().
+			SetNX(gomock.Any(), service.PURGE_DELETED_USERS_LOCK_KEY, gomock.Any(), 10*60).
+			DoAndReturn(func(_ any, _ string, value any, _ int) (bool, error) {
+				lockToken = value.(string)
+				return true, nil
+			})
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{service.PURGE_DELETED_USERS_LOCK_KEY}, gomock.Any()).
+			DoAndReturn(func(_ any, _ string, _ []string, args ...any) (any, error) {
+				assert.Equal(t, lockToken, args[0])
+				return int64(1), nil
+			})
+		mockUserRepo.EXPECT().FindDeletedBefore(gomock.Any(), service.PURGE_DELETED_USERS_BATCH_SIZE).Return([]*entity.User{user}, nil)
+		mockUserRepo.EXPECT().DeleteIfDeletedBefore(user.Id, gomock.Any()).Return(false, errors.New("connection refused"))
+
+		service := service.NewUserService(mockEventPub, mockValkeyRepo, mockUserRepo, time.Hour)
+
+		_, err := service.PurgeDeletedUsers()
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,52 @@
+package criteria
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEventCriteria filters audit events, which are always listed newest
+// first.
+type AuditEventCriteria struct {
+	UserId        *uuid.UUID
+	Action        *string
+	Outcome       *string
+	IpAddress     *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Page          int
+	Limit         int
+}
+
+func (event *AuditEventCriteria) WithUserId(userId *uuid.UUID) *AuditEventCriteria {
+	event.UserId = userId
+	return event
+}
+
+func (event *AuditEventCriteria) WithAction(action *string) *AuditEventCriteria {
+	event.Action = action
+	return event
+}
+
+func (event *AuditEventCriteria) WithOutcome(outcome *string) *AuditEventCriteria {
+	event.Outcome = outcome
+	return event
+}
+
+func (event *AuditEventCriteria) WithIpAddress(ipAddress *string) *AuditEventCriteria {
+	event.IpAddress = ipAddress
+	return event
+}
+
+func (event *AuditEventCriteria) WithCreated(after *time.Time, before *time.Time) *AuditEventCriteria {
+	event.CreatedAfter = after
+	event.CreatedBefore = before
+	return event
+}
+
+func (event *AuditEventCriteria) WithPage(page int, limit int) *AuditEventCriteria {
+	event.Page = page
+	event.Limit = limit
+	return event
+}
@@ -1,10 +1,50 @@
 package criteria
 
-import "github.com/google/uuid"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/google/uuid"
+)
+
+const (
+	USER_SORT_CREATED_AT = "created_at"
+	USER_SORT_NAME       = "name"
+	USER_SORT_EMAIL      = "email"
+)
+
+const (
+	USER_STATUS_ACTIVE   = "active"
+	USER_STATUS_DISABLED = "disabled"
+	USER_STATUS_DELETED  = "deleted"
+)
+
+const (
+	DEFAULT_LIMIT = 20
+	MAX_LIMIT     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// UserCriteria filters and pages users. Name and Email match any part of the
+// value, ignoring case. A Cursor takes precedence over Page.
 type UserCriteria struct {
-	Id   uuid.UUID
-	Name *string
+	Id            uuid.UUID
+	Name          *string
+	Email         *string
+	Status        *string
+	EmailVerified *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	Sort          string
+	Desc          bool
+	Page          int
+	Limit         int
+	Cursor        *UserCursor
 }
 
 func (user *UserCriteria) WithId(id uuid.UUID) *UserCriteria {
@@ -16,3 +56,97 @@ func (user *UserCriteria) WithName(name *string) *UserCriteria {
 	user.Name = name
 	return user
 }
+
+func (user *UserCriteria) WithEmail(email *string) *UserCriteria {
+	user.Email = email
+	return user
+}
+
+func (user *UserCriteria) WithStatus(status *string) *UserCriteria {
+	user.Status = status
+	return user
+}
+
+func (user *UserCriteria) WithEmailVerified(emailVerified *bool) *UserCriteria {
+	user.EmailVerified = emailVerified
+	return user
+}
+
+func (user *UserCriteria) WithCreated(after *time.Time, before *time.Time) *UserCriteria {
+	user.CreatedAfter = after
+	user.CreatedBefore = before
+	return user
+}
+
+func (user *UserCriteria) WithUpdated(after *time.Time, before *time.Time) *UserCriteria {
+	user.UpdatedAfter = after
+	user.UpdatedBefore = before
+	return user
+}
+
+func (user *UserCriteria) WithSort(sort string, desc bool) *UserCriteria {
+	user.Sort = sort
+	user.Desc = desc
+	return user
+}
+
+func (user *UserCriteria) WithPage(page int, limit int) *UserCriteria {
+	user.Page = page
+	user.Limit = limit
+	return user
+}
+
+func (user *UserCriteria) WithCursor(cursor *UserCursor) *UserCriteria {
+	user.Cursor = cursor
+	return user
+}
+
+// SortColumn is the column users are ordered by, created_at unless another
+// one is set.
+func (user *UserCriteria) SortColumn() string {
+	switch user.Sort {
+	case USER_SORT_NAME, USER_SORT_EMAIL:
+		return user.Sort
+	default:
+		return USER_SORT_CREATED_AT
+	}
+}
+
+// UserCursor points at the last user of a page: the value of the sort column
+// and the id, which breaks ties between equal values.
+type UserCursor struct {
+	Value string    `json:"v"`
+	Id    uuid.UUID `json:"id"`
+}
+
+func NewUserCursor(sortColumn string, id uuid.UUID, name string, email string, createdAt time.Time) *UserCursor {
+	cursor := UserCursor{Id: id}
+	switch sortColumn {
+	case USER_SORT_NAME:
+		cursor.Value = name
+	case USER_SORT_EMAIL:
+		cursor.Value = email
+	default:
+		cursor.Value = createdAt.UTC().Format(time.RFC3339Nano)
+	}
+	return &cursor
+}
+
+func (cursor *UserCursor) Encode() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeUserCursor(encoded string) (*UserCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor UserCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Id == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AUDIT_OUTCOME_SUCCESS = "success"
+	AUDIT_OUTCOME_FAILURE = "failure"
+)
+
+// Actions recorded by the audit log, one per sign in or account method.
+const (
+	AUDIT_ACTION_PROFILE_VIEWED            = "profile.viewed"
+	AUDIT_ACTION_REGISTER                  = "register"
+	AUDIT_ACTION_LOGIN                     = "login"
+	AUDIT_ACTION_LOGIN_MAGIC_LINK          = "login.magic_link"
+	AUDIT_ACTION_LOGIN_PASSKEY             = "login.passkey"
+	AUDIT_ACTION_PROFILE_UPDATED           = "profile.updated"
+	AUDIT_ACTION_EMAIL_CHANGE_CONFIRMED    = "email_change.confirmed"
+	AUDIT_ACTION_EMAIL_CHANGE_REVERTED     = "email_change.reverted"
+	AUDIT_ACTION_PASSWORD_RESET_REQUESTED  = "password_reset.requested"
+	AUDIT_ACTION_PASSWORD_RESET            = "password_reset.completed"
+	AUDIT_ACTION_EMAIL_VERIFIED            = "email.verified"
+	AUDIT_ACTION_EMAIL_VERIFICATION_RESENT = "email.verification_resent"
+	AUDIT_ACTION_MAGIC_LINK_REQUESTED      = "magic_link.requested"
+	AUDIT_ACTION_ACCOUNT_DELETED           = "account.deleted"
+	AUDIT_ACTION_ACCOUNT_RESTORED          = "account.restored"
+)
+
+// AuditEvent records one security relevant action. Events are only ever
+// appended, never removed, and only changed to drop the email and ip address
+// of a purged user.
+type AuditEvent struct {
+	Id        uuid.UUID
+	CreatedAt time.Time
+	// UserId is the account the action was about, nil when none matched.
+	UserId *uuid.UUID
+	// ActorId is set once the caller proved to be the user, by a session, a
+	// password or an emailed token.
+	ActorId   *uuid.UUID
+	Email     string
+	Action    string
+	Outcome   string
+	Reason    string
+	IpAddress string
+	UserAgent string
+}
+
+func (e *AuditEvent) validate() error {
+	if e.Action == "" {
+		return errors.New("action must not be empty")
+	}
+	if e.Outcome != AUDIT_OUTCOME_SUCCESS && e.Outcome != AUDIT_OUTCOME_FAILURE {
+		return errors.New("outcome must be success or failure")
+	}
+
+	return nil
+}
+
+func NewAuditEvent(action string, email string, ipAddress string, userAgent string) *AuditEvent {
+	return &AuditEvent{
+		Id:        uuid.New(),
+		CreatedAt: time.Now(),
+		Email:     email,
+		Action:    action,
+		IpAddress: ipAddress,
+		UserAgent: userAgent,
+	}
+}
+
+// ForUser names the account the action is about.
+func (e *AuditEvent) ForUser(user *User) {
+	e.UserId = &user.Id
+	e.Email = user.Email
+}
+
+// ActedBy marks the user as the actor as well, once they proved who they are.
+func (e *AuditEvent) ActedBy(user *User) {
+	e.ForUser(user)
+	e.ActorId = &user.Id
+}
+
+// Conclude sets the outcome from the error the action ended with. A reason
+// given beforehand is kept over the error's message.
+func (e *AuditEvent) Conclude(err error) {
+	if err == nil {
+		e.Outcome = AUDIT_OUTCOME_SUCCESS
+		e.Reason = ""
+		return
+	}
+
+	e.Outcome = AUDIT_OUTCOME_FAILURE
+	if e.Reason == "" {
+		e.Reason = err.Error()
+	}
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DATA_EXPORT = "data-export"
+
+	DATA_EXPORT_STATUS_PENDING = "pending"
+	DATA_EXPORT_STATUS_READY   = "ready"
+	DATA_EXPORT_STATUS_FAILED  = "failed"
+)
+
+var (
+	ErrDataExportNotFound   = errors.New("data export not found or expired")
+	ErrDataExportInProgress = errors.New("a data export is already in progress")
+	ErrDataExportInvalid    = errors.New("invalid or expired data export link")
+)
+
+// DataExport gathers everything held about a user into an archive, which can
+// be downloaded until it expires.
+type DataExport struct {
+	Id          uuid.UUID
+	UserId      uuid.UUID
+	Status      string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+func NewDataExport(userId uuid.UUID) *DataExport {
+	return &DataExport{
+		Id:        uuid.New(),
+		UserId:    userId,
+		Status:    DATA_EXPORT_STATUS_PENDING,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (e *DataExport) IsPending() bool {
+	return e.Status == DATA_EXPORT_STATUS_PENDING
+}
+
+func (e *DataExport) IsReady() bool {
+	return e.Status == DATA_EXPORT_STATUS_READY
+}
+
+func (e *DataExport) Complete(expiresAt time.Time) {
+	now := time.Now()
+	e.Status = DATA_EXPORT_STATUS_READY
+	e.CompletedAt = &now
+	e.ExpiresAt = &expiresAt
+}
+
+func (e *DataExport) Fail() {
+	now := time.Now()
+	e.Status = DATA_EXPORT_STATUS_FAILED
+	e.CompletedAt = &now
+}
+
+// FileName is where the archive is stored, unique to the export so a newer
+// one never overwrites an archive that is still being downloaded.
+func (e *DataExport) FileName() string {
+	return e.Id.String() + ".zip"
+}
+
+type DataExportRequestedEvent struct {
+	Id     uuid.UUID
+	UserId uuid.UUID
+}
+
+type DataExportReadyEvent struct {
+	Email string
+	Name  string
+	Token string
+	Exp   time.Time
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+// LoginLockoutPolicy locks an email or a client ip out once its failed logins
+// reach the threshold. Every further failure doubles the lockout, starting at
+// BaseDuration and capped at MaxDuration. Failures are forgotten after Window
+// without a new one. A zero threshold disables that lockout.
+type LoginLockoutPolicy struct {
+	EmailThreshold int
+	IpThreshold    int
+	Window         time.Duration
+	BaseDuration   time.Duration
+	MaxDuration    time.Duration
+}
+
+func (policy LoginLockoutPolicy) LockoutDuration(failures int64, threshold int) time.Duration {
+	if threshold <= 0 || failures < int64(threshold) {
+		return 0
+	}
+
+	duration := policy.BaseDuration
+	for i := int64(threshold); i < failures && duration < policy.MaxDuration; i++ {
+		duration *= 2
+	}
+	if policy.MaxDuration > 0 && duration > policy.MaxDuration {
+		duration = policy.MaxDuration
+	}
+
+	return duration
+}
+
+// LoginLockedError is ErrLoginLocked with the time left until the next attempt
+// is allowed.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (err *LoginLockedError) Error() string {
+	return ErrLoginLocked.Error()
+}
+
+func (err *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+type LoginLockedEvent struct {
+	Email     string
+	Name      string
+	IpAddress string
+	Until     time.Time
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes only granted to machine clients through the client credentials grant.
+const (
+	SCOPE_USERS_READ = "users:read"
+)
+
+var MACHINE_SCOPES = []string{SCOPE_USERS_READ}
+
+// MachineClient is a backend service authenticating as itself rather than on
+// behalf of a user. It is always confidential.
+type MachineClient struct {
+	Id         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ClientId   string
+	SecretHash string
+	Name       string
+	Scopes     []string
+}
+
+func (c *MachineClient) validate() error {
+	if c.ClientId == "" {
+		return errors.New("client id must not be empty")
+	}
+	if c.SecretHash == "" {
+		return errors.New("secret must not be empty")
+	}
+	if c.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if len(c.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range c.Scopes {
+		if !slices.Contains(MACHINE_SCOPES, scope) {
+			return fmt.Errorf("unsupported scope %q", scope)
+		}
+	}
+
+	return nil
+}
+
+func NewMachineClient(clientId string, secretHash string, name string, scopes []string) *MachineClient {
+	return &MachineClient{
+		Id:         uuid.New(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		ClientId:   clientId,
+		SecretHash: secretHash,
+		Name:       name,
+		Scopes:     scopes,
+	}
+}
+
+func (c *MachineClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AUTHORIZATION_CODE          = "authorization-code"
+	AUTHORIZATION_CODE_DURATION = time.Minute
+)
+
+const (
+	GRANT_TYPE_AUTHORIZATION_CODE = "authorization_code"
+	GRANT_TYPE_REFRESH_TOKEN      = "refresh_token"
+	GRANT_TYPE_CLIENT_CREDENTIALS = "client_credentials"
+)
+
+// Token type hints from RFC 7009 section 2.1, also used for token_type in
+// introspection responses.
+const (
+	TOKEN_TYPE_HINT_ACCESS_TOKEN  = "access_token"
+	TOKEN_TYPE_HINT_REFRESH_TOKEN = "refresh_token"
+)
+
+const (
+	SCOPE_OPENID  = "openid"
+	SCOPE_PROFILE = "profile"
+	SCOPE_EMAIL   = "email"
+)
+
+var SUPPORTED_SCOPES = []string{SCOPE_OPENID, SCOPE_PROFILE, SCOPE_EMAIL}
+
+// Error codes from RFC 6749 section 4.1.2.1 and 5.2.
+const (
+	OAUTH_INVALID_REQUEST           = "invalid_request"
+	OAUTH_INVALID_CLIENT            = "invalid_client"
+	OAUTH_INVALID_GRANT             = "invalid_grant"
+	OAUTH_INVALID_SCOPE             = "invalid_scope"
+	OAUTH_UNAUTHORIZED_CLIENT       = "unauthorized_client"
+	OAUTH_ACCESS_DENIED             = "access_denied"
+	OAUTH_UNSUPPORTED_GRANT_TYPE    = "unsupported_grant_type"
+	OAUTH_UNSUPPORTED_RESPONSE_TYPE = "unsupported_response_type"
+	OAUTH_SERVER_ERROR              = "server_error"
+)
+
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func NewOAuthError(code string, description string) *OAuthError {
+	return &OAuthError{
+		Code:        code,
+		Description: description,
+	}
+}
+
+func (e *OAuthError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+type AuthorizationCode struct {
+	Code          string
+	ClientId      string
+	UserId        uuid.UUID
+	RedirectUri   string
+	Scope         string
+	CodeChallenge string
+	Nonce         string
+	AuthTime      time.Time
+	ExpiresAt     time.Time
+}
+
+func NewAuthorizationCode(code string, clientId string, userId uuid.UUID, redirectUri string, scope string, codeChallenge string, nonce string, authTime time.Time, ttl time.Duration) *AuthorizationCode {
+	return &AuthorizationCode{
+		Code:          code,
+		ClientId:      clientId,
+		UserId:        userId,
+		RedirectUri:   redirectUri,
+		Scope:         scope,
+		CodeChallenge: codeChallenge,
+		Nonce:         nonce,
+		AuthTime:      authTime,
+		ExpiresAt:     time.Now().Add(ttl),
+	}
+}
+
+func (c *AuthorizationCode) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// ParseScope splits a space delimited scope parameter, dropping duplicates.
+func ParseScope(scope string) []string {
+	scopes := make([]string, 0)
+	for _, s := range strings.Fields(scope) {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OAuthClient struct {
+	Id           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	OwnerId      uuid.UUID
+	ClientId     string
+	SecretHash   string
+	Name         string
+	RedirectUris []string
+	Scopes       []string
+}
+
+func (c *OAuthClient) validate() error {
+	if c.ClientId == "" {
+		return errors.New("client id must not be empty")
+	}
+	if c.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if len(c.RedirectUris) == 0 {
+		return errors.New("at least one redirect uri is required")
+	}
+	for _, redirectUri := range c.RedirectUris {
+		if err := validateRedirectUri(redirectUri); err != nil {
+			return err
+		}
+	}
+	for _, scope := range c.Scopes {
+		if !slices.Contains(SUPPORTED_SCOPES, scope) {
+			return fmt.Errorf("unsupported scope %q", scope)
+		}
+	}
+
+	return nil
+}
+
+func NewOAuthClient(ownerId uuid.UUID, clientId string, secretHash string, name string, redirectUris []string, scopes []string) *OAuthClient {
+	return &OAuthClient{
+		Id:           uuid.New(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		OwnerId:      ownerId,
+		ClientId:     clientId,
+		SecretHash:   secretHash,
+		Name:         name,
+		RedirectUris: redirectUris,
+		Scopes:       scopes,
+	}
+}
+
+// IsPublic reports whether the client cannot keep a secret, like a SPA or a
+// mobile app. Public clients must always use PKCE.
+func (c *OAuthClient) IsPublic() bool {
+	return c.SecretHash == ""
+}
+
+// HasRedirectUri compares redirect uris exactly, as required by OAuth 2.1.
+func (c *OAuthClient) HasRedirectUri(redirectUri string) bool {
+	return slices.Contains(c.RedirectUris, redirectUri)
+}
+
+func (c *OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateRedirectUri(redirectUri string) error {
+	u, err := url.Parse(redirectUri)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("redirect uri %q must be an absolute url", redirectUri)
+	}
+	if u.Fragment != "" {
+		return fmt.Errorf("redirect uri %q must not contain a fragment", redirectUri)
+	}
+	if u.Scheme != "https" && !(u.Scheme == "http" && isLoopback(u.Hostname())) {
+		return fmt.Errorf("redirect uri %q must use https", redirectUri)
+	}
+
+	return nil
+}
+
+func isLoopback(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OAUTH_CONSENT_REQUEST          = "oauth-consent-request"
+	OAUTH_CONSENT_REQUEST_DURATION = 10 * time.Minute
+)
+
+// OAuthConsent records the scopes a user has granted a client, so the user is
+// only asked again when the client wants more.
+type OAuthConsent struct {
+	Id        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserId    uuid.UUID
+	ClientId  string
+	Scopes    []string
+}
+
+func (c *OAuthConsent) validate() error {
+	if c.UserId == uuid.Nil {
+		return errors.New("user id must not be empty")
+	}
+	if c.ClientId == "" {
+		return errors.New("client id must not be empty")
+	}
+
+	return nil
+}
+
+func NewOAuthConsent(userId uuid.UUID, clientId string, scopes []string) *OAuthConsent {
+	return &OAuthConsent{
+		Id:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserId:    userId,
+		ClientId:  clientId,
+		Scopes:    scopes,
+	}
+}
+
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Grant adds the scopes to the ones already granted.
+func (c *OAuthConsent) Grant(scopes []string) {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			c.Scopes = append(c.Scopes, scope)
+		}
+	}
+	c.UpdatedAt = time.Now()
+}
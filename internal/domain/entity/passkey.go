@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Valkey key names of the pending WebAuthn ceremonies.
+const (
+	WEBAUTHN_REGISTRATION = "webauthn-registration"
+	WEBAUTHN_LOGIN        = "webauthn-login"
+)
+
+const WEBAUTHN_CEREMONY_DURATION = 5 * time.Minute
+
+var (
+	ErrPasskeyNotFound          = errors.New("passkey not found")
+	ErrPasskeyCeremonyExpired   = errors.New("webauthn ceremony expired, start again")
+	ErrPasskeyAlreadyRegistered = errors.New("passkey is already registered")
+)
+
+// Passkey is a WebAuthn credential a user can sign in with instead of their
+// password.
+type Passkey struct {
+	Id           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserId       uuid.UUID
+	Name         string
+	CredentialId []byte
+	PublicKey    []byte
+	SignCount    uint32
+	Transports   []string
+	LastUsedAt   *time.Time
+}
+
+func (p *Passkey) validate() error {
+	if p.UserId == uuid.Nil {
+		return errors.New("user id must not be empty")
+	}
+	if p.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if len(p.CredentialId) == 0 {
+		return errors.New("credential id must not be empty")
+	}
+	if len(p.PublicKey) == 0 {
+		return errors.New("public key must not be empty")
+	}
+
+	return nil
+}
+
+func NewPasskey(userId uuid.UUID, name string, credentialId []byte, publicKey []byte, signCount uint32, transports []string) *Passkey {
+	return &Passkey{
+		Id:           uuid.New(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		UserId:       userId,
+		Name:         name,
+		CredentialId: credentialId,
+		PublicKey:    publicKey,
+		SignCount:    signCount,
+		Transports:   transports,
+	}
+}
+
+func (p *Passkey) Use(signCount uint32) {
+	now := time.Now()
+	p.SignCount = signCount
+	p.LastUsedAt = &now
+	p.UpdatedAt = now
+}
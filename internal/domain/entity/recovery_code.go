@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const RECOVERY_CODE_COUNT = 10
+
+var ErrRecoveryCodeInvalid = errors.New("invalid or already used recovery code")
+
+// RecoveryCode can be used once in place of a second factor when the
+// authenticator is lost. Only its hash is stored.
+type RecoveryCode struct {
+	Id        uuid.UUID
+	CreatedAt time.Time
+	UserId    uuid.UUID
+	CodeHash  string
+	UsedAt    *time.Time
+}
+
+func (c *RecoveryCode) validate() error {
+	if c.UserId == uuid.Nil {
+		return errors.New("user id must not be empty")
+	}
+	if c.CodeHash == "" {
+		return errors.New("code hash must not be empty")
+	}
+
+	return nil
+}
+
+func NewRecoveryCode(userId uuid.UUID, codeHash string) *RecoveryCode {
+	return &RecoveryCode{
+		Id:        uuid.New(),
+		CreatedAt: time.Now(),
+		UserId:    userId,
+		CodeHash:  codeHash,
+	}
+}
+
+// NormalizeRecoveryCode lets users type a code with or without its dash and
+// in any case.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToLower(code)
+}
+
+type RecoveryCodesRegeneratedEvent struct {
+	Email string
+	Name  string
+	Time  time.Time
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const ROLE_ADMIN = "admin"
+
+const (
+	PERMISSION_USERS_READ   = "users:read"
+	PERMISSION_USERS_WRITE  = "users:write"
+	PERMISSION_ROLES_MANAGE = "roles:manage"
+	PERMISSION_AUDIT_READ   = "audit:read"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrLastAdmin    = errors.New("the last admin cannot be removed")
+)
+
+// Role grants its permissions to the users it is assigned to. A role that
+// requires MFA only grants them once the user has enabled it.
+type Role struct {
+	Id          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Description string
+	Permissions []string
+	RequireMfa  bool
+}
+
+func (r *Role) validate() error {
+	if r.Name == "" {
+		return errors.New("name must not be empty")
+	}
+
+	return nil
+}
+
+func NewRole(name string, description string, permissions []string, requireMfa bool) *Role {
+	return &Role{
+		Id:          uuid.New(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		RequireMfa:  requireMfa,
+	}
+}
+
+// NewAdminRole is the role seeded at startup. Security requires admins to use
+// MFA.
+func NewAdminRole() *Role {
+	return NewRole(ROLE_ADMIN, "Manages users and their roles", []string{
+		PERMISSION_USERS_READ,
+		PERMISSION_USERS_WRITE,
+		PERMISSION_ROLES_MANAGE,
+		PERMISSION_AUDIT_READ,
+	}, true)
+}
+
+func (r *Role) HasPermission(permission string) bool {
+	return slices.Contains(r.Permissions, permission)
+}
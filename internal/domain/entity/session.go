@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SESSION = "session"
+)
+
+var ErrSessionRevoked = errors.New("session has been revoked or has expired")
+
+type Session struct {
+	Id         uuid.UUID
+	UserId     uuid.UUID
+	Device     string
+	IpAddress  string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+func NewSession(userId uuid.UUID, device string, ipAddress string, userAgent string) *Session {
+	return &Session{
+		Id:         uuid.New(),
+		UserId:     userId,
+		Device:     device,
+		IpAddress:  ipAddress,
+		UserAgent:  userAgent,
+		CreatedAt:  time.Now(),
+		LastSeenAt: time.Now(),
+	}
+}
+
+func (s *Session) Touch() {
+	s.LastSeenAt = time.Now()
+}
+
+func (s *Session) IsIdle(timeout time.Duration) bool {
+	return time.Since(s.LastSeenAt) > timeout
+}
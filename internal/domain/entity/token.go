@@ -0,0 +1,14 @@
+package entity
+
+import "errors"
+
+const (
+	REFRESH_TOKEN_FAMILY  = "refresh-token-family"
+	ACCESS_TOKEN_DENYLIST = "access-token-denylist"
+)
+
+var (
+	ErrAccessTokenRevoked  = errors.New("access token has been revoked")
+	ErrRefreshTokenRevoked = errors.New("refresh token has been revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
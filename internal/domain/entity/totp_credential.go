@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Valkey key names used by multi-factor authentication.
+const (
+	TOTP_ENROLLMENT = "totp-enrollment"
+	TOTP_LAST_STEP  = "totp-last-step"
+	MFA_CHALLENGE   = "mfa-challenge"
+)
+
+const TOTP_ENROLLMENT_DURATION = 10 * time.Minute
+
+// A challenge is dropped after this many wrong codes and the user has to sign
+// in with their password again.
+const MFA_CHALLENGE_MAX_ATTEMPTS = 5
+
+var (
+	ErrMfaInvalidCode       = errors.New("invalid verification code")
+	ErrMfaAlreadyEnabled    = errors.New("multi-factor authentication is already enabled")
+	ErrMfaNotEnabled        = errors.New("multi-factor authentication is not enabled")
+	ErrMfaEnrollmentExpired = errors.New("no pending enrollment, start again")
+	ErrMfaChallengeInvalid  = errors.New("mfa challenge is invalid or expired")
+)
+
+// TotpCredential is the confirmed authenticator app of a user. Unconfirmed
+// enrollments only live in Valkey.
+type TotpCredential struct {
+	Id        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserId    uuid.UUID
+	Secret    string
+}
+
+func (c *TotpCredential) validate() error {
+	if c.UserId == uuid.Nil {
+		return errors.New("user id must not be empty")
+	}
+	if c.Secret == "" {
+		return errors.New("secret must not be empty")
+	}
+
+	return nil
+}
+
+func NewTotpCredential(userId uuid.UUID, secret string) *TotpCredential {
+	return &TotpCredential{
+		Id:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserId:    userId,
+		Secret:    secret,
+	}
+}
@@ -8,16 +8,63 @@ import (
 )
 
 const (
-	RESET_PASSWORD = "reset-password"
+	RESET_PASSWORD             = "reset-password"
+	RECOVERY_CODES_REGENERATED = "recovery-codes-regenerated"
+	MAGIC_LINK                 = "magic-link"
+	VERIFY_EMAIL               = "verify-email"
+	EMAIL_CHANGE               = "email-change"
+	LOGIN_LOCKED               = "login-locked"
+	USER_DELETION_SCHEDULED    = "user-deletion-scheduled"
+	DATA_EXPORT_REQUESTED      = "data-export-requested"
+	DATA_EXPORT_READY          = "data-export-ready"
+)
+
+// Topics of the user lifecycle events, see user_lifecycle_event.go. Other
+// services depend on these names, they must not change.
+const (
+	USER_REGISTERED       = "user-registered"
+	USER_LOGGED_IN        = "user-logged-in"
+	USER_PROFILE_UPDATED  = "user-profile-updated"
+	USER_EMAIL_CHANGED    = "user-email-changed"
+	USER_PASSWORD_CHANGED = "user-password-changed"
+	USER_DELETED          = "user-deleted"
+)
+
+// What unverified users may do: sign in as usual, only read until they verify,
+// or not sign in at all.
+const (
+	EMAIL_VERIFICATION_OFF      = "off"
+	EMAIL_VERIFICATION_RESTRICT = "restrict"
+	EMAIL_VERIFICATION_BLOCK    = "block"
+)
+
+const EMAIL_VERIFICATION_RESEND_COOLDOWN = time.Minute
+
+var (
+	ErrMagicLinkInvalid         = errors.New("invalid or already used magic link")
+	ErrEmailNotVerified         = errors.New("email is not verified")
+	ErrEmailVerificationInvalid = errors.New("invalid email verification token")
+	ErrEmailTaken               = errors.New("email is already in use")
+	ErrEmailChangeInvalid       = errors.New("invalid or expired email change")
+	ErrUserDisabled             = errors.New("user is disabled")
+	ErrSelfManagement           = errors.New("admins cannot disable or delete themselves")
+	ErrUserDeleted              = errors.New("user is pending deletion")
+	ErrRestoreAccountInvalid    = errors.New("invalid or expired restore link")
 )
 
 type User struct {
-	Id        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Email     string
-	Password  string
+	Id            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Name          string
+	Email         string
+	Password      string
+	EmailVerified bool
+	VerifiedAt    *time.Time
+	DisabledAt    *time.Time
+	// DeletedAt is set while the account waits out the grace period before it
+	// is purged, during which it can still be restored.
+	DeletedAt *time.Time
 }
 
 func (u *User) validate() error {
@@ -59,6 +106,54 @@ func (u *User) UpdateEmail(email string) error {
 	return u.validate()
 }
 
+func (u *User) VerifyEmail() {
+	now := time.Now()
+	u.EmailVerified = true
+	u.VerifiedAt = &now
+	u.UpdatedAt = now
+}
+
+func (u *User) Disable() {
+	now := time.Now()
+	u.DisabledAt = &now
+	u.UpdatedAt = now
+}
+
+func (u *User) Enable() {
+	u.DisabledAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+func (u *User) MarkDeleted() {
+	now := time.Now()
+	u.DeletedAt = &now
+	u.UpdatedAt = now
+}
+
+func (u *User) Restore() {
+	u.DeletedAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// SignInError tells why the user may not sign in, or nil when they may.
+func (u *User) SignInError() error {
+	if u.IsDeleted() {
+		return ErrUserDeleted
+	}
+	if u.IsDisabled() {
+		return ErrUserDisabled
+	}
+	return nil
+}
+
 func (u *User) UpdatePassword(password string) error {
 	u.Password = password
 	u.UpdatedAt = time.Now()
@@ -71,3 +166,36 @@ type ResetPasswordEvent struct {
 	Token string
 	Exp   time.Time
 }
+
+type VerifyEmailEvent struct {
+	Email string
+	Name  string
+	Token string
+	Exp   time.Time
+}
+
+// EmailChangeEvent asks the new address to confirm the change and gives the
+// old address a way to undo it.
+type EmailChangeEvent struct {
+	Name         string
+	OldEmail     string
+	NewEmail     string
+	ConfirmToken string
+	RevertToken  string
+	Exp          time.Time
+}
+
+type MagicLinkEvent struct {
+	Email string
+	Token string
+	Exp   time.Time
+}
+
+// UserDeletionScheduledEvent gives the user a way to restore the account until
+// it is purged.
+type UserDeletionScheduledEvent struct {
+	Email   string
+	Name    string
+	Token   string
+	PurgeAt time.Time
+}
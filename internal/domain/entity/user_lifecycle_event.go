@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The user lifecycle events let other services follow the accounts without
+// polling our database. They are published as JSON, keyed by the user id so
+// the events of one user are consumed in order. Every event carries the
+// fields of EventMetadata next to its own, for example on user-registered:
+//
+//	{
+//	  "EventId": "5b0c6f4e-8f0a-4f7e-9f43-3f6f4b1a2c7d",
+//	  "OccurredAt": "2024-01-01T12:00:00Z",
+//	  "Id": "0e8f4c2a-6f1b-4a53-8d0c-2b7e3c9a1f64",
+//	  "Email": "john@example.com",
+//	  "Name": "John Doe",
+//	  "EmailVerified": false
+//	}
+//
+// Id is always the user id. Consumers should ignore fields they do not know,
+// new ones may be added but existing ones are not renamed or removed.
+
+// Ways a user signed in or changed their password, as found in the Method of
+// UserLoggedInEvent and UserPasswordChangedEvent.
+const (
+	LOGIN_METHOD_PASSWORD   = "password"
+	LOGIN_METHOD_MAGIC_LINK = "magic-link"
+	LOGIN_METHOD_PASSKEY    = "passkey"
+
+	PASSWORD_CHANGE_METHOD_UPDATE = "update"
+	PASSWORD_CHANGE_METHOD_RESET  = "reset"
+)
+
+// EventMetadata identifies one occurrence of an event, so consumers can drop
+// the duplicates a redelivery may bring.
+type EventMetadata struct {
+	EventId    uuid.UUID
+	OccurredAt time.Time
+}
+
+func NewEventMetadata() EventMetadata {
+	return EventMetadata{
+		EventId:    uuid.New(),
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// UserRegisteredEvent is published on user-registered once the account is
+// created.
+type UserRegisteredEvent struct {
+	EventMetadata
+	Id            uuid.UUID
+	Email         string
+	Name          string
+	EmailVerified bool
+}
+
+func NewUserRegisteredEvent(user *User) UserRegisteredEvent {
+	return UserRegisteredEvent{
+		EventMetadata: NewEventMetadata(),
+		Id:            user.Id,
+		Email:         user.Email,
+		Name:          user.Name,
+		EmailVerified: user.EmailVerified,
+	}
+}
+
+// UserLoggedInEvent is published on user-logged-in once the tokens of a sign
+// in are issued, after any second factor, with a password, a magic link or a
+// passkey.
+type UserLoggedInEvent struct {
+	EventMetadata
+	Id        uuid.UUID
+	Email     string
+	Method    string
+	IpAddress string
+	UserAgent string
+}
+
+// NewUserLoggedInEvent takes the id and email alone, as the login is only
+// complete once its tokens are issued, where the user is no longer an entity.
+func NewUserLoggedInEvent(id uuid.UUID, email string, method string, ipAddress string, userAgent string) UserLoggedInEvent {
+	return UserLoggedInEvent{
+		EventMetadata: NewEventMetadata(),
+		Id:            id,
+		Email:         email,
+		Method:        method,
+		IpAddress:     ipAddress,
+		UserAgent:     userAgent,
+	}
+}
+
+// UserProfileUpdatedEvent is published on user-profile-updated with the
+// profile as saved. A new email only shows up in UserEmailChangedEvent, once
+// it is confirmed.
+type UserProfileUpdatedEvent struct {
+	EventMetadata
+	Id    uuid.UUID
+	Email string
+	Name  string
+}
+
+func NewUserProfileUpdatedEvent(user *User) UserProfileUpdatedEvent {
+	return UserProfileUpdatedEvent{
+		EventMetadata: NewEventMetadata(),
+		Id:            user.Id,
+		Email:         user.Email,
+		Name:          user.Name,
+	}
+}
+
+// UserEmailChangedEvent is published on user-email-changed when a change is
+// confirmed, when the old address reverts it and when an admin changes it.
+type UserEmailChangedEvent struct {
+	EventMetadata
+	Id       uuid.UUID
+	OldEmail string
+	NewEmail string
+}
+
+func NewUserEmailChangedEvent(user *User, oldEmail string) UserEmailChangedEvent {
+	return UserEmailChangedEvent{
+		EventMetadata: NewEventMetadata(),
+		Id:            user.Id,
+		OldEmail:      oldEmail,
+		NewEmail:      user.Email,
+	}
+}
+
+// UserPasswordChangedEvent is published on user-password-changed. It never
+// carries the password or its hash.
+type UserPasswordChangedEvent struct {
+	EventMetadata
+	Id     uuid.UUID
+	Email  string
+	Method string
+}
+
+func NewUserPasswordChangedEvent(user *User, method string) UserPasswordChangedEvent {
+	return UserPasswordChangedEvent{
+		EventMetadata: NewEventMetadata(),
+		Id:            user.Id,
+		Email:         user.Email,
+		Method:        method,
+	}
+}
+
+// UserDeletedEvent is published on user-deleted once the account is gone for
+// good, after the deletion grace period, so other services can drop what they
+// hold about the user. Deletions still in their grace period can be undone and
+// are not announced here.
+type UserDeletedEvent struct {
+	EventMetadata
+	Id        uuid.UUID
+	Email     string
+	DeletedAt time.Time
+}
+
+func NewUserDeletedEvent(user *User) UserDeletedEvent {
+	return UserDeletedEvent{
+		EventMetadata: NewEventMetadata(),
+		Id:            user.Id,
+		Email:         user.Email,
+		DeletedAt:     time.Now(),
+	}
+}
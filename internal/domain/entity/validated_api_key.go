@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedApiKey struct {
+	ApiKey
+	isValidated bool
+}
+
+func (vk *ValidatedApiKey) IsValid() bool {
+	return vk.isValidated
+}
+
+func NewValidatedApiKey(key *ApiKey) (*ValidatedApiKey, error) {
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedApiKey{
+		ApiKey:      *key,
+		isValidated: true,
+	}, nil
+}
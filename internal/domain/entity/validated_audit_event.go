@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedAuditEvent struct {
+	AuditEvent
+	isValidated bool
+}
+
+func (ve *ValidatedAuditEvent) IsValid() bool {
+	return ve.isValidated
+}
+
+func NewValidatedAuditEvent(event *AuditEvent) (*ValidatedAuditEvent, error) {
+	if err := event.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedAuditEvent{
+		AuditEvent:  *event,
+		isValidated: true,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedMachineClient struct {
+	MachineClient
+	isValidated bool
+}
+
+func (vc *ValidatedMachineClient) IsValid() bool {
+	return vc.isValidated
+}
+
+func NewValidatedMachineClient(client *MachineClient) (*ValidatedMachineClient, error) {
+	if err := client.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedMachineClient{
+		MachineClient: *client,
+		isValidated:   true,
+	}, nil
+}
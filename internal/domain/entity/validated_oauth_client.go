@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedOAuthClient struct {
+	OAuthClient
+	isValidated bool
+}
+
+func (vc *ValidatedOAuthClient) IsValid() bool {
+	return vc.isValidated
+}
+
+func NewValidatedOAuthClient(client *OAuthClient) (*ValidatedOAuthClient, error) {
+	if err := client.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedOAuthClient{
+		OAuthClient: *client,
+		isValidated: true,
+	}, nil
+}
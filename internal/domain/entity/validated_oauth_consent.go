@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedOAuthConsent struct {
+	OAuthConsent
+	isValidated bool
+}
+
+func (vc *ValidatedOAuthConsent) IsValid() bool {
+	return vc.isValidated
+}
+
+func NewValidatedOAuthConsent(consent *OAuthConsent) (*ValidatedOAuthConsent, error) {
+	if err := consent.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedOAuthConsent{
+		OAuthConsent: *consent,
+		isValidated:  true,
+	}, nil
+}
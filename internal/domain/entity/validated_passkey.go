@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedPasskey struct {
+	Passkey
+	isValidated bool
+}
+
+func (vp *ValidatedPasskey) IsValid() bool {
+	return vp.isValidated
+}
+
+func NewValidatedPasskey(passkey *Passkey) (*ValidatedPasskey, error) {
+	if err := passkey.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedPasskey{
+		Passkey:     *passkey,
+		isValidated: true,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedRecoveryCode struct {
+	RecoveryCode
+	isValidated bool
+}
+
+func (vc *ValidatedRecoveryCode) IsValid() bool {
+	return vc.isValidated
+}
+
+func NewValidatedRecoveryCode(code *RecoveryCode) (*ValidatedRecoveryCode, error) {
+	if err := code.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedRecoveryCode{
+		RecoveryCode: *code,
+		isValidated:  true,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedRole struct {
+	Role
+	isValidated bool
+}
+
+func (vr *ValidatedRole) IsValid() bool {
+	return vr.isValidated
+}
+
+func NewValidatedRole(role *Role) (*ValidatedRole, error) {
+	if err := role.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedRole{
+		Role:        *role,
+		isValidated: true,
+	}, nil
+}
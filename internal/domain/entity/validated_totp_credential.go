@@ -0,0 +1,21 @@
+package entity
+
+type ValidatedTotpCredential struct {
+	TotpCredential
+	isValidated bool
+}
+
+func (vc *ValidatedTotpCredential) IsValid() bool {
+	return vc.isValidated
+}
+
+func NewValidatedTotpCredential(credential *TotpCredential) (*ValidatedTotpCredential, error) {
+	if err := credential.validate(); err != nil {
+		return nil, err
+	}
+
+	return &ValidatedTotpCredential{
+		TotpCredential: *credential,
+		isValidated:    true,
+	}, nil
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: machine_client_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=machine_client_repository.go -destination=../mocks/machine_client_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "github/imfropz/go-ddd/internal/domain/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMachineClientRepository is a mock of MachineClientRepository interface.
+type MockMachineClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMachineClientRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMachineClientRepositoryMockRecorder is the mock recorder for MockMachineClientRepository.
+type MockMachineClientRepositoryMockRecorder struct {
+	mock *MockMachineClientRepository
+}
+
+// NewMockMachineClientRepository creates a new mock instance.
+func NewMockMachineClientRepository(ctrl *gomock.Controller) *MockMachineClientRepository {
+	mock := &MockMachineClientRepository{ctrl: ctrl}
+	mock.recorder = &MockMachineClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMachineClientRepository) EXPECT() *MockMachineClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockMachineClientRepository) Create(client *entity.ValidatedMachineClient) (*entity.MachineClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", client)
+	ret0, _ := ret[0].(*entity.MachineClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMachineClientRepositoryMockRecorder) Create(client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMachineClientRepository)(nil).Create), client)
+}
+
+// FindAll mocks base method.
+func (m *MockMachineClientRepository) FindAll() ([]*entity.MachineClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].([]*entity.MachineClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockMachineClientRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockMachineClientRepository)(nil).FindAll))
+}
+
+// FindByClientId mocks base method.
+func (m *MockMachineClientRepository) FindByClientId(clientId string) (*entity.MachineClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByClientId", clientId)
+	ret0, _ := ret[0].(*entity.MachineClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByClientId indicates an expected call of FindByClientId.
+func (mr *MockMachineClientRepositoryMockRecorder) FindByClientId(clientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByClientId", reflect.TypeOf((*MockMachineClientRepository)(nil).FindByClientId), clientId)
+}
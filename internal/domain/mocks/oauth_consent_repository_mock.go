@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: oauth_consent_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=oauth_consent_repository.go -destination=../mocks/oauth_consent_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "github/imfropz/go-ddd/internal/domain/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthConsentRepository is a mock of OAuthConsentRepository interface.
+type MockOAuthConsentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthConsentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthConsentRepositoryMockRecorder is the mock recorder for MockOAuthConsentRepository.
+type MockOAuthConsentRepositoryMockRecorder struct {
+	mock *MockOAuthConsentRepository
+}
+
+// NewMockOAuthConsentRepository creates a new mock instance.
+func NewMockOAuthConsentRepository(ctrl *gomock.Controller) *MockOAuthConsentRepository {
+	mock := &MockOAuthConsentRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthConsentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthConsentRepository) EXPECT() *MockOAuthConsentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByUserIdAndClientId mocks base method.
+func (m *MockOAuthConsentRepository) FindByUserIdAndClientId(userId uuid.UUID, clientId string) (*entity.OAuthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserIdAndClientId", userId, clientId)
+	ret0, _ := ret[0].(*entity.OAuthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserIdAndClientId indicates an expected call of FindByUserIdAndClientId.
+func (mr *MockOAuthConsentRepositoryMockRecorder) FindByUserIdAndClientId(userId, clientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserIdAndClientId", reflect.TypeOf((*MockOAuthConsentRepository)(nil).FindByUserIdAndClientId), userId, clientId)
+}
+
+// Save mocks base method.
+func (m *MockOAuthConsentRepository) Save(consent *entity.ValidatedOAuthConsent) (*entity.OAuthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", consent)
+	ret0, _ := ret[0].(*entity.OAuthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockOAuthConsentRepositoryMockRecorder) Save(consent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockOAuthConsentRepository)(nil).Save), consent)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recovery_code_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=recovery_code_repository.go -destination=../mocks/recovery_code_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "github/imfropz/go-ddd/internal/domain/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecoveryCodeRepository is a mock of RecoveryCodeRepository interface.
+type MockRecoveryCodeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecoveryCodeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRecoveryCodeRepositoryMockRecorder is the mock recorder for MockRecoveryCodeRepository.
+type MockRecoveryCodeRepositoryMockRecorder struct {
+	mock *MockRecoveryCodeRepository
+}
+
+// NewMockRecoveryCodeRepository creates a new mock instance.
+func NewMockRecoveryCodeRepository(ctrl *gomock.Controller) *MockRecoveryCodeRepository {
+	mock := &MockRecoveryCodeRepository{ctrl: ctrl}
+	mock.recorder = &MockRecoveryCodeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecoveryCodeRepository) EXPECT() *MockRecoveryCodeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnusedByUserId mocks base method.
+func (m *MockRecoveryCodeRepository) CountUnusedByUserId(userId uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedByUserId", userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedByUserId indicates an expected call of CountUnusedByUserId.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) CountUnusedByUserId(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedByUserId", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).CountUnusedByUserId), userId)
+}
+
+// DeleteByUserId mocks base method.
+func (m *MockRecoveryCodeRepository) DeleteByUserId(userId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserId", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserId indicates an expected call of DeleteByUserId.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) DeleteByUserId(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserId", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).DeleteByUserId), userId)
+}
+
+// FindUnusedByUserId mocks base method.
+func (m *MockRecoveryCodeRepository) FindUnusedByUserId(userId uuid.UUID) ([]*entity.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnusedByUserId", userId)
+	ret0, _ := ret[0].([]*entity.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnusedByUserId indicates an expected call of FindUnusedByUserId.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) FindUnusedByUserId(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnusedByUserId", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).FindUnusedByUserId), userId)
+}
+
+// MarkUsed mocks base method.
+func (m *MockRecoveryCodeRepository) MarkUsed(id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsed", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkUsed indicates an expected call of MarkUsed.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) MarkUsed(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsed", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).MarkUsed), id)
+}
+
+// ReplaceAllByUserId mocks base method.
+func (m *MockRecoveryCodeRepository) ReplaceAllByUserId(userId uuid.UUID, codes []*entity.ValidatedRecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceAllByUserId", userId, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceAllByUserId indicates an expected call of ReplaceAllByUserId.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) ReplaceAllByUserId(userId, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllByUserId", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).ReplaceAllByUserId), userId, codes)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: totp_credential_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=totp_credential_repository.go -destination=../mocks/totp_credential_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "github/imfropz/go-ddd/internal/domain/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTotpCredentialRepository is a mock of TotpCredentialRepository interface.
+type MockTotpCredentialRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTotpCredentialRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTotpCredentialRepositoryMockRecorder is the mock recorder for MockTotpCredentialRepository.
+type MockTotpCredentialRepositoryMockRecorder struct {
+	mock *MockTotpCredentialRepository
+}
+
+// NewMockTotpCredentialRepository creates a new mock instance.
+func NewMockTotpCredentialRepository(ctrl *gomock.Controller) *MockTotpCredentialRepository {
+	mock := &MockTotpCredentialRepository{ctrl: ctrl}
+	mock.recorder = &MockTotpCredentialRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTotpCredentialRepository) EXPECT() *MockTotpCredentialRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTotpCredentialRepository) Create(credential *entity.ValidatedTotpCredential) (*entity.TotpCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", credential)
+	ret0, _ := ret[0].(*entity.TotpCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTotpCredentialRepositoryMockRecorder) Create(credential any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTotpCredentialRepository)(nil).Create), credential)
+}
+
+// DeleteByUserId mocks base method.
+func (m *MockTotpCredentialRepository) DeleteByUserId(userId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserId", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserId indicates an expected call of DeleteByUserId.
+func (mr *MockTotpCredentialRepositoryMockRecorder) DeleteByUserId(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserId", reflect.TypeOf((*MockTotpCredentialRepository)(nil).DeleteByUserId), userId)
+}
+
+// ExistsByUserId mocks base method.
+func (m *MockTotpCredentialRepository) ExistsByUserId(userId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByUserId", userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByUserId indicates an expected call of ExistsByUserId.
+func (mr *MockTotpCredentialRepositoryMockRecorder) ExistsByUserId(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByUserId", reflect.TypeOf((*MockTotpCredentialRepository)(nil).ExistsByUserId), userId)
+}
+
+// FindByUserId mocks base method.
+func (m *MockTotpCredentialRepository) FindByUserId(userId uuid.UUID) (*entity.TotpCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserId", userId)
+	ret0, _ := ret[0].(*entity.TotpCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserId indicates an expected call of FindByUserId.
+func (mr *MockTotpCredentialRepositoryMockRecorder) FindByUserId(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserId", reflect.TypeOf((*MockTotpCredentialRepository)(nil).FindByUserId), userId)
+}
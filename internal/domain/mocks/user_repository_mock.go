@@ -13,6 +13,7 @@ import (
 	criteria "github/imfropz/go-ddd/internal/domain/criteria"
 	entity "github/imfropz/go-ddd/internal/domain/entity"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +43,21 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockUserRepository) Count(userCriteria *criteria.UserCriteria) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", userCriteria)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockUserRepositoryMockRecorder) Count(userCriteria any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserRepository)(nil).Count), userCriteria)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(user *entity.ValidatedUser) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -57,18 +73,19 @@ func (mr *MockUserRepositoryMockRecorder) Create(user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), user)
 }
 
-// Delete mocks base method.
-func (m *MockUserRepository) Delete(id uuid.UUID) error {
+// DeleteIfDeletedBefore mocks base method.
+func (m *MockUserRepository) DeleteIfDeletedBefore(id uuid.UUID, before time.Time) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteIfDeletedBefore", id, before)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockUserRepositoryMockRecorder) Delete(id any) *gomock.Call {
+// DeleteIfDeletedBefore indicates an expected call of DeleteIfDeletedBefore.
+func (mr *MockUserRepositoryMockRecorder) DeleteIfDeletedBefore(id, before any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIfDeletedBefore", reflect.TypeOf((*MockUserRepository)(nil).DeleteIfDeletedBefore), id, before)
 }
 
 // FindAll mocks base method.
@@ -116,6 +133,21 @@ func (mr *MockUserRepositoryMockRecorder) FindById(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockUserRepository)(nil).FindById), id)
 }
 
+// FindDeletedBefore mocks base method.
+func (m *MockUserRepository) FindDeletedBefore(before time.Time, limit int) ([]*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedBefore", before, limit)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedBefore indicates an expected call of FindDeletedBefore.
+func (mr *MockUserRepositoryMockRecorder) FindDeletedBefore(before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedBefore", reflect.TypeOf((*MockUserRepository)(nil).FindDeletedBefore), before, limit)
+}
+
 // Update mocks base method.
 func (m *MockUserRepository) Update(user *entity.ValidatedUser) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -130,3 +162,18 @@ func (mr *MockUserRepositoryMockRecorder) Update(user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), user)
 }
+
+// UpdateStatus mocks base method.
+func (m *MockUserRepository) UpdateStatus(user *entity.ValidatedUser) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", user)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockUserRepositoryMockRecorder) UpdateStatus(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockUserRepository)(nil).UpdateStatus), user)
+}
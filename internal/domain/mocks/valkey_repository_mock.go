@@ -71,6 +71,26 @@ func (mr *MockValkeyRepositoryMockRecorder) Delete(ctx any, keys ...any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockValkeyRepository)(nil).Delete), varargs...)
 }
 
+// Eval mocks base method.
+func (m *MockValkeyRepository) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, script, keys}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Eval", varargs...)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Eval indicates an expected call of Eval.
+func (mr *MockValkeyRepositoryMockRecorder) Eval(ctx, script, keys any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, script, keys}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Eval", reflect.TypeOf((*MockValkeyRepository)(nil).Eval), varargs...)
+}
+
 // Exists mocks base method.
 func (m *MockValkeyRepository) Exists(ctx context.Context, key string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -115,6 +135,25 @@ func (mr *MockValkeyRepositoryMockRecorder) Get(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockValkeyRepository)(nil).Get), ctx, key)
 }
 
+// HDel mocks base method.
+func (m *MockValkeyRepository) HDel(ctx context.Context, key string, fields ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HDel", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockValkeyRepositoryMockRecorder) HDel(ctx, key any, fields ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockValkeyRepository)(nil).HDel), varargs...)
+}
+
 // HGet mocks base method.
 func (m *MockValkeyRepository) HGet(ctx context.Context, key, field string) (string, error) {
 	m.ctrl.T.Helper()
@@ -221,3 +260,18 @@ func (mr *MockValkeyRepositoryMockRecorder) Set(ctx, key, value, ttl any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockValkeyRepository)(nil).Set), ctx, key, value, ttl)
 }
+
+// SetNX mocks base method.
+func (m *MockValkeyRepository) SetNX(ctx context.Context, key string, value any, ttl int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockValkeyRepositoryMockRecorder) SetNX(ctx, key, value, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockValkeyRepository)(nil).SetNX), ctx, key, value, ttl)
+}
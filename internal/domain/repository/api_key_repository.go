@@ -0,0 +1,17 @@
+//go:generate mockgen -source=api_key_repository.go -destination=../mocks/api_key_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type ApiKeyRepository interface {
+	Create(key *entity.ValidatedApiKey) (*entity.ApiKey, error)
+	FindById(id uuid.UUID) (*entity.ApiKey, error)
+	FindByKeyHash(keyHash string) (*entity.ApiKey, error)
+	FindAllByUserId(userId uuid.UUID) ([]*entity.ApiKey, error)
+	Update(key *entity.ValidatedApiKey) (*entity.ApiKey, error)
+}
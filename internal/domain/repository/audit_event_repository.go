@@ -0,0 +1,15 @@
+//go:generate mockgen -source=audit_event_repository.go -destination=../mocks/audit_event_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+// AuditEventRepository is append only: events cannot be updated or deleted.
+type AuditEventRepository interface {
+	Create(event *entity.ValidatedAuditEvent) (*entity.AuditEvent, error)
+	FindAll(auditEventCriteria *criteria.AuditEventCriteria) ([]*entity.AuditEvent, error)
+	Count(auditEventCriteria *criteria.AuditEventCriteria) (int64, error)
+}
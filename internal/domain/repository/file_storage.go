@@ -0,0 +1,17 @@
+//go:generate mockgen -source=file_storage.go -destination=../mocks/file_storage_mock.go -package=mocks
+
+package repository
+
+import (
+	"io"
+	"time"
+)
+
+type FileStorage interface {
+	Save(name string, data []byte) error
+	Open(name string) (io.ReadCloser, error)
+	Delete(name string) error
+	// DeleteBefore removes the files last written before the given time and
+	// reports how many there were.
+	DeleteBefore(before time.Time) (int, error)
+}
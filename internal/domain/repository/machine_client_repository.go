@@ -0,0 +1,13 @@
+//go:generate mockgen -source=machine_client_repository.go -destination=../mocks/machine_client_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+)
+
+type MachineClientRepository interface {
+	Create(client *entity.ValidatedMachineClient) (*entity.MachineClient, error)
+	FindByClientId(clientId string) (*entity.MachineClient, error)
+	FindAll() ([]*entity.MachineClient, error)
+}
@@ -0,0 +1,15 @@
+//go:generate mockgen -source=oauth_client_repository.go -destination=../mocks/oauth_client_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type OAuthClientRepository interface {
+	Create(client *entity.ValidatedOAuthClient) (*entity.OAuthClient, error)
+	FindByClientId(clientId string) (*entity.OAuthClient, error)
+	FindAllByOwnerId(ownerId uuid.UUID) ([]*entity.OAuthClient, error)
+}
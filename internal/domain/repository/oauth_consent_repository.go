@@ -0,0 +1,14 @@
+//go:generate mockgen -source=oauth_consent_repository.go -destination=../mocks/oauth_consent_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type OAuthConsentRepository interface {
+	Save(consent *entity.ValidatedOAuthConsent) (*entity.OAuthConsent, error)
+	FindByUserIdAndClientId(userId uuid.UUID, clientId string) (*entity.OAuthConsent, error)
+}
@@ -0,0 +1,18 @@
+//go:generate mockgen -source=passkey_repository.go -destination=../mocks/passkey_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type PasskeyRepository interface {
+	Create(passkey *entity.ValidatedPasskey) (*entity.Passkey, error)
+	FindById(id uuid.UUID) (*entity.Passkey, error)
+	FindByCredentialId(credentialId []byte) (*entity.Passkey, error)
+	FindAllByUserId(userId uuid.UUID) ([]*entity.Passkey, error)
+	Update(passkey *entity.ValidatedPasskey) (*entity.Passkey, error)
+	Delete(id uuid.UUID) error
+}
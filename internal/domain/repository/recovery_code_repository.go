@@ -0,0 +1,20 @@
+//go:generate mockgen -source=recovery_code_repository.go -destination=../mocks/recovery_code_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type RecoveryCodeRepository interface {
+	// ReplaceAllByUserId drops every code of the user in favour of the new set.
+	ReplaceAllByUserId(userId uuid.UUID, codes []*entity.ValidatedRecoveryCode) error
+	FindUnusedByUserId(userId uuid.UUID) ([]*entity.RecoveryCode, error)
+	CountUnusedByUserId(userId uuid.UUID) (int, error)
+	// MarkUsed reports false when the code was already used, so that a code
+	// submitted twice at once only succeeds once.
+	MarkUsed(id uuid.UUID) (bool, error)
+	DeleteByUserId(userId uuid.UUID) error
+}
@@ -0,0 +1,20 @@
+//go:generate mockgen -source=role_repository.go -destination=../mocks/role_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type RoleRepository interface {
+	Create(role *entity.ValidatedRole) (*entity.Role, error)
+	Update(role *entity.ValidatedRole) (*entity.Role, error)
+	FindAll() ([]*entity.Role, error)
+	FindByName(name string) (*entity.Role, error)
+	FindByUserId(userId uuid.UUID) ([]*entity.Role, error)
+	AssignToUser(userId uuid.UUID, roleId uuid.UUID) error
+	UnassignFromUser(userId uuid.UUID, roleId uuid.UUID) error
+	CountUsers(roleId uuid.UUID) (int64, error)
+}
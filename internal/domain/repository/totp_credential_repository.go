@@ -0,0 +1,16 @@
+//go:generate mockgen -source=totp_credential_repository.go -destination=../mocks/totp_credential_repository_mock.go -package=mocks
+
+package repository
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type TotpCredentialRepository interface {
+	Create(credential *entity.ValidatedTotpCredential) (*entity.TotpCredential, error)
+	FindByUserId(userId uuid.UUID) (*entity.TotpCredential, error)
+	ExistsByUserId(userId uuid.UUID) (bool, error)
+	DeleteByUserId(userId uuid.UUID) error
+}
@@ -5,6 +5,7 @@ package repository
 import (
 	"github/imfropz/go-ddd/internal/domain/criteria"
 	"github/imfropz/go-ddd/internal/domain/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,6 +15,16 @@ type UserRepository interface {
 	FindById(id uuid.UUID) (*entity.User, error)
 	FindByEmail(email string) (*entity.User, error)
 	FindAll(userCriteria *criteria.UserCriteria) ([]*entity.User, error)
+	// Count counts the users matching the criteria, ignoring paging.
+	Count(userCriteria *criteria.UserCriteria) (int64, error)
 	Update(user *entity.ValidatedUser) (*entity.User, error)
-	Delete(id uuid.UUID) error
+	// UpdateStatus saves DisabledAt and DeletedAt, which Update leaves alone
+	// when they are nil.
+	UpdateStatus(user *entity.ValidatedUser) (*entity.User, error)
+	FindDeletedBefore(before time.Time, limit int) ([]*entity.User, error)
+	// DeleteIfDeletedBefore removes the user for good, together with their
+	// credentials, and anonymizes their audit events. It only does so while the
+	// user is still marked deleted before the given time, and reports whether
+	// it did.
+	DeleteIfDeletedBefore(id uuid.UUID, before time.Time) (bool, error)
 }
@@ -7,6 +7,7 @@ import "context"
 type ValkeyRepository interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value interface{}, ttl int) error
+	SetNX(ctx context.Context, key string, value interface{}, ttl int) (bool, error)
 	Delete(ctx context.Context, keys ...string) error
 	Exists(ctx context.Context, key string) (bool, error)
 	Expire(ctx context.Context, key string, ttl int) error
@@ -14,7 +15,9 @@ type ValkeyRepository interface {
 	HSet(ctx context.Context, key string, values map[string]interface{}) error
 	HGet(ctx context.Context, key, field string) (string, error)
 	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDel(ctx context.Context, key string, fields ...string) error
 	LPush(ctx context.Context, key string, values ...interface{}) error
 	LRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
 	Close()
 }
@@ -0,0 +1,411 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Postgres  PostgresConfig  `yaml:"postgres"`
+	Valkey    ValkeyConfig    `yaml:"valkey"`
+	Kafka     KafkaConfig     `yaml:"kafka"`
+	SMTP      SMTPConfig      `yaml:"smtp"`
+	Mail      MailConfig      `yaml:"mail"`
+	Jwt       JwtConfig       `yaml:"jwt"`
+	Oidc      OidcConfig      `yaml:"oidc"`
+	Mfa       MfaConfig       `yaml:"mfa"`
+	Webauthn  WebauthnConfig  `yaml:"webauthn"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Export    ExportConfig    `yaml:"export"`
+}
+
+type ServerConfig struct {
+	Address        string   `yaml:"address" env:"SERVER_ADDRESS"`
+	TrustedProxies []string `yaml:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES"`
+}
+
+type PostgresConfig struct {
+	Host     string `yaml:"host" env:"POSTGRES_HOST" required:"true"`
+	Port     int    `yaml:"port" env:"POSTGRES_PORT" required:"true"`
+	User     string `yaml:"user" env:"POSTGRES_USER" required:"true"`
+	Password string `yaml:"password" env:"POSTGRES_PASSWORD" required:"true"`
+	DBName   string `yaml:"dbname" env:"POSTGRES_DB" required:"true"`
+	SSLMode  string `yaml:"sslmode" env:"POSTGRES_SSLMODE"`
+}
+
+type ValkeyConfig struct {
+	Addresses []string `yaml:"addresses" env:"VALKEY_ADDRESSES" required:"true"`
+	Password  string   `yaml:"password" env:"VALKEY_PASSWORD"`
+}
+
+type KafkaConfig struct {
+	Brokers       []string `yaml:"brokers" env:"KAFKA_BROKERS" required:"true"`
+	Version       string   `yaml:"version" env:"KAFKA_VERSION" required:"true"`
+	ClientID      string   `yaml:"client_id" env:"KAFKA_CLIENT_ID" required:"true"`
+	ConsumerGroup string   `yaml:"consumer_group" env:"KAFKA_CONSUMER_GROUP" required:"true"`
+}
+
+type SMTPConfig struct {
+	SMTPHost     string `yaml:"host" env:"SMTP_HOST" required:"true"`
+	SMTPPort     string `yaml:"port" env:"SMTP_PORT" required:"true"`
+	SMTPUsername string `yaml:"username" env:"SMTP_USERNAME" required:"true"`
+	SMTPPassword string `yaml:"password" env:"SMTP_PASSWORD" required:"true"`
+}
+
+// MailConfig urls are the pages of the frontend that receive the emailed token
+// as their token query parameter.
+type MailConfig struct {
+	FromEmail             string `yaml:"from_email" env:"FROM_EMAIL" required:"true"`
+	MagicLinkUrl          string `yaml:"magic_link_url" env:"MAIL_MAGIC_LINK_URL" required:"true"`
+	VerifyEmailUrl        string `yaml:"verify_email_url" env:"MAIL_VERIFY_EMAIL_URL" required:"true"`
+	ConfirmEmailChangeUrl string `yaml:"confirm_email_change_url" env:"MAIL_CONFIRM_EMAIL_CHANGE_URL" required:"true"`
+	RevertEmailChangeUrl  string `yaml:"revert_email_change_url" env:"MAIL_REVERT_EMAIL_CHANGE_URL" required:"true"`
+	RestoreAccountUrl     string `yaml:"restore_account_url" env:"MAIL_RESTORE_ACCOUNT_URL" required:"true"`
+	DataExportUrl         string `yaml:"data_export_url" env:"MAIL_DATA_EXPORT_URL" required:"true"`
+}
+
+type JwtConfig struct {
+	SigningKeyFile       string   `yaml:"signing_key_file" env:"JWT_SIGNING_KEY_FILE"`
+	VerificationKeyFiles []string `yaml:"verification_key_files" env:"JWT_VERIFICATION_KEY_FILES"`
+	// Ephemeral signs with a key generated at startup instead of the key file,
+	// so every restart signs all users out. It is only meant for development.
+	Ephemeral bool `yaml:"ephemeral" env:"JWT_EPHEMERAL"`
+}
+
+type OidcConfig struct {
+	Issuer string `yaml:"issuer" env:"OIDC_ISSUER" required:"true"`
+}
+
+type MfaConfig struct {
+	TotpIssuer string `yaml:"totp_issuer" env:"MFA_TOTP_ISSUER" required:"true"`
+}
+
+// WebauthnConfig describes this service as the relying party for passkeys.
+// The id is the domain passkeys are bound to and must not change once users
+// registered them.
+type WebauthnConfig struct {
+	RpId    string   `yaml:"rp_id" env:"WEBAUTHN_RP_ID" required:"true"`
+	RpName  string   `yaml:"rp_name" env:"WEBAUTHN_RP_NAME" required:"true"`
+	Origins []string `yaml:"origins" env:"WEBAUTHN_ORIGINS" required:"true"`
+}
+
+// AuthConfig.EmailVerificationPolicy is off, restrict or block. Accounts that
+// existed before email verification start out unverified, so switching it on
+// affects them too. AdminEmails are given the admin role at startup when they
+// are registered.
+type AuthConfig struct {
+	EmailVerificationPolicy string         `yaml:"email_verification_policy" env:"AUTH_EMAIL_VERIFICATION_POLICY" required:"true"`
+	AdminEmails             []string       `yaml:"admin_emails" env:"AUTH_ADMIN_EMAILS"`
+	Lockout                 LockoutConfig  `yaml:"lockout"`
+	Deletion                DeletionConfig `yaml:"deletion"`
+}
+
+// LockoutConfig thresholds are the failed logins allowed per email and per
+// client ip before a lockout, 0 disables it. The lockout doubles with every
+// further failure up to the max duration.
+type LockoutConfig struct {
+	EmailThreshold int           `yaml:"email_threshold" env:"AUTH_LOCKOUT_EMAIL_THRESHOLD"`
+	IpThreshold    int           `yaml:"ip_threshold" env:"AUTH_LOCKOUT_IP_THRESHOLD"`
+	Window         time.Duration `yaml:"window" env:"AUTH_LOCKOUT_WINDOW"`
+	BaseDuration   time.Duration `yaml:"base_duration" env:"AUTH_LOCKOUT_BASE_DURATION"`
+	MaxDuration    time.Duration `yaml:"max_duration" env:"AUTH_LOCKOUT_MAX_DURATION"`
+}
+
+// DeletionConfig.GracePeriod is how long a deleted account can be restored
+// before it is purged. Expired accounts are looked for every purge interval.
+type DeletionConfig struct {
+	GracePeriod   time.Duration `yaml:"grace_period" env:"AUTH_DELETION_GRACE_PERIOD"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"AUTH_DELETION_PURGE_INTERVAL"`
+}
+
+// RateLimitConfig.Enabled can be turned off when a gateway in front of the
+// service already limits requests.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+}
+
+// ExportConfig.Directory is where data export archives are written. They can
+// be downloaded for the link duration and are removed afterwards, looked for
+// every purge interval.
+type ExportConfig struct {
+	Directory     string        `yaml:"directory" env:"EXPORT_DIRECTORY" required:"true"`
+	LinkDuration  time.Duration `yaml:"link_duration" env:"EXPORT_LINK_DURATION"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"EXPORT_PURGE_INTERVAL"`
+}
+
+// Default holds the values matching the docker-compose development stack.
+// Secrets are deliberately left empty so they always have to be provided.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Address: ":8080",
+		},
+		Postgres: PostgresConfig{
+			Host:    "localhost",
+			Port:    5432,
+			User:    "postgres",
+			DBName:  "postgres",
+			SSLMode: "disable",
+		},
+		Valkey: ValkeyConfig{
+			Addresses: []string{"localhost:6379"},
+		},
+		Kafka: KafkaConfig{
+			Brokers:       []string{"localhost:9092"},
+			Version:       "3.9.1",
+			ClientID:      "user-service",
+			ConsumerGroup: "notification-service-group",
+		},
+		Mail: MailConfig{
+			MagicLinkUrl:          "http://localhost:8080/magic-link",
+			VerifyEmailUrl:        "http://localhost:8080/verify-email",
+			ConfirmEmailChangeUrl: "http://localhost:8080/confirm-email-change",
+			RevertEmailChangeUrl:  "http://localhost:8080/revert-email-change",
+			RestoreAccountUrl:     "http://localhost:8080/restore-account",
+			DataExportUrl:         "http://localhost:8080/api/v1/profile/data-export/download",
+		},
+		Oidc: OidcConfig{
+			Issuer: "http://localhost:8080",
+		},
+		Mfa: MfaConfig{
+			TotpIssuer: "go-ddd",
+		},
+		Webauthn: WebauthnConfig{
+			RpId:    "localhost",
+			RpName:  "go-ddd",
+			Origins: []string{"http://localhost:8080"},
+		},
+		Auth: AuthConfig{
+			EmailVerificationPolicy: "off",
+			Lockout: LockoutConfig{
+				EmailThreshold: 5,
+				IpThreshold:    20,
+				Window:         15 * time.Minute,
+				BaseDuration:   time.Minute,
+				MaxDuration:    time.Hour,
+			},
+			Deletion: DeletionConfig{
+				GracePeriod:   30 * 24 * time.Hour,
+				PurgeInterval: time.Hour,
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+		},
+		Export: ExportConfig{
+			Directory:     "data/exports",
+			LinkDuration:  24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, then the YAML file at path
+// when one is given, then the environment variables, each overriding the
+// previous one. The result is validated before it is returned.
+func Load(path string) (*Config, error) {
+	config, err := loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadEnv(reflect.ValueOf(config).Elem()); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadPostgres loads and validates the postgres section alone, for tools that
+// only need the database and should not ask for the rest of the server's
+// configuration.
+func LoadPostgres(path string) (*PostgresConfig, error) {
+	config, err := loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.ValueOf(&config.Postgres).Elem()
+	if err := loadEnv(value); err != nil {
+		return nil, err
+	}
+
+	if err := errors.Join(validate(value, "postgres.")...); err != nil {
+		return nil, err
+	}
+
+	return &config.Postgres, nil
+}
+
+func loadFile(path string) (*Config, error) {
+	config := Default()
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Validate reports every missing required value at once, naming both the YAML
+// key and the environment variable that can provide it.
+func (config *Config) Validate() error {
+	errs := validate(reflect.ValueOf(config).Elem(), "")
+
+	for _, proxy := range config.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			errs = append(errs, fmt.Errorf("invalid config server.trusted_proxies %q, expected an ip address or CIDR range", proxy))
+		}
+	}
+
+	if config.Jwt.SigningKeyFile == "" && !config.Jwt.Ephemeral {
+		errs = append(errs, errors.New("missing required config jwt.signing_key_file (env JWT_SIGNING_KEY_FILE), set jwt.ephemeral to sign with a throwaway key in development"))
+	}
+	if config.Jwt.SigningKeyFile != "" && config.Jwt.Ephemeral {
+		errs = append(errs, errors.New("invalid config jwt.ephemeral, expected no jwt.signing_key_file along with it"))
+	}
+
+	switch config.Auth.EmailVerificationPolicy {
+	case "", "off", "restrict", "block":
+	default:
+		errs = append(errs, fmt.Errorf("invalid config auth.email_verification_policy %q, expected off, restrict or block", config.Auth.EmailVerificationPolicy))
+	}
+
+	lockout := config.Auth.Lockout
+	if lockout.Window <= 0 {
+		errs = append(errs, fmt.Errorf("invalid config auth.lockout.window %s, expected a positive duration", lockout.Window))
+	}
+	if lockout.BaseDuration <= 0 {
+		errs = append(errs, fmt.Errorf("invalid config auth.lockout.base_duration %s, expected a positive duration", lockout.BaseDuration))
+	}
+	if lockout.MaxDuration <= 0 {
+		errs = append(errs, fmt.Errorf("invalid config auth.lockout.max_duration %s, expected a positive duration", lockout.MaxDuration))
+	}
+	if lockout.BaseDuration > lockout.MaxDuration {
+		errs = append(errs, fmt.Errorf("invalid config auth.lockout.base_duration %s, expected at most auth.lockout.max_duration %s", lockout.BaseDuration, lockout.MaxDuration))
+	}
+
+	if config.Auth.Deletion.GracePeriod <= 0 {
+		errs = append(errs, fmt.Errorf("invalid config auth.deletion.grace_period %s, expected a positive duration", config.Auth.Deletion.GracePeriod))
+	}
+	if config.Auth.Deletion.PurgeInterval <= 0 {
+		errs = append(errs, fmt.Errorf("invalid config auth.deletion.purge_interval %s, expected a positive duration", config.Auth.Deletion.PurgeInterval))
+	}
+
+	if config.Export.LinkDuration <= 0 {
+		errs = append(errs, fmt.Errorf("invalid config export.link_duration %s, expected a positive duration", config.Export.LinkDuration))
+	}
+	if config.Export.PurgeInterval <= 0 {
+		errs = append(errs, fmt.Errorf("invalid config export.purge_interval %s, expected a positive duration", config.Export.PurgeInterval))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validate(value reflect.Value, prefix string) []error {
+	var errs []error
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := prefix + strings.Split(field.Tag.Get("yaml"), ",")[0]
+
+		if field.Type.Kind() == reflect.Struct {
+			errs = append(errs, validate(value.Field(i), name+".")...)
+			continue
+		}
+
+		if field.Tag.Get("required") == "true" && value.Field(i).IsZero() {
+			errs = append(errs, fmt.Errorf("missing required config %s (env %s)", name, field.Tag.Get("env")))
+		}
+	}
+
+	return errs
+}
+
+func loadEnv(value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		fieldValue := value.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := loadEnv(fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		raw, ok := os.LookupEnv(name)
+		if name == "" || !ok {
+			continue
+		}
+
+		if err := setValue(fieldValue, raw); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func setValue(value reflect.Value, raw string) error {
+	switch value.Interface().(type) {
+	case string:
+		value.SetString(raw)
+	case int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(number))
+	case bool:
+		boolean, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(boolean)
+	case time.Duration:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+	case []string:
+		items := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", value.Type())
+	}
+
+	return nil
+}
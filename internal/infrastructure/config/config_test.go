@@ -0,0 +1,193 @@
+package config_test
+
+import (
+	"github/imfropz/go-ddd/internal/infrastructure/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("POSTGRES_PASSWORD", "postgres")
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_PORT", "465")
+	t.Setenv("SMTP_USERNAME", "username")
+	t.Setenv("SMTP_PASSWORD", "password")
+	t.Setenv("FROM_EMAIL", "noreply@example.com")
+	t.Setenv("JWT_SIGNING_KEY_FILE", "/run/secrets/jwt-signing-key.pem")
+}
+
+func TestConfig_Load(t *testing.T) {
+	t.Run("success: defaults and environment", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("KAFKA_BROKERS", "broker-1:9092, broker-2:9092")
+		t.Setenv("POSTGRES_PORT", "6543")
+
+		cfg, err := config.Load("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ":8080", cfg.Server.Address)
+		assert.Equal(t, 6543, cfg.Postgres.Port)
+		assert.Equal(t, []string{"broker-1:9092", "broker-2:9092"}, cfg.Kafka.Brokers)
+		assert.Equal(t, "noreply@example.com", cfg.Mail.FromEmail)
+	})
+
+	t.Run("success: environment overrides file", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("POSTGRES_HOST", "env-host")
+
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		os.WriteFile(path, []byte("postgres:\n  host: file-host\n  dbname: users\nvalkey:\n  password: secret\n"), 0600)
+
+		cfg, err := config.Load(path)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "env-host", cfg.Postgres.Host)
+		assert.Equal(t, "users", cfg.Postgres.DBName)
+		assert.Equal(t, "secret", cfg.Valkey.Password)
+	})
+
+	t.Run("success: lockout durations", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("AUTH_LOCKOUT_BASE_DURATION", "30s")
+
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		os.WriteFile(path, []byte("auth:\n  lockout:\n    email_threshold: 3\n    max_duration: 2h\n"), 0600)
+
+		cfg, err := config.Load(path)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, cfg.Auth.Lockout.EmailThreshold)
+		assert.Equal(t, 20, cfg.Auth.Lockout.IpThreshold)
+		assert.Equal(t, 30*time.Second, cfg.Auth.Lockout.BaseDuration)
+		assert.Equal(t, 2*time.Hour, cfg.Auth.Lockout.MaxDuration)
+	})
+
+	t.Run("failure: missing required values", func(t *testing.T) {
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "postgres.password (env POSTGRES_PASSWORD)")
+		assert.Contains(t, err.Error(), "smtp.host (env SMTP_HOST)")
+		assert.Contains(t, err.Error(), "mail.from_email (env FROM_EMAIL)")
+	})
+
+	t.Run("success: ephemeral signing key", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("JWT_SIGNING_KEY_FILE", "")
+		t.Setenv("JWT_EPHEMERAL", "true")
+
+		cfg, err := config.Load("")
+
+		assert.NoError(t, err)
+		assert.True(t, cfg.Jwt.Ephemeral)
+	})
+
+	t.Run("failure: missing signing key", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("JWT_SIGNING_KEY_FILE", "")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "jwt.signing_key_file (env JWT_SIGNING_KEY_FILE)")
+	})
+
+	t.Run("failure: invalid number", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("POSTGRES_PORT", "not-a-port")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "POSTGRES_PORT")
+	})
+
+	t.Run("failure: invalid email verification policy", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("AUTH_EMAIL_VERIFICATION_POLICY", "strict")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "auth.email_verification_policy")
+	})
+	t.Run("failure: invalid lockout durations", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("AUTH_LOCKOUT_WINDOW", "0s")
+		t.Setenv("AUTH_LOCKOUT_BASE_DURATION", "2h")
+		t.Setenv("AUTH_LOCKOUT_MAX_DURATION", "1h")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "auth.lockout.window 0s")
+		assert.Contains(t, err.Error(), "auth.lockout.base_duration 2h0m0s, expected at most auth.lockout.max_duration 1h0m0s")
+	})
+
+	t.Run("failure: negative lockout duration", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("AUTH_LOCKOUT_MAX_DURATION", "-1m")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "auth.lockout.max_duration -1m0s, expected a positive duration")
+	})
+
+	t.Run("failure: invalid deletion grace period", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("AUTH_DELETION_GRACE_PERIOD", "0s")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "auth.deletion.grace_period 0s")
+	})
+
+	t.Run("failure: invalid export purge interval", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("EXPORT_PURGE_INTERVAL", "0s")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "export.purge_interval 0s")
+	})
+
+	t.Run("failure: invalid trusted proxy", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("SERVER_TRUSTED_PROXIES", "10.0.0.0/8, proxy.internal")
+
+		_, err := config.Load("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `server.trusted_proxies "proxy.internal"`)
+		assert.NotContains(t, err.Error(), "10.0.0.0/8")
+	})
+}
+
+func TestConfig_LoadPostgres(t *testing.T) {
+	t.Run("success: ignores the other sections", func(t *testing.T) {
+		t.Setenv("POSTGRES_PASSWORD", "postgres")
+		t.Setenv("POSTGRES_PORT", "6543")
+		t.Setenv("KAFKA_BROKERS", "")
+
+		cfg, err := config.LoadPostgres("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "postgres", cfg.Password)
+		assert.Equal(t, 6543, cfg.Port)
+	})
+
+	t.Run("failure: missing password", func(t *testing.T) {
+		_, err := config.LoadPostgres("")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "postgres.password (env POSTGRES_PASSWORD)")
+		assert.NotContains(t, err.Error(), "smtp")
+	})
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBApiKey(key *entity.ValidatedApiKey) *ApiKey {
+	k := &ApiKey{
+		UserId:     key.UserId,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+	k.Id = key.Id
+
+	return k
+}
+
+func fromDBApiKey(dbKey *ApiKey) *entity.ApiKey {
+	k := &entity.ApiKey{
+		UserId:     dbKey.UserId,
+		Name:       dbKey.Name,
+		Prefix:     dbKey.Prefix,
+		KeyHash:    dbKey.KeyHash,
+		Scopes:     dbKey.Scopes,
+		ExpiresAt:  dbKey.ExpiresAt,
+		LastUsedAt: dbKey.LastUsedAt,
+		RevokedAt:  dbKey.RevokedAt,
+		CreatedAt:  dbKey.CreatedAt,
+		UpdatedAt:  dbKey.UpdatedAt,
+	}
+	k.Id = dbKey.Id
+
+	return k
+}
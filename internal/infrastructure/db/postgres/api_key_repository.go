@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormApiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormApiKeyRepository(db *gorm.DB) repository.ApiKeyRepository {
+	return &GormApiKeyRepository{db: db}
+}
+
+func (repo *GormApiKeyRepository) Create(key *entity.ValidatedApiKey) (*entity.ApiKey, error) {
+	dbKey := toDBApiKey(key)
+
+	if err := repo.db.Create(dbKey).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindById(dbKey.Id)
+}
+
+func (repo *GormApiKeyRepository) FindById(id uuid.UUID) (*entity.ApiKey, error) {
+	var dbKey ApiKey
+	if err := repo.db.First(&dbKey, id).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBApiKey(&dbKey), nil
+}
+
+func (repo *GormApiKeyRepository) FindByKeyHash(keyHash string) (*entity.ApiKey, error) {
+	var dbKey ApiKey
+	if err := repo.db.Model(&ApiKey{}).Where("key_hash = ?", keyHash).First(&dbKey).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBApiKey(&dbKey), nil
+}
+
+func (repo *GormApiKeyRepository) FindAllByUserId(userId uuid.UUID) ([]*entity.ApiKey, error) {
+	var dbKeys []ApiKey
+	if err := repo.db.Model(&ApiKey{}).Where("user_id = ?", userId).Order("created_at").Find(&dbKeys).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]*entity.ApiKey, len(dbKeys))
+	for i, dbKey := range dbKeys {
+		keys[i] = fromDBApiKey(&dbKey)
+	}
+
+	return keys, nil
+}
+
+func (repo *GormApiKeyRepository) Update(key *entity.ValidatedApiKey) (*entity.ApiKey, error) {
+	dbKey := toDBApiKey(key)
+
+	if err := repo.db.Model(&ApiKey{}).Where("id = ?", dbKey.Id).Updates(dbKey).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindById(dbKey.Id)
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBAuditEvent(event *entity.ValidatedAuditEvent) *AuditEvent {
+	e := &AuditEvent{
+		UserId:    event.UserId,
+		ActorId:   event.ActorId,
+		Email:     event.Email,
+		Action:    event.Action,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		IpAddress: event.IpAddress,
+		UserAgent: event.UserAgent,
+		CreatedAt: event.CreatedAt,
+	}
+	e.Id = event.Id
+
+	return e
+}
+
+func fromDBAuditEvent(dbEvent *AuditEvent) *entity.AuditEvent {
+	e := &entity.AuditEvent{
+		UserId:    dbEvent.UserId,
+		ActorId:   dbEvent.ActorId,
+		Email:     dbEvent.Email,
+		Action:    dbEvent.Action,
+		Outcome:   dbEvent.Outcome,
+		Reason:    dbEvent.Reason,
+		IpAddress: dbEvent.IpAddress,
+		UserAgent: dbEvent.UserAgent,
+		CreatedAt: dbEvent.CreatedAt,
+	}
+	e.Id = dbEvent.Id
+
+	return e
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type GormAuditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuditEventRepository(db *gorm.DB) repository.AuditEventRepository {
+	return &GormAuditEventRepository{db: db}
+}
+
+func (repo *GormAuditEventRepository) Create(event *entity.ValidatedAuditEvent) (*entity.AuditEvent, error) {
+	dbEvent := toDBAuditEvent(event)
+
+	if err := repo.db.Create(dbEvent).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBAuditEvent(dbEvent), nil
+}
+
+func (repo *GormAuditEventRepository) FindAll(auditEventCriteria *criteria.AuditEventCriteria) ([]*entity.AuditEvent, error) {
+	query := filterAuditEvents(repo.db.Model(&AuditEvent{}), auditEventCriteria).Order("created_at DESC, id DESC")
+
+	if auditEventCriteria != nil && auditEventCriteria.Limit > 0 {
+		if auditEventCriteria.Page > 1 {
+			query = query.Offset((auditEventCriteria.Page - 1) * auditEventCriteria.Limit)
+		}
+		query = query.Limit(auditEventCriteria.Limit)
+	}
+
+	var dbEvents []AuditEvent
+	if err := query.Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*entity.AuditEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = fromDBAuditEvent(&dbEvent)
+	}
+
+	return events, nil
+}
+
+func (repo *GormAuditEventRepository) Count(auditEventCriteria *criteria.AuditEventCriteria) (int64, error) {
+	var count int64
+	if err := filterAuditEvents(repo.db.Model(&AuditEvent{}), auditEventCriteria).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func filterAuditEvents(query *gorm.DB, auditEventCriteria *criteria.AuditEventCriteria) *gorm.DB {
+	if auditEventCriteria == nil {
+		return query
+	}
+
+	if auditEventCriteria.UserId != nil {
+		query = query.Where("user_id = ?", *auditEventCriteria.UserId)
+	}
+	if auditEventCriteria.Action != nil {
+		query = query.Where("action = ?", *auditEventCriteria.Action)
+	}
+	if auditEventCriteria.Outcome != nil {
+		query = query.Where("outcome = ?", *auditEventCriteria.Outcome)
+	}
+	if auditEventCriteria.IpAddress != nil {
+		query = query.Where("ip_address = ?", *auditEventCriteria.IpAddress)
+	}
+	if auditEventCriteria.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *auditEventCriteria.CreatedAfter)
+	}
+	if auditEventCriteria.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *auditEventCriteria.CreatedBefore)
+	}
+
+	return query
+}
@@ -1,11 +1,22 @@
 package postgres
 
 import (
+	"fmt"
+	"github/imfropz/go-ddd/internal/infrastructure/config"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewConnection() (*gorm.DB, error) {
-	dsn := "host=localhost user=postgres password=postgres dbname=postgres port=5432 sslmode=disable"
+func NewConnection(postgresConfig config.PostgresConfig) (*gorm.DB, error) {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		postgresConfig.Host,
+		postgresConfig.User,
+		postgresConfig.Password,
+		postgresConfig.DBName,
+		postgresConfig.Port,
+		postgresConfig.SSLMode,
+	)
 	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
 }
@@ -7,10 +7,117 @@ import (
 )
 
 type User struct {
+	Id            uuid.UUID `gorm:"primaryKey"`
+	Name          string
+	Email         string `gorm:"unique"`
+	Password      string
+	EmailVerified bool `gorm:"not null;default:false"`
+	VerifiedAt    *time.Time
+	DisabledAt    *time.Time
+	DeletedAt     *time.Time `gorm:"index"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type OAuthClient struct {
+	Id           uuid.UUID `gorm:"primaryKey"`
+	OwnerId      uuid.UUID `gorm:"index"`
+	ClientId     string    `gorm:"unique"`
+	SecretHash   string
+	Name         string
+	RedirectUris []string `gorm:"serializer:json"`
+	Scopes       []string `gorm:"serializer:json"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type OAuthConsent struct {
+	Id        uuid.UUID `gorm:"primaryKey"`
+	UserId    uuid.UUID `gorm:"uniqueIndex:idx_oauth_consent_user_client"`
+	ClientId  string    `gorm:"uniqueIndex:idx_oauth_consent_user_client"`
+	Scopes    []string  `gorm:"serializer:json"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type MachineClient struct {
+	Id         uuid.UUID `gorm:"primaryKey"`
+	ClientId   string    `gorm:"unique"`
+	SecretHash string
+	Name       string
+	Scopes     []string `gorm:"serializer:json"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type ApiKey struct {
+	Id         uuid.UUID `gorm:"primaryKey"`
+	UserId     uuid.UUID `gorm:"index"`
+	Name       string
+	Prefix     string
+	KeyHash    string   `gorm:"unique"`
+	Scopes     []string `gorm:"serializer:json"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type TotpCredential struct {
 	Id        uuid.UUID `gorm:"primaryKey"`
-	Name      string
-	Email     string `gorm:"unique"`
-	Password  string
+	UserId    uuid.UUID `gorm:"unique"`
+	Secret    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+type Passkey struct {
+	Id           uuid.UUID `gorm:"primaryKey"`
+	UserId       uuid.UUID `gorm:"index"`
+	Name         string
+	CredentialId []byte `gorm:"unique"`
+	PublicKey    []byte
+	SignCount    uint32
+	Transports   []string `gorm:"serializer:json"`
+	LastUsedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type RecoveryCode struct {
+	Id        uuid.UUID `gorm:"primaryKey"`
+	UserId    uuid.UUID `gorm:"index"`
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type Role struct {
+	Id          uuid.UUID `gorm:"primaryKey"`
+	Name        string    `gorm:"unique"`
+	Description string
+	Permissions []string `gorm:"serializer:json"`
+	RequireMfa  bool     `gorm:"not null;default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type UserRole struct {
+	UserId    uuid.UUID `gorm:"primaryKey"`
+	RoleId    uuid.UUID `gorm:"primaryKey;index"`
+	CreatedAt time.Time
+}
+
+type AuditEvent struct {
+	Id        uuid.UUID  `gorm:"primaryKey"`
+	UserId    *uuid.UUID `gorm:"index"`
+	ActorId   *uuid.UUID
+	Email     string
+	Action    string `gorm:"index"`
+	Outcome   string
+	Reason    string
+	IpAddress string
+	UserAgent string
+	CreatedAt time.Time `gorm:"index"`
+}
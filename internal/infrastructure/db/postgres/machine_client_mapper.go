@@ -0,0 +1,31 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBMachineClient(client *entity.ValidatedMachineClient) *MachineClient {
+	c := &MachineClient{
+		ClientId:   client.ClientId,
+		SecretHash: client.SecretHash,
+		Name:       client.Name,
+		Scopes:     client.Scopes,
+		CreatedAt:  client.CreatedAt,
+		UpdatedAt:  client.UpdatedAt,
+	}
+	c.Id = client.Id
+
+	return c
+}
+
+func fromDBMachineClient(dbClient *MachineClient) *entity.MachineClient {
+	c := &entity.MachineClient{
+		ClientId:   dbClient.ClientId,
+		SecretHash: dbClient.SecretHash,
+		Name:       dbClient.Name,
+		Scopes:     dbClient.Scopes,
+		CreatedAt:  dbClient.CreatedAt,
+		UpdatedAt:  dbClient.UpdatedAt,
+	}
+	c.Id = dbClient.Id
+
+	return c
+}
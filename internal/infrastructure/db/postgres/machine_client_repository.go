@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type GormMachineClientRepository struct {
+	db *gorm.DB
+}
+
+func NewGormMachineClientRepository(db *gorm.DB) repository.MachineClientRepository {
+	return &GormMachineClientRepository{db: db}
+}
+
+func (repo *GormMachineClientRepository) Create(client *entity.ValidatedMachineClient) (*entity.MachineClient, error) {
+	dbClient := toDBMachineClient(client)
+
+	if err := repo.db.Create(dbClient).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindByClientId(dbClient.ClientId)
+}
+
+func (repo *GormMachineClientRepository) FindByClientId(clientId string) (*entity.MachineClient, error) {
+	var dbClient MachineClient
+	if err := repo.db.Model(&MachineClient{}).Where("client_id = ?", clientId).First(&dbClient).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBMachineClient(&dbClient), nil
+}
+
+func (repo *GormMachineClientRepository) FindAll() ([]*entity.MachineClient, error) {
+	var dbClients []MachineClient
+	if err := repo.db.Model(&MachineClient{}).Order("created_at").Find(&dbClients).Error; err != nil {
+		return nil, err
+	}
+
+	clients := make([]*entity.MachineClient, len(dbClients))
+	for i, dbClient := range dbClients {
+		clients[i] = fromDBMachineClient(&dbClient)
+	}
+
+	return clients, nil
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBOAuthClient(client *entity.ValidatedOAuthClient) *OAuthClient {
+	c := &OAuthClient{
+		OwnerId:      client.OwnerId,
+		ClientId:     client.ClientId,
+		SecretHash:   client.SecretHash,
+		Name:         client.Name,
+		RedirectUris: client.RedirectUris,
+		Scopes:       client.Scopes,
+		CreatedAt:    client.CreatedAt,
+		UpdatedAt:    client.UpdatedAt,
+	}
+	c.Id = client.Id
+
+	return c
+}
+
+func fromDBOAuthClient(dbClient *OAuthClient) *entity.OAuthClient {
+	c := &entity.OAuthClient{
+		OwnerId:      dbClient.OwnerId,
+		ClientId:     dbClient.ClientId,
+		SecretHash:   dbClient.SecretHash,
+		Name:         dbClient.Name,
+		RedirectUris: dbClient.RedirectUris,
+		Scopes:       dbClient.Scopes,
+		CreatedAt:    dbClient.CreatedAt,
+		UpdatedAt:    dbClient.UpdatedAt,
+	}
+	c.Id = dbClient.Id
+
+	return c
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormOAuthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOAuthClientRepository(db *gorm.DB) repository.OAuthClientRepository {
+	return &GormOAuthClientRepository{db: db}
+}
+
+func (repo *GormOAuthClientRepository) Create(client *entity.ValidatedOAuthClient) (*entity.OAuthClient, error) {
+	dbClient := toDBOAuthClient(client)
+
+	if err := repo.db.Create(dbClient).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindByClientId(dbClient.ClientId)
+}
+
+func (repo *GormOAuthClientRepository) FindByClientId(clientId string) (*entity.OAuthClient, error) {
+	var dbClient OAuthClient
+	if err := repo.db.Model(&OAuthClient{}).Where("client_id = ?", clientId).First(&dbClient).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBOAuthClient(&dbClient), nil
+}
+
+func (repo *GormOAuthClientRepository) FindAllByOwnerId(ownerId uuid.UUID) ([]*entity.OAuthClient, error) {
+	var dbClients []OAuthClient
+	if err := repo.db.Model(&OAuthClient{}).Where("owner_id = ?", ownerId).Order("created_at").Find(&dbClients).Error; err != nil {
+		return nil, err
+	}
+
+	clients := make([]*entity.OAuthClient, len(dbClients))
+	for i, dbClient := range dbClients {
+		clients[i] = fromDBOAuthClient(&dbClient)
+	}
+
+	return clients, nil
+}
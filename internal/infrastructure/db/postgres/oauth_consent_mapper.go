@@ -0,0 +1,29 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBOAuthConsent(consent *entity.ValidatedOAuthConsent) *OAuthConsent {
+	c := &OAuthConsent{
+		UserId:    consent.UserId,
+		ClientId:  consent.ClientId,
+		Scopes:    consent.Scopes,
+		CreatedAt: consent.CreatedAt,
+		UpdatedAt: consent.UpdatedAt,
+	}
+	c.Id = consent.Id
+
+	return c
+}
+
+func fromDBOAuthConsent(dbConsent *OAuthConsent) *entity.OAuthConsent {
+	c := &entity.OAuthConsent{
+		UserId:    dbConsent.UserId,
+		ClientId:  dbConsent.ClientId,
+		Scopes:    dbConsent.Scopes,
+		CreatedAt: dbConsent.CreatedAt,
+		UpdatedAt: dbConsent.UpdatedAt,
+	}
+	c.Id = dbConsent.Id
+
+	return c
+}
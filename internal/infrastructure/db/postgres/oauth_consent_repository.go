@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormOAuthConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOAuthConsentRepository(db *gorm.DB) repository.OAuthConsentRepository {
+	return &GormOAuthConsentRepository{db: db}
+}
+
+// Save creates the consent or replaces the scopes of the one the user already
+// gave the client.
+func (repo *GormOAuthConsentRepository) Save(consent *entity.ValidatedOAuthConsent) (*entity.OAuthConsent, error) {
+	dbConsent := toDBOAuthConsent(consent)
+
+	if err := repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(dbConsent).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindByUserIdAndClientId(dbConsent.UserId, dbConsent.ClientId)
+}
+
+func (repo *GormOAuthConsentRepository) FindByUserIdAndClientId(userId uuid.UUID, clientId string) (*entity.OAuthConsent, error) {
+	var dbConsent OAuthConsent
+	if err := repo.db.Model(&OAuthConsent{}).Where("user_id = ? AND client_id = ?", userId, clientId).First(&dbConsent).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBOAuthConsent(&dbConsent), nil
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBPasskey(passkey *entity.ValidatedPasskey) *Passkey {
+	p := &Passkey{
+		UserId:       passkey.UserId,
+		Name:         passkey.Name,
+		CredentialId: passkey.CredentialId,
+		PublicKey:    passkey.PublicKey,
+		SignCount:    passkey.SignCount,
+		Transports:   passkey.Transports,
+		LastUsedAt:   passkey.LastUsedAt,
+		CreatedAt:    passkey.CreatedAt,
+		UpdatedAt:    passkey.UpdatedAt,
+	}
+	p.Id = passkey.Id
+
+	return p
+}
+
+func fromDBPasskey(dbPasskey *Passkey) *entity.Passkey {
+	p := &entity.Passkey{
+		UserId:       dbPasskey.UserId,
+		Name:         dbPasskey.Name,
+		CredentialId: dbPasskey.CredentialId,
+		PublicKey:    dbPasskey.PublicKey,
+		SignCount:    dbPasskey.SignCount,
+		Transports:   dbPasskey.Transports,
+		LastUsedAt:   dbPasskey.LastUsedAt,
+		CreatedAt:    dbPasskey.CreatedAt,
+		UpdatedAt:    dbPasskey.UpdatedAt,
+	}
+	p.Id = dbPasskey.Id
+
+	return p
+}
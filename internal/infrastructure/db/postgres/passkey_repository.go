@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormPasskeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPasskeyRepository(db *gorm.DB) repository.PasskeyRepository {
+	return &GormPasskeyRepository{db: db}
+}
+
+func (repo *GormPasskeyRepository) Create(passkey *entity.ValidatedPasskey) (*entity.Passkey, error) {
+	dbPasskey := toDBPasskey(passkey)
+
+	if err := repo.db.Create(dbPasskey).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindById(dbPasskey.Id)
+}
+
+func (repo *GormPasskeyRepository) FindById(id uuid.UUID) (*entity.Passkey, error) {
+	var dbPasskey Passkey
+	if err := repo.db.First(&dbPasskey, id).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBPasskey(&dbPasskey), nil
+}
+
+func (repo *GormPasskeyRepository) FindByCredentialId(credentialId []byte) (*entity.Passkey, error) {
+	var dbPasskey Passkey
+	if err := repo.db.Model(&Passkey{}).Where("credential_id = ?", credentialId).First(&dbPasskey).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBPasskey(&dbPasskey), nil
+}
+
+func (repo *GormPasskeyRepository) FindAllByUserId(userId uuid.UUID) ([]*entity.Passkey, error) {
+	var dbPasskeys []Passkey
+	if err := repo.db.Model(&Passkey{}).Where("user_id = ?", userId).Order("created_at").Find(&dbPasskeys).Error; err != nil {
+		return nil, err
+	}
+
+	passkeys := make([]*entity.Passkey, len(dbPasskeys))
+	for i, dbPasskey := range dbPasskeys {
+		passkeys[i] = fromDBPasskey(&dbPasskey)
+	}
+
+	return passkeys, nil
+}
+
+func (repo *GormPasskeyRepository) Update(passkey *entity.ValidatedPasskey) (*entity.Passkey, error) {
+	dbPasskey := toDBPasskey(passkey)
+
+	if err := repo.db.Model(&Passkey{}).Where("id = ?", dbPasskey.Id).Updates(dbPasskey).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindById(dbPasskey.Id)
+}
+
+func (repo *GormPasskeyRepository) Delete(id uuid.UUID) error {
+	return repo.db.Delete(&Passkey{}, id).Error
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBRecoveryCode(code *entity.ValidatedRecoveryCode) *RecoveryCode {
+	c := &RecoveryCode{
+		UserId:    code.UserId,
+		CodeHash:  code.CodeHash,
+		UsedAt:    code.UsedAt,
+		CreatedAt: code.CreatedAt,
+	}
+	c.Id = code.Id
+
+	return c
+}
+
+func fromDBRecoveryCode(dbCode *RecoveryCode) *entity.RecoveryCode {
+	c := &entity.RecoveryCode{
+		UserId:    dbCode.UserId,
+		CodeHash:  dbCode.CodeHash,
+		UsedAt:    dbCode.UsedAt,
+		CreatedAt: dbCode.CreatedAt,
+	}
+	c.Id = dbCode.Id
+
+	return c
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRecoveryCodeRepository(db *gorm.DB) repository.RecoveryCodeRepository {
+	return &GormRecoveryCodeRepository{db: db}
+}
+
+func (repo *GormRecoveryCodeRepository) ReplaceAllByUserId(userId uuid.UUID, codes []*entity.ValidatedRecoveryCode) error {
+	dbCodes := make([]*RecoveryCode, len(codes))
+	for i, code := range codes {
+		dbCodes[i] = toDBRecoveryCode(code)
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(dbCodes) == 0 {
+			return nil
+		}
+		return tx.Create(dbCodes).Error
+	})
+}
+
+func (repo *GormRecoveryCodeRepository) FindUnusedByUserId(userId uuid.UUID) ([]*entity.RecoveryCode, error) {
+	var dbCodes []RecoveryCode
+	if err := repo.db.Model(&RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userId).Find(&dbCodes).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]*entity.RecoveryCode, len(dbCodes))
+	for i, dbCode := range dbCodes {
+		codes[i] = fromDBRecoveryCode(&dbCode)
+	}
+
+	return codes, nil
+}
+
+func (repo *GormRecoveryCodeRepository) CountUnusedByUserId(userId uuid.UUID) (int, error) {
+	var count int64
+	if err := repo.db.Model(&RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (repo *GormRecoveryCodeRepository) MarkUsed(id uuid.UUID) (bool, error) {
+	result := repo.db.Model(&RecoveryCode{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (repo *GormRecoveryCodeRepository) DeleteByUserId(userId uuid.UUID) error {
+	return repo.db.Where("user_id = ?", userId).Delete(&RecoveryCode{}).Error
+}
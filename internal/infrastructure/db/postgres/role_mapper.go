@@ -0,0 +1,31 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBRole(role *entity.ValidatedRole) *Role {
+	r := &Role{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		RequireMfa:  role.RequireMfa,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+	r.Id = role.Id
+
+	return r
+}
+
+func fromDBRole(dbRole *Role) *entity.Role {
+	r := &entity.Role{
+		Name:        dbRole.Name,
+		Description: dbRole.Description,
+		Permissions: dbRole.Permissions,
+		RequireMfa:  dbRole.RequireMfa,
+		CreatedAt:   dbRole.CreatedAt,
+		UpdatedAt:   dbRole.UpdatedAt,
+	}
+	r.Id = dbRole.Id
+
+	return r
+}
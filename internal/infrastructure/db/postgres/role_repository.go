@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRoleRepository(db *gorm.DB) repository.RoleRepository {
+	return &GormRoleRepository{db: db}
+}
+
+func (repo *GormRoleRepository) Create(role *entity.ValidatedRole) (*entity.Role, error) {
+	dbRole := toDBRole(role)
+
+	if err := repo.db.Create(dbRole).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindByName(dbRole.Name)
+}
+
+// Update saves every column, so a role can stop requiring MFA.
+func (repo *GormRoleRepository) Update(role *entity.ValidatedRole) (*entity.Role, error) {
+	dbRole := toDBRole(role)
+
+	if err := repo.db.Model(&Role{}).Where("id = ?", dbRole.Id).Select("*").Omit("created_at").Updates(dbRole).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindByName(dbRole.Name)
+}
+
+func (repo *GormRoleRepository) FindAll() ([]*entity.Role, error) {
+	var dbRoles []Role
+	if err := repo.db.Model(&Role{}).Order("name").Find(&dbRoles).Error; err != nil {
+		return nil, err
+	}
+
+	roles := make([]*entity.Role, len(dbRoles))
+	for i := range dbRoles {
+		roles[i] = fromDBRole(&dbRoles[i])
+	}
+
+	return roles, nil
+}
+
+func (repo *GormRoleRepository) FindByName(name string) (*entity.Role, error) {
+	var dbRole Role
+	if err := repo.db.Model(&Role{}).Where("name = ?", name).First(&dbRole).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBRole(&dbRole), nil
+}
+
+func (repo *GormRoleRepository) FindByUserId(userId uuid.UUID) ([]*entity.Role, error) {
+	var dbRoles []Role
+	if err := repo.db.Model(&Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userId).
+		Order("roles.name").
+		Find(&dbRoles).Error; err != nil {
+		return nil, err
+	}
+
+	roles := make([]*entity.Role, len(dbRoles))
+	for i := range dbRoles {
+		roles[i] = fromDBRole(&dbRoles[i])
+	}
+
+	return roles, nil
+}
+
+func (repo *GormRoleRepository) AssignToUser(userId uuid.UUID, roleId uuid.UUID) error {
+	return repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&UserRole{
+		UserId:    userId,
+		RoleId:    roleId,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+func (repo *GormRoleRepository) UnassignFromUser(userId uuid.UUID, roleId uuid.UUID) error {
+	return repo.db.Where("user_id = ? AND role_id = ?", userId, roleId).Delete(&UserRole{}).Error
+}
+
+func (repo *GormRoleRepository) CountUsers(roleId uuid.UUID) (int64, error) {
+	var count int64
+	if err := repo.db.Model(&UserRole{}).Where("role_id = ?", roleId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
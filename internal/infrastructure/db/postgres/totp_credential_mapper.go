@@ -0,0 +1,27 @@
+package postgres
+
+import "github/imfropz/go-ddd/internal/domain/entity"
+
+func toDBTotpCredential(credential *entity.ValidatedTotpCredential) *TotpCredential {
+	c := &TotpCredential{
+		UserId:    credential.UserId,
+		Secret:    credential.Secret,
+		CreatedAt: credential.CreatedAt,
+		UpdatedAt: credential.UpdatedAt,
+	}
+	c.Id = credential.Id
+
+	return c
+}
+
+func fromDBTotpCredential(dbCredential *TotpCredential) *entity.TotpCredential {
+	c := &entity.TotpCredential{
+		UserId:    dbCredential.UserId,
+		Secret:    dbCredential.Secret,
+		CreatedAt: dbCredential.CreatedAt,
+		UpdatedAt: dbCredential.UpdatedAt,
+	}
+	c.Id = dbCredential.Id
+
+	return c
+}
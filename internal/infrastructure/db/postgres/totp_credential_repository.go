@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormTotpCredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTotpCredentialRepository(db *gorm.DB) repository.TotpCredentialRepository {
+	return &GormTotpCredentialRepository{db: db}
+}
+
+func (repo *GormTotpCredentialRepository) Create(credential *entity.ValidatedTotpCredential) (*entity.TotpCredential, error) {
+	dbCredential := toDBTotpCredential(credential)
+
+	if err := repo.db.Create(dbCredential).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindByUserId(dbCredential.UserId)
+}
+
+func (repo *GormTotpCredentialRepository) FindByUserId(userId uuid.UUID) (*entity.TotpCredential, error) {
+	var dbCredential TotpCredential
+	if err := repo.db.Model(&TotpCredential{}).Where("user_id = ?", userId).First(&dbCredential).Error; err != nil {
+		return nil, err
+	}
+
+	return fromDBTotpCredential(&dbCredential), nil
+}
+
+func (repo *GormTotpCredentialRepository) ExistsByUserId(userId uuid.UUID) (bool, error) {
+	var count int64
+	if err := repo.db.Model(&TotpCredential{}).Where("user_id = ?", userId).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (repo *GormTotpCredentialRepository) DeleteByUserId(userId uuid.UUID) error {
+	return repo.db.Where("user_id = ?", userId).Delete(&TotpCredential{}).Error
+}
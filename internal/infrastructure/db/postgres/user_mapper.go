@@ -4,11 +4,15 @@ import "github/imfropz/go-ddd/internal/domain/entity"
 
 func toDBUser(user *entity.ValidatedUser) *User {
 	u := &User{
-		Name:      user.Name,
-		Email:     user.Email,
-		Password:  user.Password,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		Name:          user.Name,
+		Email:         user.Email,
+		Password:      user.Password,
+		EmailVerified: user.EmailVerified,
+		VerifiedAt:    user.VerifiedAt,
+		DisabledAt:    user.DisabledAt,
+		DeletedAt:     user.DeletedAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}
 	u.Id = user.Id
 
@@ -17,11 +21,15 @@ func toDBUser(user *entity.ValidatedUser) *User {
 
 func fromDBUser(dbUser *User) *entity.User {
 	u := &entity.User{
-		Name:      dbUser.Name,
-		Email:     dbUser.Email,
-		Password:  dbUser.Password,
-		CreatedAt: dbUser.CreatedAt,
-		UpdatedAt: dbUser.UpdatedAt,
+		Name:          dbUser.Name,
+		Email:         dbUser.Email,
+		Password:      dbUser.Password,
+		EmailVerified: dbUser.EmailVerified,
+		VerifiedAt:    dbUser.VerifiedAt,
+		DisabledAt:    dbUser.DisabledAt,
+		DeletedAt:     dbUser.DeletedAt,
+		CreatedAt:     dbUser.CreatedAt,
+		UpdatedAt:     dbUser.UpdatedAt,
 	}
 	u.Id = dbUser.Id
 
@@ -1,12 +1,17 @@
 package postgres
 
 import (
+	"errors"
+	"fmt"
 	"github/imfropz/go-ddd/internal/domain/criteria"
 	"github/imfropz/go-ddd/internal/domain/entity"
 	"github/imfropz/go-ddd/internal/domain/repository"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormUserRepository struct {
@@ -46,14 +51,28 @@ func (repo *GormUserRepository) FindByEmail(email string) (*entity.User, error)
 }
 
 func (repo *GormUserRepository) FindAll(userCriteria *criteria.UserCriteria) ([]*entity.User, error) {
-	query := repo.db.Model(&User{})
+	query := filterUsers(repo.db.Model(&User{}), userCriteria)
 
 	if userCriteria != nil {
-		if userCriteria.Id != uuid.Nil {
-			query = query.Where("id = ?", userCriteria.Id)
+		column := userCriteria.SortColumn()
+		direction, compare := "ASC", ">"
+		if userCriteria.Desc {
+			direction, compare = "DESC", "<"
 		}
-		if userCriteria.Name != nil {
-			query = query.Where("name = ?", userCriteria.Name)
+
+		if userCriteria.Cursor != nil {
+			value, err := cursorValue(column, userCriteria.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, compare), value, userCriteria.Cursor.Id)
+		} else if userCriteria.Page > 1 && userCriteria.Limit > 0 {
+			query = query.Offset((userCriteria.Page - 1) * userCriteria.Limit)
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction))
+		if userCriteria.Limit > 0 {
+			query = query.Limit(userCriteria.Limit)
 		}
 	}
 
@@ -70,6 +89,15 @@ func (repo *GormUserRepository) FindAll(userCriteria *criteria.UserCriteria) ([]
 	return users, nil
 }
 
+func (repo *GormUserRepository) Count(userCriteria *criteria.UserCriteria) (int64, error) {
+	var count int64
+	if err := filterUsers(repo.db.Model(&User{}), userCriteria).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (repo *GormUserRepository) Update(user *entity.ValidatedUser) (*entity.User, error) {
 	dbUser := toDBUser(user)
 
@@ -80,6 +108,137 @@ func (repo *GormUserRepository) Update(user *entity.ValidatedUser) (*entity.User
 	return repo.FindById(dbUser.Id)
 }
 
-func (repo *GormUserRepository) Delete(id uuid.UUID) error {
-	return repo.db.Delete(&User{}, id).Error
+func (repo *GormUserRepository) UpdateStatus(user *entity.ValidatedUser) (*entity.User, error) {
+	if err := repo.db.Model(&User{}).Where("id = ?", user.Id).Updates(map[string]interface{}{
+		"disabled_at": user.DisabledAt,
+		"deleted_at":  user.DeletedAt,
+		"updated_at":  user.UpdatedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.FindById(user.Id)
+}
+
+func (repo *GormUserRepository) FindDeletedBefore(before time.Time, limit int) ([]*entity.User, error) {
+	var dbUsers []User
+	if err := repo.db.Model(&User{}).Where("deleted_at < ?", before).Order("deleted_at").Limit(limit).Find(&dbUsers).Error; err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = fromDBUser(&dbUser)
+	}
+
+	return users, nil
+}
+
+func (repo *GormUserRepository) DeleteIfDeletedBefore(id uuid.UUID, before time.Time) (bool, error) {
+	deleted := false
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		// The row stays locked until the commit, so a restore racing with the
+		// purge either lands first and is seen here, or finds no user left.
+		var dbUser User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NOT NULL AND deleted_at < ?", id, before).
+			Take(&dbUser).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&UserRole{}, &ApiKey{}, &TotpCredential{}, &Passkey{}, &RecoveryCode{}, &OAuthConsent{}} {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("client_id IN (?)", tx.Model(&OAuthClient{}).Select("client_id").Where("owner_id = ?", id)).Delete(&OAuthConsent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("owner_id = ?", id).Delete(&OAuthClient{}).Error; err != nil {
+			return err
+		}
+		// Audit events are kept, but no longer name the user or where they
+		// signed in from. Failed logins are matched by email as they may have
+		// no user id.
+		if err := tx.Model(&AuditEvent{}).
+			Where("user_id = ? OR actor_id = ? OR email = ?", id, id, dbUser.Email).
+			Updates(map[string]interface{}{"email": "", "ip_address": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&User{}, id).Error; err != nil {
+			return err
+		}
+
+		deleted = true
+		return nil
+	})
+
+	return deleted, err
+}
+
+func filterUsers(query *gorm.DB, userCriteria *criteria.UserCriteria) *gorm.DB {
+	if userCriteria == nil {
+		return query
+	}
+
+	if userCriteria.Id != uuid.Nil {
+		query = query.Where("id = ?", userCriteria.Id)
+	}
+	if userCriteria.Name != nil {
+		query = query.Where("name ILIKE ?", containsPattern(*userCriteria.Name))
+	}
+	if userCriteria.Email != nil {
+		query = query.Where("email ILIKE ?", containsPattern(*userCriteria.Email))
+	}
+	if userCriteria.Status != nil {
+		switch *userCriteria.Status {
+		case criteria.USER_STATUS_ACTIVE:
+			query = query.Where("disabled_at IS NULL AND deleted_at IS NULL")
+		case criteria.USER_STATUS_DISABLED:
+			query = query.Where("disabled_at IS NOT NULL")
+		case criteria.USER_STATUS_DELETED:
+			query = query.Where("deleted_at IS NOT NULL")
+		}
+	}
+	if userCriteria.EmailVerified != nil {
+		query = query.Where("email_verified = ?", *userCriteria.EmailVerified)
+	}
+	if userCriteria.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *userCriteria.CreatedAfter)
+	}
+	if userCriteria.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *userCriteria.CreatedBefore)
+	}
+	if userCriteria.UpdatedAfter != nil {
+		query = query.Where("updated_at >= ?", *userCriteria.UpdatedAfter)
+	}
+	if userCriteria.UpdatedBefore != nil {
+		query = query.Where("updated_at < ?", *userCriteria.UpdatedBefore)
+	}
+
+	return query
+}
+
+// containsPattern matches value anywhere, with the LIKE wildcards in it taken
+// literally.
+func containsPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
+
+// cursorValue converts the cursor back to the type of the sort column.
+func cursorValue(column string, cursor *criteria.UserCursor) (interface{}, error) {
+	if column != criteria.USER_SORT_CREATED_AT {
+		return cursor.Value, nil
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+	if err != nil {
+		return nil, criteria.ErrInvalidCursor
+	}
+	return createdAt, nil
 }
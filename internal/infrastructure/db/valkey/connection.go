@@ -1,7 +1,11 @@
 package valkey
 
-import "github.com/valkey-io/valkey-go"
+import (
+	"github/imfropz/go-ddd/internal/infrastructure/config"
 
-func NewConnection() (client valkey.Client, err error) {
-	return valkey.NewClient(valkey.ClientOption{InitAddress: []string{"localhost:6379"}, Password: "valkey-password"})
+	"github.com/valkey-io/valkey-go"
+)
+
+func NewConnection(valkeyConfig config.ValkeyConfig) (client valkey.Client, err error) {
+	return valkey.NewClient(valkey.ClientOption{InitAddress: valkeyConfig.Addresses, Password: valkeyConfig.Password})
 }
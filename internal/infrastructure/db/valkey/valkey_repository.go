@@ -3,6 +3,7 @@ package valkey
 import (
 	"context"
 	"fmt"
+	"github/imfropz/go-ddd/internal/infrastructure/config"
 
 	"github.com/valkey-io/valkey-go"
 )
@@ -11,8 +12,8 @@ type ValkeyRepository struct {
 	client valkey.Client
 }
 
-func NewValkeyRepository() (*ValkeyRepository, error) {
-	client, err := NewConnection()
+func NewValkeyRepository(valkeyConfig config.ValkeyConfig) (*ValkeyRepository, error) {
+	client, err := NewConnection(valkeyConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +36,22 @@ func (r *ValkeyRepository) Set(ctx context.Context, key string, value interface{
 	return r.client.Do(ctx, cmd.Build()).Error()
 }
 
+// SetNX only sets the key when it does not exist yet, and reports whether it
+// did.
+func (r *ValkeyRepository) SetNX(ctx context.Context, key string, value interface{}, ttl int) (bool, error) {
+	val := toString(value)
+	cmd := r.client.B().Set().Key(key).Value(val).Nx()
+	if ttl > 0 {
+		cmd.ExSeconds(int64(ttl))
+	}
+
+	err := r.client.Do(ctx, cmd.Build()).Error()
+	if valkey.IsValkeyNil(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (r *ValkeyRepository) Delete(ctx context.Context, keys ...string) error {
 	return r.client.Do(ctx, r.client.B().Del().Key(keys...).Build()).Error()
 }
@@ -78,6 +95,10 @@ func (r *ValkeyRepository) HGetAll(ctx context.Context, key string) (map[string]
 	return res, nil
 }
 
+func (r *ValkeyRepository) HDel(ctx context.Context, key string, fields ...string) error {
+	return r.client.Do(ctx, r.client.B().Hdel().Key(key).Field(fields...).Build()).Error()
+}
+
 func (r *ValkeyRepository) LPush(ctx context.Context, key string, values ...interface{}) error {
 	strValues := make([]string, 0, len(values))
 	for _, v := range values {
@@ -93,6 +114,17 @@ func (r *ValkeyRepository) LRange(ctx context.Context, key string, start, stop i
 	return r.client.Do(ctx, cmd).AsStrSlice()
 }
 
+// Eval runs a Lua script atomically. It is sent by its SHA1 first, so the
+// script body only goes over the wire the first time.
+func (r *ValkeyRepository) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	strArgs := make([]string, 0, len(args))
+	for _, v := range args {
+		strArgs = append(strArgs, toString(v))
+	}
+
+	return valkey.NewLuaScript(script).Exec(ctx, r.client, keys, strArgs).ToAny()
+}
+
 func (r *ValkeyRepository) Close() {
 	r.client.Close()
 }
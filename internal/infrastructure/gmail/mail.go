@@ -4,21 +4,15 @@ import (
 	"bytes"
 	"crypto/tls"
 	"fmt"
+	"github/imfropz/go-ddd/internal/infrastructure/config"
 	"net/smtp"
 )
 
-type SMTPConfig struct {
-	SMTPHost     string
-	SMTPPort     string
-	SMTPUsername string
-	SMTPPassword string
-}
-
 type GmailMail struct {
-	smtpConfig SMTPConfig
+	smtpConfig config.SMTPConfig
 }
 
-func NewGmailMail(smtpConfig SMTPConfig) *GmailMail {
+func NewGmailMail(smtpConfig config.SMTPConfig) *GmailMail {
 	return &GmailMail{
 		smtpConfig: smtpConfig,
 	}
@@ -63,7 +57,7 @@ func (mail *GmailMail) SendToEmail(fromEmail string, toEmails []string, subject
 	return nil
 }
 
-func createSMTPClient(smtpConfig *SMTPConfig) (*smtp.Client, error) {
+func createSMTPClient(smtpConfig *config.SMTPConfig) (*smtp.Client, error) {
 	auth := smtp.PlainAuth("", smtpConfig.SMTPUsername, smtpConfig.SMTPPassword, smtpConfig.SMTPHost)
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: false, // Should be false in production
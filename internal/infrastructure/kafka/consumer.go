@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github/imfropz/go-ddd/internal/domain/event"
+	"github/imfropz/go-ddd/internal/infrastructure/config"
 	"log/slog"
 	"sync"
 
@@ -12,19 +13,19 @@ import (
 
 type SaramaConsumer struct {
 	consumer sarama.ConsumerGroup
-	config   *SaramaConfig
+	config   *config.KafkaConfig
 	ctx      context.Context
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
 }
 
-func NewSaramaConsumer(config *SaramaConfig, groupId string) (*SaramaConsumer, error) {
-	kafkaConfig, err := createSaramaConfig(config)
+func NewSaramaConsumer(kafkaConfig *config.KafkaConfig) (*SaramaConsumer, error) {
+	saramaConfig, err := createSaramaConfig(kafkaConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	consumer, err := sarama.NewConsumerGroup(config.Brokers, groupId, kafkaConfig)
+	consumer, err := sarama.NewConsumerGroup(kafkaConfig.Brokers, kafkaConfig.ConsumerGroup, saramaConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +34,7 @@ func NewSaramaConsumer(config *SaramaConfig, groupId string) (*SaramaConsumer, e
 
 	return &SaramaConsumer{
 		consumer: consumer,
-		config:   config,
+		config:   kafkaConfig,
 		ctx:      ctx,
 		cancel:   cancel,
 	}, nil
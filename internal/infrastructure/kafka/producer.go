@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"github/imfropz/go-ddd/internal/domain/event"
+	"github/imfropz/go-ddd/internal/infrastructure/config"
 	"sync"
 
 	"github.com/IBM/sarama"
@@ -11,41 +12,35 @@ import (
 
 type SaramaProducer struct {
 	producer sarama.SyncProducer
-	config   *SaramaConfig
+	config   *config.KafkaConfig
 	mu       sync.Mutex
 }
 
-type SaramaConfig struct {
-	Brokers  []string
-	Version  string
-	ClientID string
-}
-
-func NewSaramaProducer(config *SaramaConfig) (*SaramaProducer, error) {
-	kafkaConfig, err := createSaramaConfig(config)
+func NewSaramaProducer(kafkaConfig *config.KafkaConfig) (*SaramaProducer, error) {
+	saramaConfig, err := createSaramaConfig(kafkaConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	producer, err := sarama.NewSyncProducer(config.Brokers, kafkaConfig)
+	producer, err := sarama.NewSyncProducer(kafkaConfig.Brokers, saramaConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	return &SaramaProducer{
 		producer: producer,
-		config:   config,
+		config:   kafkaConfig,
 	}, nil
 }
 
-func createSaramaConfig(config *SaramaConfig) (*sarama.Config, error) {
+func createSaramaConfig(kafkaConfig *config.KafkaConfig) (*sarama.Config, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.Producer.Return.Successes = true
 	saramaConfig.Producer.Retry.Max = 3
 	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
-	saramaConfig.ClientID = config.ClientID
+	saramaConfig.ClientID = kafkaConfig.ClientID
 
-	version, err := sarama.ParseKafkaVersion(config.Version)
+	version, err := sarama.ParseKafkaVersion(kafkaConfig.Version)
 	if err != nil {
 		return nil, err
 	}
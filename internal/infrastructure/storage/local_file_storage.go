@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalFileStorage keeps files in a single directory on the local disk. Names
+// are reduced to their last element so they cannot point outside of it.
+type LocalFileStorage struct {
+	directory string
+}
+
+func NewLocalFileStorage(directory string) (*LocalFileStorage, error) {
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &LocalFileStorage{
+		directory: directory,
+	}, nil
+}
+
+// Save writes to a temporary file first, so a file being read is never seen
+// half written.
+func (s *LocalFileStorage) Save(name string, data []byte) error {
+	file, err := os.CreateTemp(s.directory, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path(name))
+}
+
+func (s *LocalFileStorage) Open(name string) (io.ReadCloser, error) {
+	return os.Open(s.path(name))
+}
+
+func (s *LocalFileStorage) Delete(name string) error {
+	if err := os.Remove(s.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalFileStorage) DeleteBefore(before time.Time) (int, error) {
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !info.ModTime().Before(before) {
+			continue
+		}
+		if err := os.Remove(s.path(entry.Name())); err != nil && !os.IsNotExist(err) {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (s *LocalFileStorage) path(name string) string {
+	return filepath.Join(s.directory, filepath.Base(name))
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type ApiKeyController struct {
+	service interfaces.ApiKeyService
+}
+
+func NewApiKeyController(r *mux.Router, service interfaces.ApiKeyService, authenticator *middleware.Authenticator) *ApiKeyController {
+	controller := ApiKeyController{
+		service: service,
+	}
+
+	r.Handle("/api/v1/api-keys", authenticator.SessionHandler(http.HandlerFunc(controller.ListApiKeysV1))).Methods(http.MethodGet)
+	r.Handle("/api/v1/api-keys", authenticator.SessionHandler(http.HandlerFunc(controller.CreateApiKeyV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/revoke-api-key", authenticator.SessionHandler(http.HandlerFunc(controller.RevokeApiKeyV1))).Methods(http.MethodPost)
+
+	return &controller
+}
+
+func (ac *ApiKeyController) ListApiKeysV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := ac.service.ListApiKeys(&command.ListApiKeysCommand{
+		UserId: claims.Id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToApiKeyListResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateApiKeyV1 answers with the plain key, which cannot be retrieved again.
+func (ac *ApiKeyController) CreateApiKeyV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewCreateApiKeyRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := ac.service.CreateApiKey(req.ToCreateApiKeyCommand(claims.Id))
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on create api key: %v", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToApiKeyResponse(result.Result)
+	response.Key = result.Key
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (ac *ApiKeyController) RevokeApiKeyV1(w http.ResponseWriter, r *http.Request) {
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewRevokeApiKeyRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := ac.service.RevokeApiKey(req.ToRevokeApiKeyCommand(claims.Id)); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
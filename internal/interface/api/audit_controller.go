@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/filter"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type AuditController struct {
+	service interfaces.AuditService
+}
+
+// NewAuditController registers the security history of the signed in user,
+// and the audit log across users, which needs a session holding audit:read.
+func NewAuditController(r *mux.Router, service interfaces.AuditService, authenticator *middleware.Authenticator) *AuditController {
+	controller := AuditController{
+		service: service,
+	}
+
+	r.Handle("/api/v1/profile/audit-events", authenticator.AuthenticationHandler(http.HandlerFunc(controller.ListProfileAuditEventsV1))).Methods(http.MethodGet)
+	r.Handle("/api/v1/admin/audit-events", authenticator.SessionHandler(middleware.RequirePermission(http.HandlerFunc(controller.ListAuditEventsV1), entity.PERMISSION_AUDIT_READ))).Methods(http.MethodGet)
+
+	return &controller
+}
+
+func (ac *AuditController) ListProfileAuditEventsV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	auditEventCriteria, err := filter.RequestToAuditEventCriteria(*r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	events, err := ac.service.ListUserAuditEvents(&command.ListUserAuditEventsCommand{
+		UserId:   claims.Id,
+		Criteria: auditEventCriteria,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on list profile audit events: %v", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := mapper.ToAuditEventListResponse(events.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (ac *AuditController) ListAuditEventsV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	auditEventCriteria, err := filter.RequestToAuditEventCriteria(*r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	events, err := ac.service.ListAuditEvents(&command.ListAuditEventsCommand{
+		Criteria: auditEventCriteria,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on list audit events: %v", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := mapper.ToAuditEventListResponse(events.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -2,37 +2,71 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github/imfropz/go-ddd/common/util"
 	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
 	"github/imfropz/go-ddd/internal/application/interfaces"
-	"github/imfropz/go-ddd/internal/domain/repository"
+	"github/imfropz/go-ddd/internal/domain/entity"
 	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
 	"github/imfropz/go-ddd/internal/interface/api/dto/request"
 	"github/imfropz/go-ddd/internal/interface/api/middleware"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 type AuthenticateController struct {
-	service interfaces.AuthenticateService
+	service        interfaces.AuthenticateService
+	tokenService   interfaces.TokenService
+	mfaService     interfaces.MfaService
+	trustedProxies []netip.Prefix
 }
 
-func NewAuthenticateController(r *mux.Router, service interfaces.AuthenticateService, userRepository repository.UserRepository) *AuthenticateController {
+// Limits for the public endpoints that send emails, create accounts or check
+// passwords. Failed passwords are also locked out per account by the service.
+var (
+	loginRateLimit         = middleware.RateLimitPolicy{Name: "login", Limit: 20, Window: time.Minute}
+	registerRateLimit      = middleware.RateLimitPolicy{Name: "register", Limit: 5, Window: time.Hour}
+	emailRateLimit         = middleware.RateLimitPolicy{Name: "email", Limit: 30, Window: time.Hour}
+	resetPasswordRateLimit = middleware.RateLimitPolicy{Name: "reset-password", Limit: 3, Window: time.Hour, Key: middleware.ByEmail}
+	magicLinkRateLimit     = middleware.RateLimitPolicy{Name: "magic-link", Limit: 5, Window: time.Hour, Key: middleware.ByEmail}
+	resendVerifyRateLimit  = middleware.RateLimitPolicy{Name: "resend-verify-email", Limit: 3, Window: time.Hour, Key: middleware.ByEmail}
+	updateProfileRateLimit = middleware.RateLimitPolicy{Name: "update-profile", Limit: 10, Window: time.Hour, Key: middleware.ByUserId}
+	emailTokenRateLimit    = middleware.RateLimitPolicy{Name: "email-token", Limit: 20, Window: time.Minute}
+)
+
+func NewAuthenticateController(r *mux.Router, service interfaces.AuthenticateService, tokenService interfaces.TokenService, mfaService interfaces.MfaService, authenticator *middleware.Authenticator, rateLimit *middleware.RateLimit, trustedProxies []netip.Prefix) *AuthenticateController {
 	controller := AuthenticateController{
-		service: service,
+		service:        service,
+		tokenService:   tokenService,
+		mfaService:     mfaService,
+		trustedProxies: trustedProxies,
 	}
 
-	r.Handle("/api/v1/profile", middleware.AuthenticationHandler(http.HandlerFunc(controller.ProfileV1), userRepository)).Methods(http.MethodGet)
-	r.Handle("/api/v1/login", http.HandlerFunc(controller.LoginV1)).Methods(http.MethodPost)
-	r.Handle("/api/v1/register", http.HandlerFunc(controller.RegisterV1)).Methods(http.MethodPost)
-	r.Handle("/api/v1/update-profile", middleware.AuthenticationHandler(http.HandlerFunc(controller.UpdateProfileV1), userRepository)).Methods(http.MethodPost)
-	r.Handle("/api/v1/reset-password", http.HandlerFunc(controller.ResetPasswordV1)).Methods(http.MethodPost)
-	r.Handle("/api/v1/reset-password-with-token", http.HandlerFunc(controller.ResetPasswordWithTokenV1)).Methods(http.MethodPost)
-	r.Handle("/api/v1/refresh-token", middleware.AuthenticationHandler(http.HandlerFunc(controller.RefreshTokenV1), userRepository)).Methods(http.MethodPost)
-	r.Handle("/api/v1/delete-profile", middleware.AuthenticationHandler(http.HandlerFunc(controller.DeleteProfileV1), userRepository)).Methods(http.MethodPost)
+	r.Handle("/api/v1/profile", authenticator.AuthenticationHandler(http.HandlerFunc(controller.ProfileV1))).Methods(http.MethodGet)
+	r.Handle("/api/v1/login", rateLimit.Handler(http.HandlerFunc(controller.LoginV1), loginRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/login/mfa", rateLimit.Handler(http.HandlerFunc(controller.LoginMfaV1), loginRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/login/magic-link", rateLimit.Handler(rateLimit.Handler(http.HandlerFunc(controller.RequestMagicLinkV1), magicLinkRateLimit), emailRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/login/magic-link/verify", rateLimit.Handler(http.HandlerFunc(controller.LoginWithMagicLinkV1), emailTokenRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/register", rateLimit.Handler(http.HandlerFunc(controller.RegisterV1), registerRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/update-profile", authenticator.AuthenticationHandler(rateLimit.Handler(http.HandlerFunc(controller.UpdateProfileV1), updateProfileRateLimit))).Methods(http.MethodPost)
+	r.Handle("/api/v1/reset-password", rateLimit.Handler(rateLimit.Handler(http.HandlerFunc(controller.ResetPasswordV1), resetPasswordRateLimit), emailRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/reset-password-with-token", rateLimit.Handler(http.HandlerFunc(controller.ResetPasswordWithTokenV1), emailTokenRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/refresh-token", http.HandlerFunc(controller.RefreshTokenV1)).Methods(http.MethodPost)
+	r.Handle("/api/v1/verify-email", rateLimit.Handler(http.HandlerFunc(controller.VerifyEmailV1), emailTokenRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/resend-verify-email", rateLimit.Handler(rateLimit.Handler(http.HandlerFunc(controller.ResendVerifyEmailV1), resendVerifyRateLimit), emailRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/confirm-email-change", rateLimit.Handler(http.HandlerFunc(controller.ConfirmEmailChangeV1), emailTokenRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/revert-email-change", rateLimit.Handler(http.HandlerFunc(controller.RevertEmailChangeV1), emailTokenRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/logout", authenticator.UnverifiedSessionHandler(http.HandlerFunc(controller.LogoutV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/delete-profile", authenticator.UnverifiedSessionHandler(http.HandlerFunc(controller.DeleteProfileV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/restore-account", rateLimit.Handler(http.HandlerFunc(controller.RestoreAccountV1), emailTokenRateLimit)).Methods(http.MethodPost)
 
 	return &controller
 }
@@ -40,10 +74,13 @@ func NewAuthenticateController(r *mux.Router, service interfaces.AuthenticateSer
 func (ac *AuthenticateController) ProfileV1(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 
-	claims := r.Context().Value(util.AccessTokenClaims{}).(util.AccessTokenClaims)
+	claims := util.MustAccessTokenClaims(r.Context())
 
+	clientInfo := request.NewClientInfo(r, ac.trustedProxies)
 	profileCommand := command.ProfileCommand{
-		Email: claims.Email,
+		Email:     claims.Email,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
 	}
 
 	user, err := ac.service.Profile(&profileCommand)
@@ -67,19 +104,63 @@ func (ac *AuthenticateController) LoginV1(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	loginCommand := req.ToLoginCommand()
+	loginCommand := req.ToLoginCommand(request.NewClientInfo(r, ac.trustedProxies))
 	user, err := ac.service.Login(loginCommand)
+	var locked *entity.LoginLockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if errors.Is(err, entity.ErrEmailNotVerified) || errors.Is(err, entity.ErrUserDisabled) || errors.Is(err, entity.ErrUserDeleted) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	response, err := mapper.ToTokenResponse(user.Result)
+	ac.completeLogin(w, r, user.Result, entity.LOGIN_METHOD_PASSWORD)
+}
+
+// LoginMfaV1 completes a login that answered with an MFA challenge.
+func (ac *AuthenticateController) LoginMfaV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewLoginMfaRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ac.mfaService.VerifyMfaChallenge(req.ToVerifyMfaChallengeCommand(request.NewClientInfo(r, ac.trustedProxies)))
+	var locked *entity.LoginLockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	clientInfo := request.NewClientInfo(r, ac.trustedProxies)
+	token, err := ac.tokenService.IssueToken(&command.IssueTokenCommand{
+		User:      user.Result,
+		Device:    clientInfo.Device,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+		Method:    user.Method,
+	})
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
+	response := mapper.ToTokenResponse(token.Result)
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
@@ -93,19 +174,33 @@ func (ac *AuthenticateController) RegisterV1(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	registerCommand := req.ToRegisterCommand()
+	clientInfo := request.NewClientInfo(r, ac.trustedProxies)
+	registerCommand := req.ToRegisterCommand(clientInfo)
 	user, err := ac.service.Register(registerCommand)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	response, err := mapper.ToTokenResponse(user.Result)
+	if user.VerificationRequired {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(mapper.ToUserResponse(user.Result))
+		return
+	}
+
+	token, err := ac.tokenService.IssueToken(&command.IssueTokenCommand{
+		User:      user.Result,
+		Device:    clientInfo.Device,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	})
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
+	response := mapper.ToTokenResponse(token.Result)
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
@@ -113,7 +208,7 @@ func (ac *AuthenticateController) RegisterV1(w http.ResponseWriter, r *http.Requ
 func (ac *AuthenticateController) UpdateProfileV1(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 
-	claims := r.Context().Value(util.AccessTokenClaims{}).(util.AccessTokenClaims)
+	claims := util.MustAccessTokenClaims(r.Context())
 
 	req, err := request.NewUpdateProfileRequest(r)
 	if err != nil {
@@ -121,14 +216,19 @@ func (ac *AuthenticateController) UpdateProfileV1(w http.ResponseWriter, r *http
 		return
 	}
 
-	command := req.ToUpdateProfileCommand(claims.Id)
+	command := req.ToUpdateProfileCommand(claims.Id, claims.SessionId, request.NewClientInfo(r, ac.trustedProxies))
 	result, err := ac.service.UpdateProfile(command)
+	if errors.Is(err, entity.ErrEmailTaken) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	response := mapper.ToUserResponse(result.Result)
+	response.PendingEmail = result.PendingEmail
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
@@ -141,7 +241,7 @@ func (ac *AuthenticateController) ResetPasswordV1(w http.ResponseWriter, r *http
 		return
 	}
 
-	command := req.ToResetPasswordCommand()
+	command := req.ToResetPasswordCommand(request.NewClientInfo(r, ac.trustedProxies))
 	_, err = ac.service.ResetPassword(command)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -158,7 +258,7 @@ func (ac *AuthenticateController) ResetPasswordWithTokenV1(w http.ResponseWriter
 		return
 	}
 
-	command := req.ToResetPasswordWithTokenCommand()
+	command := req.ToResetPasswordWithTokenCommand(request.NewClientInfo(r, ac.trustedProxies))
 	_, err = ac.service.ResetPasswordWithToken(command)
 	if err != nil {
 		slog.Error(fmt.Sprintf("error on reset password with token: %v", err))
@@ -172,33 +272,43 @@ func (ac *AuthenticateController) ResetPasswordWithTokenV1(w http.ResponseWriter
 func (ac *AuthenticateController) RefreshTokenV1(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 
-	claims := r.Context().Value(util.AccessTokenClaims{}).(util.AccessTokenClaims)
-
 	req, err := request.NewRefreshTokenRequest(r)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	if refreshClaims, err := util.ValidateRefreshToken(req.RefreshToken); err != nil && refreshClaims.Id != claims.Id {
-		w.WriteHeader(http.StatusBadRequest)
+	refreshTokenCommand := req.ToRefreshTokenCommand()
+	result, err := ac.tokenService.RefreshToken(refreshTokenCommand)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on refresh token: %v", err))
+		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	result, err := ac.service.Profile(&command.ProfileCommand{Email: claims.Email})
+	response := mapper.ToTokenResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (ac *AuthenticateController) LogoutV1(w http.ResponseWriter, r *http.Request) {
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewLogoutRequest(r)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	response, err := mapper.ToTokenResponse(result.Result)
-	if err != nil {
+	logoutCommand := req.ToLogoutCommand(claims)
+	if err := ac.tokenService.Logout(logoutCommand); err != nil {
+		slog.Error(fmt.Sprintf("error on logout: %v", err))
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
 }
 
 func (ac *AuthenticateController) DeleteProfileV1(w http.ResponseWriter, r *http.Request) {
@@ -208,7 +318,7 @@ func (ac *AuthenticateController) DeleteProfileV1(w http.ResponseWriter, r *http
 		return
 	}
 
-	deleteProfileCommand := req.ToDeleteProfileCommand()
+	deleteProfileCommand := req.ToDeleteProfileCommand(request.NewClientInfo(r, ac.trustedProxies))
 	if err := ac.service.DeleteProfile(deleteProfileCommand); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -216,3 +326,181 @@ func (ac *AuthenticateController) DeleteProfileV1(w http.ResponseWriter, r *http
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// RestoreAccountV1 undoes a scheduled deletion from the emailed link. Deleting
+// signed the user out, so they sign in again afterwards.
+func (ac *AuthenticateController) RestoreAccountV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewRestoreAccountRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ac.service.RestoreAccount(req.ToRestoreAccountCommand(request.NewClientInfo(r, ac.trustedProxies)))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (ac *AuthenticateController) VerifyEmailV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewVerifyEmailRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ac.service.VerifyEmail(req.ToVerifyEmailCommand(request.NewClientInfo(r, ac.trustedProxies)))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (ac *AuthenticateController) ConfirmEmailChangeV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewConfirmEmailChangeRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ac.service.ConfirmEmailChange(req.ToConfirmEmailChangeCommand(request.NewClientInfo(r, ac.trustedProxies)))
+	if errors.Is(err, entity.ErrEmailTaken) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (ac *AuthenticateController) RevertEmailChangeV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewRevertEmailChangeRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ac.service.RevertEmailChange(req.ToRevertEmailChangeCommand(request.NewClientInfo(r, ac.trustedProxies)))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ResendVerifyEmailV1 answers the same whether or not an email was sent, so it
+// cannot be used to find out who is registered.
+func (ac *AuthenticateController) ResendVerifyEmailV1(w http.ResponseWriter, r *http.Request) {
+	req, err := request.NewResendVerifyEmailRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := ac.service.ResendVerifyEmail(req.ToResendVerifyEmailCommand(request.NewClientInfo(r, ac.trustedProxies))); err != nil {
+		slog.Info(fmt.Sprintf("verification email not sent: %v", err))
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// RequestMagicLinkV1 answers the same whether or not the email belongs to an
+// account, so it cannot be used to find out who is registered.
+func (ac *AuthenticateController) RequestMagicLinkV1(w http.ResponseWriter, r *http.Request) {
+	req, err := request.NewRequestMagicLinkRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := ac.service.RequestMagicLink(req.ToRequestMagicLinkCommand(request.NewClientInfo(r, ac.trustedProxies))); err != nil {
+		slog.Info(fmt.Sprintf("magic link not sent: %v", err))
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// LoginWithMagicLinkV1 still asks for the second factor of users who have one.
+func (ac *AuthenticateController) LoginWithMagicLinkV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewLoginWithMagicLinkRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ac.service.LoginWithMagicLink(req.ToLoginWithMagicLinkCommand(request.NewClientInfo(r, ac.trustedProxies)))
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	ac.completeLogin(w, r, user.Result, entity.LOGIN_METHOD_MAGIC_LINK)
+}
+
+// completeLogin answers with an MFA challenge when the user has a second
+// factor, and with the token pair otherwise.
+func (ac *AuthenticateController) completeLogin(w http.ResponseWriter, r *http.Request, user *common.UserResult, method string) {
+	challenge, err := ac.mfaService.CreateMfaChallenge(&command.CreateMfaChallengeCommand{
+		User:   user,
+		Method: method,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on create mfa challenge: %v", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if challenge.Result.Required {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(mapper.ToMfaChallengeResponse(challenge.Result))
+		return
+	}
+
+	clientInfo := request.NewClientInfo(r, ac.trustedProxies)
+	token, err := ac.tokenService.IssueToken(&command.IssueTokenCommand{
+		User:      user,
+		Device:    clientInfo.Device,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+		Method:    method,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToTokenResponse(token.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var dataExportRateLimit = middleware.RateLimitPolicy{Name: "data-export", Limit: 3, Window: 24 * time.Hour, Key: middleware.ByUserId}
+
+type DataExportController struct {
+	service interfaces.DataExportService
+}
+
+func NewDataExportController(r *mux.Router, service interfaces.DataExportService, authenticator *middleware.Authenticator, rateLimit *middleware.RateLimit) *DataExportController {
+	controller := DataExportController{
+		service: service,
+	}
+
+	r.Handle("/api/v1/profile/data-export", authenticator.UnverifiedSessionHandler(rateLimit.Handler(http.HandlerFunc(controller.RequestDataExportV1), dataExportRateLimit))).Methods(http.MethodPost)
+	r.Handle("/api/v1/profile/data-export", authenticator.UnverifiedSessionHandler(http.HandlerFunc(controller.GetDataExportV1))).Methods(http.MethodGet)
+	r.Handle("/api/v1/profile/data-export/download", rateLimit.Handler(http.HandlerFunc(controller.DownloadDataExportV1), emailTokenRateLimit)).Methods(http.MethodGet)
+
+	return &controller
+}
+
+// RequestDataExportV1 answers 202 right away. The archive is built in the
+// background and its download link is emailed once it is ready.
+func (dc *DataExportController) RequestDataExportV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := dc.service.RequestDataExport(&command.RequestDataExportCommand{
+		UserId: claims.Id,
+	})
+	if errors.Is(err, entity.ErrDataExportInProgress) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := mapper.ToDataExportResponse(result.Result)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (dc *DataExportController) GetDataExportV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := dc.service.GetDataExport(&command.GetDataExportCommand{
+		UserId: claims.Id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	response := mapper.ToDataExportResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DownloadDataExportV1 is what the emailed link points to, so the token comes
+// in the query string and no session is needed.
+func (dc *DataExportController) DownloadDataExportV1(w http.ResponseWriter, r *http.Request) {
+	result, err := dc.service.DownloadDataExport(&command.DownloadDataExportCommand{
+		Token: r.URL.Query().Get("token"),
+	})
+	if errors.Is(err, entity.ErrDataExportInvalid) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer result.Result.Content.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.Result.Name))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, result.Result.Content)
+}
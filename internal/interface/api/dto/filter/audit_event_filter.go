@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"fmt"
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// RequestToAuditEventCriteria reads the audit event filters from the query
+// string. Dates are RFC 3339 and events are always listed newest first.
+func RequestToAuditEventCriteria(r http.Request) (*criteria.AuditEventCriteria, error) {
+	query := r.URL.Query()
+
+	auditEventCriteria := criteria.AuditEventCriteria{}
+	if query.Has("user_id") {
+		userId, err := uuid.Parse(query.Get("user_id"))
+		if err != nil {
+			return nil, err
+		}
+		auditEventCriteria = *auditEventCriteria.WithUserId(&userId)
+	}
+	if query.Has("action") {
+		action := query.Get("action")
+		auditEventCriteria = *auditEventCriteria.WithAction(&action)
+	}
+	if query.Has("outcome") {
+		outcome := query.Get("outcome")
+		if outcome != entity.AUDIT_OUTCOME_SUCCESS && outcome != entity.AUDIT_OUTCOME_FAILURE {
+			return nil, fmt.Errorf("invalid outcome %q", outcome)
+		}
+		auditEventCriteria = *auditEventCriteria.WithOutcome(&outcome)
+	}
+	if query.Has("ip_address") {
+		ipAddress := query.Get("ip_address")
+		auditEventCriteria = *auditEventCriteria.WithIpAddress(&ipAddress)
+	}
+
+	createdAfter, err := queryTime(query, "created_after")
+	if err != nil {
+		return nil, err
+	}
+	createdBefore, err := queryTime(query, "created_before")
+	if err != nil {
+		return nil, err
+	}
+	auditEventCriteria = *auditEventCriteria.WithCreated(createdAfter, createdBefore)
+
+	page, err := queryInt(query, "page")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := queryInt(query, "limit")
+	if err != nil {
+		return nil, err
+	}
+	auditEventCriteria = *auditEventCriteria.WithPage(page, limit)
+
+	return &auditEventCriteria, nil
+}
@@ -0,0 +1,48 @@
+package filter_test
+
+import (
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/filter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestToAuditEventCriteria(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		userId := uuid.New()
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-events?user_id="+userId.String()+"&action=login&outcome=failure"+
+			"&ip_address=10.0.0.1&created_after=2024-01-01T00:00:00Z&page=3&limit=10", nil)
+
+		auditEventCriteria, err := filter.RequestToAuditEventCriteria(*r)
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, *auditEventCriteria.UserId)
+		assert.Equal(t, entity.AUDIT_ACTION_LOGIN, *auditEventCriteria.Action)
+		assert.Equal(t, entity.AUDIT_OUTCOME_FAILURE, *auditEventCriteria.Outcome)
+		assert.Equal(t, "10.0.0.1", *auditEventCriteria.IpAddress)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), *auditEventCriteria.CreatedAfter)
+		assert.Nil(t, auditEventCriteria.CreatedBefore)
+		assert.Equal(t, 3, auditEventCriteria.Page)
+		assert.Equal(t, 10, auditEventCriteria.Limit)
+	})
+
+	for _, query := range []string{
+		"user_id=not-a-uuid",
+		"outcome=maybe",
+		"created_before=yesterday",
+		"page=-1",
+	} {
+		t.Run("failure: "+query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-events?"+query, nil)
+
+			_, err := filter.RequestToAuditEventCriteria(*r)
+
+			assert.Error(t, err)
+		})
+	}
+}
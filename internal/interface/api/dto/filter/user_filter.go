@@ -1,12 +1,20 @@
 package filter
 
 import (
+	"fmt"
 	"github/imfropz/go-ddd/internal/domain/criteria"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// RequestToUserCriteria reads the user filters from the query string. Dates
+// are RFC 3339, sort is created_at, name or email with a leading "-" for
+// descending order, and users are listed newest first by default.
 func RequestToUserCriteria(r http.Request) (*criteria.UserCriteria, error) {
 	query := r.URL.Query()
 
@@ -22,6 +30,96 @@ func RequestToUserCriteria(r http.Request) (*criteria.UserCriteria, error) {
 		name := query.Get("name")
 		userCriteria = *userCriteria.WithName(&name)
 	}
+	if query.Has("email") {
+		email := query.Get("email")
+		userCriteria = *userCriteria.WithEmail(&email)
+	}
+	if query.Has("status") {
+		status := query.Get("status")
+		if status != criteria.USER_STATUS_ACTIVE && status != criteria.USER_STATUS_DISABLED && status != criteria.USER_STATUS_DELETED {
+			return nil, fmt.Errorf("invalid status %q", status)
+		}
+		userCriteria = *userCriteria.WithStatus(&status)
+	}
+	if query.Has("email_verified") {
+		emailVerified, err := strconv.ParseBool(query.Get("email_verified"))
+		if err != nil {
+			return nil, err
+		}
+		userCriteria = *userCriteria.WithEmailVerified(&emailVerified)
+	}
+
+	createdAfter, err := queryTime(query, "created_after")
+	if err != nil {
+		return nil, err
+	}
+	createdBefore, err := queryTime(query, "created_before")
+	if err != nil {
+		return nil, err
+	}
+	userCriteria = *userCriteria.WithCreated(createdAfter, createdBefore)
+
+	updatedAfter, err := queryTime(query, "updated_after")
+	if err != nil {
+		return nil, err
+	}
+	updatedBefore, err := queryTime(query, "updated_before")
+	if err != nil {
+		return nil, err
+	}
+	userCriteria = *userCriteria.WithUpdated(updatedAfter, updatedBefore)
+
+	sort := "-" + criteria.USER_SORT_CREATED_AT
+	if query.Has("sort") {
+		sort = query.Get("sort")
+	}
+	column := strings.TrimPrefix(sort, "-")
+	if column != criteria.USER_SORT_CREATED_AT && column != criteria.USER_SORT_NAME && column != criteria.USER_SORT_EMAIL {
+		return nil, fmt.Errorf("invalid sort %q", sort)
+	}
+	userCriteria = *userCriteria.WithSort(column, strings.HasPrefix(sort, "-"))
+
+	page, err := queryInt(query, "page")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := queryInt(query, "limit")
+	if err != nil {
+		return nil, err
+	}
+	userCriteria = *userCriteria.WithPage(page, limit)
+
+	if query.Has("cursor") {
+		cursor, err := criteria.DecodeUserCursor(query.Get("cursor"))
+		if err != nil {
+			return nil, err
+		}
+		userCriteria = *userCriteria.WithCursor(cursor)
+	}
 
 	return &userCriteria, nil
 }
+
+func queryTime(query url.Values, name string) (*time.Time, error) {
+	if !query.Has(name) {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, query.Get(name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return &t, nil
+}
+
+func queryInt(query url.Values, name string) (int, error) {
+	if !query.Has(name) {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(query.Get(name))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, query.Get(name))
+	}
+	return n, nil
+}
@@ -0,0 +1,65 @@
+package filter_test
+
+import (
+	"github/imfropz/go-ddd/internal/domain/criteria"
+	"github/imfropz/go-ddd/internal/interface/api/dto/filter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestToUserCriteria(t *testing.T) {
+	t.Run("success: defaults", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users", nil)
+
+		userCriteria, err := filter.RequestToUserCriteria(*r)
+
+		assert.NoError(t, err)
+		assert.Equal(t, criteria.USER_SORT_CREATED_AT, userCriteria.Sort)
+		assert.True(t, userCriteria.Desc)
+		assert.Nil(t, userCriteria.Cursor)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		cursor := criteria.UserCursor{Value: "jane@example.com", Id: uuid.New()}
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users?name=jan&email=EXAMPLE&status=disabled&email_verified=true"+
+			"&created_after=2024-01-01T00:00:00Z&updated_before=2024-02-01T00:00:00Z&sort=email&page=2&limit=50&cursor="+cursor.Encode(), nil)
+
+		userCriteria, err := filter.RequestToUserCriteria(*r)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jan", *userCriteria.Name)
+		assert.Equal(t, "EXAMPLE", *userCriteria.Email)
+		assert.Equal(t, criteria.USER_STATUS_DISABLED, *userCriteria.Status)
+		assert.True(t, *userCriteria.EmailVerified)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), *userCriteria.CreatedAfter)
+		assert.Nil(t, userCriteria.CreatedBefore)
+		assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), *userCriteria.UpdatedBefore)
+		assert.Equal(t, criteria.USER_SORT_EMAIL, userCriteria.Sort)
+		assert.False(t, userCriteria.Desc)
+		assert.Equal(t, 2, userCriteria.Page)
+		assert.Equal(t, 50, userCriteria.Limit)
+		assert.Equal(t, cursor, *userCriteria.Cursor)
+	})
+
+	for _, query := range []string{
+		"status=banned",
+		"sort=password",
+		"created_after=yesterday",
+		"limit=-1",
+		"email_verified=maybe",
+		"cursor=not-a-cursor",
+	} {
+		t.Run("failure: "+query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users?"+query, nil)
+
+			_, err := filter.RequestToUserCriteria(*r)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+)
+
+func ToApiKeyResponse(apiKey *common.ApiKeyResult) *response.ApiKeyResponse {
+	scopes := apiKey.Scopes
+	if scopes == nil {
+		scopes = make([]string, 0)
+	}
+
+	return &response.ApiKeyResponse{
+		Id:         apiKey.Id.String(),
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func ToApiKeyListResponse(apiKeys []*common.ApiKeyResult) *response.ListApiKeysResponse {
+	res := response.ListApiKeysResponse{
+		ApiKeys: make([]*response.ApiKeyResponse, 0),
+	}
+	for _, apiKey := range apiKeys {
+		res.ApiKeys = append(res.ApiKeys, ToApiKeyResponse(apiKey))
+	}
+	return &res
+}
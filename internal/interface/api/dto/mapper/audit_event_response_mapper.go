@@ -0,0 +1,37 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+)
+
+func ToAuditEventResponse(event *common.AuditEventResult) *response.AuditEventResponse {
+	res := response.AuditEventResponse{
+		Id:        event.Id.String(),
+		CreatedAt: event.CreatedAt,
+		Email:     event.Email,
+		Action:    event.Action,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		IpAddress: event.IpAddress,
+		UserAgent: event.UserAgent,
+	}
+	if event.UserId != nil {
+		res.UserId = event.UserId.String()
+	}
+	if event.ActorId != nil {
+		res.ActorId = event.ActorId.String()
+	}
+	return &res
+}
+
+func ToAuditEventListResponse(events *common.AuditEventListResult) *response.ListAuditEventsResponse {
+	res := response.ListAuditEventsResponse{
+		Events: make([]*response.AuditEventResponse, 0),
+		Total:  events.Total,
+	}
+	for _, event := range events.Events {
+		res.Events = append(res.Events, ToAuditEventResponse(event))
+	}
+	return &res
+}
@@ -0,0 +1,16 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+)
+
+func ToDataExportResponse(export *common.DataExportResult) *response.DataExportResponse {
+	return &response.DataExportResponse{
+		Id:          export.Id.String(),
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
@@ -0,0 +1,29 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+)
+
+func ToJwkResponse(key util.JSONWebKey) *response.JwkResponse {
+	return &response.JwkResponse{
+		Kty: key.Kty,
+		Kid: key.Kid,
+		Use: key.Use,
+		Alg: key.Alg,
+		Crv: key.Crv,
+		X:   key.X,
+		N:   key.N,
+		E:   key.E,
+	}
+}
+
+func ToJwksResponse(keys []util.JSONWebKey) *response.JwksResponse {
+	res := response.JwksResponse{
+		Keys: make([]*response.JwkResponse, 0),
+	}
+	for _, key := range keys {
+		res.Keys = append(res.Keys, ToJwkResponse(key))
+	}
+	return &res
+}
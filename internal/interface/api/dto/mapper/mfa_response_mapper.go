@@ -0,0 +1,35 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+	"time"
+)
+
+func ToEnrollTotpResponse(enrollment *common.TotpEnrollmentResult) *response.EnrollTotpResponse {
+	return &response.EnrollTotpResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.Uri,
+	}
+}
+
+func ToMfaStatusResponse(status *common.MfaStatusResult) *response.MfaStatusResponse {
+	return &response.MfaStatusResponse{
+		TotpEnabled:            status.TotpEnabled,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	}
+}
+
+func ToRecoveryCodesResponse(codes *common.RecoveryCodesResult) *response.RecoveryCodesResponse {
+	return &response.RecoveryCodesResponse{
+		RecoveryCodes: codes.Codes,
+	}
+}
+
+func ToMfaChallengeResponse(challenge *common.MfaChallengeResult) *response.MfaChallengeResponse {
+	return &response.MfaChallengeResponse{
+		MfaRequired: challenge.Required,
+		MfaToken:    challenge.MfaToken,
+		ExpiresIn:   int(time.Until(challenge.ExpiresAt).Seconds()),
+	}
+}
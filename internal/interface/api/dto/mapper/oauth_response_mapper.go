@@ -0,0 +1,81 @@
+package mapper
+
+import (
+	"errors"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+
+	"github.com/google/uuid"
+)
+
+func ToOAuthClientResponse(client *common.OAuthClientResult) *response.OAuthClientResponse {
+	return &response.OAuthClientResponse{
+		ClientId:     client.ClientId,
+		Name:         client.Name,
+		RedirectUris: client.RedirectUris,
+		Scopes:       client.Scopes,
+		Public:       client.Public,
+		CreatedAt:    client.CreatedAt,
+		UpdatedAt:    client.UpdatedAt,
+	}
+}
+
+func ToOAuthClientListResponse(clients []*common.OAuthClientResult) *response.ListOAuthClientsResponse {
+	res := response.ListOAuthClientsResponse{
+		Clients: make([]*response.OAuthClientResponse, 0),
+	}
+	for _, client := range clients {
+		res.Clients = append(res.Clients, ToOAuthClientResponse(client))
+	}
+	return &res
+}
+
+func ToOAuthTokenResponse(token *common.TokenResult) *response.OAuthTokenResponse {
+	return &response.OAuthTokenResponse{
+		AccessToken:  token.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    token.ExpiresIn,
+		RefreshToken: token.RefreshToken,
+		Scope:        token.Scope,
+		IdToken:      token.IdToken,
+	}
+}
+
+func ToIntrospectionResponse(introspection *common.IntrospectionResult) *response.IntrospectionResponse {
+	if !introspection.Active {
+		return &response.IntrospectionResponse{Active: false}
+	}
+
+	res := response.IntrospectionResponse{
+		Active:      true,
+		TokenType:   introspection.TokenType,
+		Subject:     introspection.Subject.String(),
+		SubjectType: introspection.SubjectType,
+		Username:    introspection.Username,
+		TokenId:     introspection.TokenId,
+		ClientId:    introspection.ClientId,
+		Scope:       introspection.Scope,
+		ExpiresAt:   introspection.ExpiresAt.Unix(),
+	}
+	if introspection.SessionId != uuid.Nil {
+		res.SessionId = introspection.SessionId.String()
+	}
+	return &res
+}
+
+// ToOAuthErrorResponse hides anything that is not an OAuth error behind a
+// generic server_error.
+func ToOAuthErrorResponse(err error) *response.OAuthErrorResponse {
+	var oauthError *entity.OAuthError
+	if !errors.As(err, &oauthError) {
+		return &response.OAuthErrorResponse{
+			Error: entity.OAUTH_SERVER_ERROR,
+		}
+	}
+
+	return &response.OAuthErrorResponse{
+		Error:            oauthError.Code,
+		ErrorDescription: oauthError.Description,
+	}
+}
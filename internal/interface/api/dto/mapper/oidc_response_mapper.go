@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+	"strings"
+)
+
+func ToOpenIdConfigurationResponse(issuer string, signingAlgs []string) *response.OpenIdConfigurationResponse {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	return &response.OpenIdConfigurationResponse{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		UserInfoEndpoint:                  issuer + "/userinfo",
+		JwksUri:                           issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             issuer + "/oauth/introspect",
+		RevocationEndpoint:                issuer + "/oauth/revoke",
+		ScopesSupported:                   entity.SUPPORTED_SCOPES,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{entity.GRANT_TYPE_AUTHORIZATION_CODE, entity.GRANT_TYPE_REFRESH_TOKEN, entity.GRANT_TYPE_CLIENT_CREDENTIALS},
+		SubjectTypesSupported:             []string{"public"},
+		IdTokenSigningAlgValuesSupported:  signingAlgs,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "name", "email", "email_verified"},
+	}
+}
+
+func ToUserInfoResponse(userInfo *common.UserInfoResult) *response.UserInfoResponse {
+	res := response.UserInfoResponse{
+		Subject: userInfo.Subject.String(),
+		Name:    userInfo.Name,
+		Email:   userInfo.Email,
+	}
+	if userInfo.Email != "" {
+		res.EmailVerified = &userInfo.EmailVerified
+	}
+	return &res
+}
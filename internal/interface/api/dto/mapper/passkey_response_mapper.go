@@ -0,0 +1,84 @@
+package mapper
+
+import (
+	"encoding/base64"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+)
+
+const (
+	PUBLIC_KEY_CREDENTIAL_TYPE = "public-key"
+	USER_VERIFICATION_REQUIRED = "required"
+)
+
+func ToPasskeyResponse(passkey *common.PasskeyResult) *response.PasskeyResponse {
+	transports := passkey.Transports
+	if transports == nil {
+		transports = make([]string, 0)
+	}
+
+	return &response.PasskeyResponse{
+		Id:         passkey.Id.String(),
+		Name:       passkey.Name,
+		Transports: transports,
+		CreatedAt:  passkey.CreatedAt,
+		LastUsedAt: passkey.LastUsedAt,
+	}
+}
+
+func ToPasskeyListResponse(passkeys []*common.PasskeyResult) *response.ListPasskeysResponse {
+	res := response.ListPasskeysResponse{
+		Passkeys: make([]*response.PasskeyResponse, 0),
+	}
+	for _, passkey := range passkeys {
+		res.Passkeys = append(res.Passkeys, ToPasskeyResponse(passkey))
+	}
+	return &res
+}
+
+func ToPasskeyCreationOptionsResponse(options *common.PasskeyRegistrationOptionsResult) *response.PasskeyCreationOptionsResponse {
+	res := response.PasskeyCreationOptionsResponse{
+		Challenge: options.Challenge,
+		Rp: response.PasskeyRelyingPartyResponse{
+			Id:   options.RelyingPartyId,
+			Name: options.RelyingPartyName,
+		},
+		User: response.PasskeyUserResponse{
+			Id:          base64.RawURLEncoding.EncodeToString(options.UserHandle),
+			Name:        options.UserName,
+			DisplayName: options.UserDisplayName,
+		},
+		PubKeyCredParams:   make([]response.PasskeyCredentialParameterResponse, 0),
+		Timeout:            options.Timeout.Milliseconds(),
+		ExcludeCredentials: make([]response.PasskeyCredentialDescriptorResponse, 0),
+		AuthenticatorSelection: response.PasskeyAuthenticatorSelectionResponse{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   USER_VERIFICATION_REQUIRED,
+		},
+		Attestation: "none",
+	}
+	for _, alg := range options.Algorithms {
+		res.PubKeyCredParams = append(res.PubKeyCredParams, response.PasskeyCredentialParameterResponse{
+			Type: PUBLIC_KEY_CREDENTIAL_TYPE,
+			Alg:  alg,
+		})
+	}
+	for _, passkey := range options.ExcludeCredentials {
+		res.ExcludeCredentials = append(res.ExcludeCredentials, response.PasskeyCredentialDescriptorResponse{
+			Type:       PUBLIC_KEY_CREDENTIAL_TYPE,
+			Id:         base64.RawURLEncoding.EncodeToString(passkey.CredentialId),
+			Transports: passkey.Transports,
+		})
+	}
+	return &res
+}
+
+func ToPasskeyRequestOptionsResponse(options *common.PasskeyLoginOptionsResult) *response.PasskeyRequestOptionsResponse {
+	return &response.PasskeyRequestOptionsResponse{
+		Challenge:        options.Challenge,
+		RpId:             options.RelyingPartyId,
+		Timeout:          options.Timeout.Milliseconds(),
+		UserVerification: USER_VERIFICATION_REQUIRED,
+	}
+}
@@ -0,0 +1,33 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+)
+
+func ToRoleResponse(role *common.RoleResult) *response.RoleResponse {
+	permissions := role.Permissions
+	if permissions == nil {
+		permissions = make([]string, 0)
+	}
+
+	return &response.RoleResponse{
+		Id:          role.Id.String(),
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		RequireMfa:  role.RequireMfa,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func ToRoleListResponse(roles []*common.RoleResult) *response.ListRolesResponse {
+	res := response.ListRolesResponse{
+		Roles: make([]*response.RoleResponse, 0),
+	}
+	for _, role := range roles {
+		res.Roles = append(res.Roles, ToRoleResponse(role))
+	}
+	return &res
+}
@@ -0,0 +1,30 @@
+package mapper
+
+import (
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/interface/api/dto/response"
+
+	"github.com/google/uuid"
+)
+
+func ToSessionResponse(session *common.SessionResult, currentSessionId uuid.UUID) *response.SessionResponse {
+	return &response.SessionResponse{
+		Id:         session.Id.String(),
+		Device:     session.Device,
+		IpAddress:  session.IpAddress,
+		UserAgent:  session.UserAgent,
+		Current:    session.Id == currentSessionId,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+	}
+}
+
+func ToSessionListResponse(sessions []*common.SessionResult, currentSessionId uuid.UUID) *response.ListSessionsResponse {
+	res := response.ListSessionsResponse{
+		Sessions: make([]*response.SessionResponse, 0),
+	}
+	for _, session := range sessions {
+		res.Sessions = append(res.Sessions, ToSessionResponse(session, currentSessionId))
+	}
+	return &res
+}
@@ -7,19 +7,24 @@ import (
 
 func ToUserResponse(user *common.UserResult) *response.UserResponse {
 	return &response.UserResponse{
-		Id:        user.Id.String(),
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		Id:            user.Id.String(),
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		DisabledAt:    user.DisabledAt,
+		DeletedAt:     user.DeletedAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}
 }
 
-func ToUserListResponse(users []*common.UserResult) *response.ListUsersResponse {
+func ToUserListResponse(users *common.UserListResult) *response.ListUsersResponse {
 	res := response.ListUsersResponse{
-		Users: make([]*response.UserResponse, 0),
+		Users:      make([]*response.UserResponse, 0),
+		Total:      users.Total,
+		NextCursor: users.NextCursor,
 	}
-	for _, user := range users {
+	for _, user := range users.Users {
 		res.Users = append(res.Users, ToUserResponse(user))
 	}
 	return &res
@@ -0,0 +1,67 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateApiKeyRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func NewCreateApiKeyRequest(r *http.Request) (*CreateApiKeyRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req CreateApiKeyRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *CreateApiKeyRequest) ToCreateApiKeyCommand(userId uuid.UUID) *command.CreateApiKeyCommand {
+	return &command.CreateApiKeyCommand{
+		UserId:    userId,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+}
+
+type RevokeApiKeyRequest struct {
+	Id uuid.UUID `json:"id" validate:"required"`
+}
+
+func NewRevokeApiKeyRequest(r *http.Request) (*RevokeApiKeyRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req RevokeApiKeyRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *RevokeApiKeyRequest) ToRevokeApiKeyCommand(userId uuid.UUID) *command.RevokeApiKeyCommand {
+	return &command.RevokeApiKeyCommand{
+		UserId: userId,
+		Id:     req.Id,
+	}
+}
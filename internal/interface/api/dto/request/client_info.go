@@ -0,0 +1,95 @@
+package request
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type ClientInfo struct {
+	Device    string
+	IpAddress string
+	UserAgent string
+}
+
+func NewClientInfo(r *http.Request, trustedProxies []netip.Prefix) *ClientInfo {
+	return &ClientInfo{
+		Device:    r.Header.Get("X-Device-Name"),
+		IpAddress: ClientIp(r, trustedProxies),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// ParseTrustedProxies parses the addresses or CIDR ranges of the proxies whose
+// X-Forwarded-For header is honored. Single addresses are accepted as well.
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ClientIp returns the address of the peer unless it is a trusted proxy. Then
+// X-Forwarded-For is walked from the right and the first hop that is not a
+// trusted proxy is the client, as everything left of it can be forged.
+func ClientIp(r *http.Request, trustedProxies []netip.Prefix) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	if !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+
+	hops := make([]string, 0)
+	for _, forwardedFor := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(forwardedFor, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		if _, err := netip.ParseAddr(hops[i]); err != nil {
+			break
+		}
+		ip = hops[i]
+		if !isTrustedProxy(ip, trustedProxies) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
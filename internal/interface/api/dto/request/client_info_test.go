@@ -0,0 +1,60 @@
+package request_test
+
+import (
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIp(t *testing.T) {
+	newRequest := func(remoteAddr string, forwardedFor ...string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		for _, value := range forwardedFor {
+			r.Header.Add("X-Forwarded-For", value)
+		}
+		return r
+	}
+
+	t.Run("success: ignores forwarded for from untrusted peers", func(t *testing.T) {
+		ip := request.ClientIp(newRequest("203.0.113.7:1234", "198.51.100.1"), nil)
+
+		assert.Equal(t, "203.0.113.7", ip)
+	})
+
+	t.Run("success: right-most untrusted hop", func(t *testing.T) {
+		trustedProxies, err := request.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+		assert.NoError(t, err)
+
+		ip := request.ClientIp(newRequest("10.0.0.2:1234", "198.51.100.1, 203.0.113.7", "192.0.2.1, 10.0.0.3"), trustedProxies)
+
+		assert.Equal(t, "203.0.113.7", ip)
+	})
+
+	t.Run("success: only trusted hops", func(t *testing.T) {
+		trustedProxies, err := request.ParseTrustedProxies([]string{"10.0.0.0/8"})
+		assert.NoError(t, err)
+
+		ip := request.ClientIp(newRequest("10.0.0.2:1234", "10.0.0.4, 10.0.0.3"), trustedProxies)
+
+		assert.Equal(t, "10.0.0.4", ip)
+	})
+
+	t.Run("success: stops at a malformed hop", func(t *testing.T) {
+		trustedProxies, err := request.ParseTrustedProxies([]string{"10.0.0.0/8"})
+		assert.NoError(t, err)
+
+		ip := request.ClientIp(newRequest("10.0.0.2:1234", "203.0.113.7, not-an-ip"), trustedProxies)
+
+		assert.Equal(t, "10.0.0.2", ip)
+	})
+
+	t.Run("failure: invalid trusted proxy", func(t *testing.T) {
+		_, err := request.ParseTrustedProxies([]string{"proxy.internal"})
+
+		assert.Error(t, err)
+	})
+}
@@ -27,9 +27,38 @@ func NewDeleteProfileRequest(r *http.Request) (*DeleteProfileRequest, error) {
 	return &req, nil
 }
 
-func (req *DeleteProfileRequest) ToDeleteProfileCommand() *command.DeleteProfileCommand {
+func (req *DeleteProfileRequest) ToDeleteProfileCommand(clientInfo *ClientInfo) *command.DeleteProfileCommand {
 	return &command.DeleteProfileCommand{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:     req.Email,
+		Password:  req.Password,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
+
+type RestoreAccountRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func NewRestoreAccountRequest(r *http.Request) (*RestoreAccountRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req RestoreAccountRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *RestoreAccountRequest) ToRestoreAccountCommand(clientInfo *ClientInfo) *command.RestoreAccountCommand {
+	return &command.RestoreAccountCommand{
+		Token:     req.Token,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
 	}
 }
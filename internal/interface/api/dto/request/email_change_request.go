@@ -0,0 +1,62 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+)
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func NewConfirmEmailChangeRequest(r *http.Request) (*ConfirmEmailChangeRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req ConfirmEmailChangeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (request *ConfirmEmailChangeRequest) ToConfirmEmailChangeCommand(clientInfo *ClientInfo) *command.ConfirmEmailChangeCommand {
+	return &command.ConfirmEmailChangeCommand{
+		Token:     request.Token,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
+
+type RevertEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func NewRevertEmailChangeRequest(r *http.Request) (*RevertEmailChangeRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req RevertEmailChangeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (request *RevertEmailChangeRequest) ToRevertEmailChangeCommand(clientInfo *ClientInfo) *command.RevertEmailChangeCommand {
+	return &command.RevertEmailChangeCommand{
+		Token:     request.Token,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
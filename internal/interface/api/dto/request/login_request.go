@@ -27,9 +27,65 @@ func NewLoginRequest(r *http.Request) (*LoginRequest, error) {
 	return &req, nil
 }
 
-func (req *LoginRequest) ToLoginCommand() *command.LoginCommand {
+func (req *LoginRequest) ToLoginCommand(clientInfo *ClientInfo) *command.LoginCommand {
 	return &command.LoginCommand{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:     req.Email,
+		Password:  req.Password,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
+
+type RequestMagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func NewRequestMagicLinkRequest(r *http.Request) (*RequestMagicLinkRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req RequestMagicLinkRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *RequestMagicLinkRequest) ToRequestMagicLinkCommand(clientInfo *ClientInfo) *command.RequestMagicLinkCommand {
+	return &command.RequestMagicLinkCommand{
+		Email:     req.Email,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
+
+type LoginWithMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func NewLoginWithMagicLinkRequest(r *http.Request) (*LoginWithMagicLinkRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req LoginWithMagicLinkRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *LoginWithMagicLinkRequest) ToLoginWithMagicLinkCommand(clientInfo *ClientInfo) *command.LoginWithMagicLinkCommand {
+	return &command.LoginWithMagicLinkCommand{
+		Token:     req.Token,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
 	}
 }
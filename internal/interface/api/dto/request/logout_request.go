@@ -0,0 +1,39 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+)
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func NewLogoutRequest(r *http.Request) (*LogoutRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req LogoutRequest
+	if len(body) == 0 {
+		return &req, nil
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *LogoutRequest) ToLogoutCommand(claims util.AccessTokenClaims) *command.LogoutCommand {
+	return &command.LogoutCommand{
+		Claims:       &claims,
+		RefreshToken: req.RefreshToken,
+	}
+}
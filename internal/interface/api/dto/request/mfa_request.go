@@ -0,0 +1,121 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type ConfirmTotpRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func NewConfirmTotpRequest(r *http.Request) (*ConfirmTotpRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req ConfirmTotpRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *ConfirmTotpRequest) ToConfirmTotpCommand(userId uuid.UUID) *command.ConfirmTotpCommand {
+	return &command.ConfirmTotpCommand{
+		UserId: userId,
+		Code:   req.Code,
+	}
+}
+
+type DisableTotpRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+func NewDisableTotpRequest(r *http.Request) (*DisableTotpRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req DisableTotpRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *DisableTotpRequest) ToDisableTotpCommand(userId uuid.UUID, clientInfo *ClientInfo) *command.DisableTotpCommand {
+	return &command.DisableTotpCommand{
+		UserId:    userId,
+		Password:  req.Password,
+		IpAddress: clientInfo.IpAddress,
+	}
+}
+
+// LoginMfaRequest carries either a code or a recovery code.
+type LoginMfaRequest struct {
+	MfaToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+func NewLoginMfaRequest(r *http.Request) (*LoginMfaRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req LoginMfaRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *LoginMfaRequest) ToVerifyMfaChallengeCommand(clientInfo *ClientInfo) *command.VerifyMfaChallengeCommand {
+	return &command.VerifyMfaChallengeCommand{
+		MfaToken:     req.MfaToken,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+		IpAddress:    clientInfo.IpAddress,
+	}
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+func NewRegenerateRecoveryCodesRequest(r *http.Request) (*RegenerateRecoveryCodesRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req RegenerateRecoveryCodesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *RegenerateRecoveryCodesRequest) ToRegenerateRecoveryCodesCommand(userId uuid.UUID, clientInfo *ClientInfo) *command.RegenerateRecoveryCodesCommand {
+	return &command.RegenerateRecoveryCodesCommand{
+		UserId:    userId,
+		Password:  req.Password,
+		IpAddress: clientInfo.IpAddress,
+	}
+}
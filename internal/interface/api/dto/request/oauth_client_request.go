@@ -0,0 +1,42 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type RegisterOAuthClientRequest struct {
+	Name         string   `json:"name" validate:"required"`
+	RedirectUris []string `json:"redirect_uris" validate:"required"`
+	Scopes       []string `json:"scopes"`
+	Public       bool     `json:"public"`
+}
+
+func NewRegisterOAuthClientRequest(r *http.Request) (*RegisterOAuthClientRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req RegisterOAuthClientRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *RegisterOAuthClientRequest) ToRegisterOAuthClientCommand(ownerId uuid.UUID) *command.RegisterOAuthClientCommand {
+	return &command.RegisterOAuthClientCommand{
+		OwnerId:      ownerId,
+		Name:         req.Name,
+		RedirectUris: req.RedirectUris,
+		Scopes:       req.Scopes,
+		Public:       req.Public,
+	}
+}
@@ -0,0 +1,244 @@
+package request
+
+import (
+	"github/imfropz/go-ddd/internal/application/command"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientId            string
+	RedirectUri         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+func NewAuthorizeRequest(r *http.Request) *AuthorizeRequest {
+	query := r.URL.Query()
+
+	return &AuthorizeRequest{
+		ResponseType:        query.Get("response_type"),
+		ClientId:            query.Get("client_id"),
+		RedirectUri:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+		Nonce:               query.Get("nonce"),
+	}
+}
+
+func (req *AuthorizeRequest) ToAuthorizeCommand(userId uuid.UUID, sessionId uuid.UUID) *command.AuthorizeCommand {
+	return &command.AuthorizeCommand{
+		UserId:              userId,
+		SessionId:           sessionId,
+		ResponseType:        req.ResponseType,
+		ClientId:            req.ClientId,
+		RedirectUri:         req.RedirectUri,
+		Scope:               req.Scope,
+		State:               req.State,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		Nonce:               req.Nonce,
+	}
+}
+
+// AuthorizeLoginRequest is the sign in form of the authorization endpoint.
+// Authorize carries the query of the authorization request to return to.
+type AuthorizeLoginRequest struct {
+	Email     string
+	Password  string
+	Authorize string
+}
+
+func NewAuthorizeLoginRequest(r *http.Request) (*AuthorizeLoginRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return &AuthorizeLoginRequest{
+		Email:     r.PostForm.Get("email"),
+		Password:  r.PostForm.Get("password"),
+		Authorize: r.PostForm.Get("authorize"),
+	}, nil
+}
+
+func (req *AuthorizeLoginRequest) ToLoginCommand(clientInfo *ClientInfo) *command.LoginCommand {
+	return &command.LoginCommand{
+		Email:     req.Email,
+		Password:  req.Password,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
+
+// AuthorizeMfaRequest is the MFA form shown after signing in on the
+// authorization endpoint.
+type AuthorizeMfaRequest struct {
+	MfaToken     string
+	Code         string
+	RecoveryCode string
+	Authorize    string
+}
+
+func NewAuthorizeMfaRequest(r *http.Request) (*AuthorizeMfaRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return &AuthorizeMfaRequest{
+		MfaToken:     r.PostForm.Get("mfa_token"),
+		Code:         r.PostForm.Get("code"),
+		RecoveryCode: r.PostForm.Get("recovery_code"),
+		Authorize:    r.PostForm.Get("authorize"),
+	}, nil
+}
+
+func (req *AuthorizeMfaRequest) ToVerifyMfaChallengeCommand(clientInfo *ClientInfo) *command.VerifyMfaChallengeCommand {
+	return &command.VerifyMfaChallengeCommand{
+		MfaToken:     req.MfaToken,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+		IpAddress:    clientInfo.IpAddress,
+	}
+}
+
+// AuthorizeQuery returns the authorization request to go back to once signed
+// in, always on this server.
+func AuthorizeQuery(authorize string) string {
+	query, err := url.ParseQuery(authorize)
+	if err != nil {
+		return ""
+	}
+	return query.Encode()
+}
+
+type OAuthConsentRequest struct {
+	ConsentId string
+	Approved  bool
+}
+
+func NewOAuthConsentRequest(r *http.Request) (*OAuthConsentRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return &OAuthConsentRequest{
+		ConsentId: r.PostForm.Get("consent_id"),
+		Approved:  r.PostForm.Get("decision") == "approve",
+	}, nil
+}
+
+func (req *OAuthConsentRequest) ToOAuthConsentCommand(userId uuid.UUID, sessionId uuid.UUID) *command.OAuthConsentCommand {
+	return &command.OAuthConsentCommand{
+		UserId:    userId,
+		SessionId: sessionId,
+		ConsentId: req.ConsentId,
+		Approved:  req.Approved,
+	}
+}
+
+type OAuthTokenRequest struct {
+	GrantType    string
+	ClientId     string
+	ClientSecret string
+	Code         string
+	RedirectUri  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+// NewOAuthTokenRequest reads the form encoded token request.
+func NewOAuthTokenRequest(r *http.Request) (*OAuthTokenRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	req := OAuthTokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectUri:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+	}
+	req.ClientId, req.ClientSecret = clientCredentials(r)
+
+	return &req, nil
+}
+
+func (req *OAuthTokenRequest) ToOAuthTokenCommand(clientInfo *ClientInfo) *command.OAuthTokenCommand {
+	return &command.OAuthTokenCommand{
+		GrantType:    req.GrantType,
+		ClientId:     req.ClientId,
+		ClientSecret: req.ClientSecret,
+		Code:         req.Code,
+		RedirectUri:  req.RedirectUri,
+		CodeVerifier: req.CodeVerifier,
+		RefreshToken: req.RefreshToken,
+		Scope:        req.Scope,
+		IpAddress:    clientInfo.IpAddress,
+		UserAgent:    clientInfo.UserAgent,
+	}
+}
+
+// TokenHintRequest is the body shared by token introspection (RFC 7662) and
+// revocation (RFC 7009).
+type TokenHintRequest struct {
+	ClientId      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
+
+func NewTokenHintRequest(r *http.Request) (*TokenHintRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	req := TokenHintRequest{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+	}
+	req.ClientId, req.ClientSecret = clientCredentials(r)
+
+	return &req, nil
+}
+
+func (req *TokenHintRequest) ToIntrospectCommand() *command.IntrospectCommand {
+	return &command.IntrospectCommand{
+		ClientId:      req.ClientId,
+		ClientSecret:  req.ClientSecret,
+		Token:         req.Token,
+		TokenTypeHint: req.TokenTypeHint,
+	}
+}
+
+func (req *TokenHintRequest) ToRevokeCommand() *command.RevokeCommand {
+	return &command.RevokeCommand{
+		ClientId:      req.ClientId,
+		ClientSecret:  req.ClientSecret,
+		Token:         req.Token,
+		TokenTypeHint: req.TokenTypeHint,
+	}
+}
+
+// clientCredentials takes the client credentials from HTTP basic auth and falls
+// back to the form. The form must already be parsed.
+func clientCredentials(r *http.Request) (string, string) {
+	if clientId, clientSecret, ok := r.BasicAuth(); ok {
+		// RFC 6749 section 2.3.1 form-encodes the credentials before basic auth.
+		clientId, _ = url.QueryUnescape(clientId)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+		return clientId, clientSecret
+	}
+
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+}
@@ -0,0 +1,143 @@
+package request
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// The passkey requests follow the JSON form of a PublicKeyCredential, where
+// binary values are base64url encoded.
+
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON"`
+	AttestationObject string   `json:"attestationObject"`
+	Transports        []string `json:"transports"`
+}
+
+type PasskeyRegistrationRequest struct {
+	Name     string                     `json:"name"`
+	RawId    string                     `json:"rawId" validate:"required"`
+	Response PasskeyAttestationResponse `json:"response"`
+}
+
+func NewPasskeyRegistrationRequest(r *http.Request) (*PasskeyRegistrationRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req PasskeyRegistrationRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *PasskeyRegistrationRequest) ToFinishPasskeyRegistrationCommand(userId uuid.UUID) (*command.FinishPasskeyRegistrationCommand, error) {
+	decoded, err := decodeBase64Url(req.RawId, req.Response.ClientDataJSON, req.Response.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &command.FinishPasskeyRegistrationCommand{
+		UserId:            userId,
+		Name:              req.Name,
+		CredentialId:      decoded[0],
+		ClientDataJSON:    decoded[1],
+		AttestationObject: decoded[2],
+		Transports:        req.Response.Transports,
+	}, nil
+}
+
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle"`
+}
+
+type PasskeyLoginRequest struct {
+	RawId    string                   `json:"rawId" validate:"required"`
+	Response PasskeyAssertionResponse `json:"response"`
+}
+
+func NewPasskeyLoginRequest(r *http.Request) (*PasskeyLoginRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req PasskeyLoginRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *PasskeyLoginRequest) ToFinishPasskeyLoginCommand(clientInfo *ClientInfo) (*command.FinishPasskeyLoginCommand, error) {
+	decoded, err := decodeBase64Url(req.RawId, req.Response.ClientDataJSON, req.Response.AuthenticatorData, req.Response.Signature, req.Response.UserHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &command.FinishPasskeyLoginCommand{
+		CredentialId:      decoded[0],
+		ClientDataJSON:    decoded[1],
+		AuthenticatorData: decoded[2],
+		Signature:         decoded[3],
+		UserHandle:        decoded[4],
+		IpAddress:         clientInfo.IpAddress,
+		UserAgent:         clientInfo.UserAgent,
+	}, nil
+}
+
+type DeletePasskeyRequest struct {
+	Id uuid.UUID `json:"id" validate:"required"`
+}
+
+func NewDeletePasskeyRequest(r *http.Request) (*DeletePasskeyRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req DeletePasskeyRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *DeletePasskeyRequest) ToDeletePasskeyCommand(userId uuid.UUID) *command.DeletePasskeyCommand {
+	return &command.DeletePasskeyCommand{
+		UserId: userId,
+		Id:     req.Id,
+	}
+}
+
+// decodeBase64Url accepts base64url with or without padding, as browsers
+// differ in what they send.
+func decodeBase64Url(values ...string) ([][]byte, error) {
+	decoded := make([][]byte, len(values))
+	for i, value := range values {
+		bytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+		if err != nil {
+			return nil, err
+		}
+		decoded[i] = bytes
+	}
+
+	return decoded, nil
+}
@@ -2,6 +2,7 @@ package request
 
 import (
 	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
 	"io"
 	"net/http"
 )
@@ -24,3 +25,9 @@ func NewRefreshTokenRequest(r *http.Request) (*RefreshTokenRequest, error) {
 
 	return &req, nil
 }
+
+func (req *RefreshTokenRequest) ToRefreshTokenCommand() *command.RefreshTokenCommand {
+	return &command.RefreshTokenCommand{
+		RefreshToken: req.RefreshToken,
+	}
+}
@@ -28,10 +28,12 @@ func NewRegisterRequest(r *http.Request) (*RegisterRequest, error) {
 	return &req, nil
 }
 
-func (req *RegisterRequest) ToRegisterCommand() *command.RegisterCommand {
+func (req *RegisterRequest) ToRegisterCommand(clientInfo *ClientInfo) *command.RegisterCommand {
 	return &command.RegisterCommand{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
+		Name:      req.Name,
+		Email:     req.Email,
+		Password:  req.Password,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
 	}
 }
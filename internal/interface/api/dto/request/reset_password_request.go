@@ -26,9 +26,11 @@ func NewResetPasswordRequest(r *http.Request) (*ResetPasswordRequest, error) {
 	return &req, nil
 }
 
-func (request *ResetPasswordRequest) ToResetPasswordCommand() *command.ResetPasswordCommand {
+func (request *ResetPasswordRequest) ToResetPasswordCommand(clientInfo *ClientInfo) *command.ResetPasswordCommand {
 	return &command.ResetPasswordCommand{
-		Email: request.Email,
+		Email:     request.Email,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
 	}
 }
 
@@ -52,9 +54,11 @@ func NewResetPasswordWithTokenRequest(r *http.Request) (*ResetPasswordWithTokenR
 	return &req, nil
 }
 
-func (request *ResetPasswordWithTokenRequest) ToResetPasswordWithTokenCommand() *command.ResetPasswordWithTokenCommand {
+func (request *ResetPasswordWithTokenRequest) ToResetPasswordWithTokenCommand(clientInfo *ClientInfo) *command.ResetPasswordWithTokenCommand {
 	return &command.ResetPasswordWithTokenCommand{
 		Token:       request.Token,
 		NewPassword: request.NewPassword,
+		IpAddress:   clientInfo.IpAddress,
+		UserAgent:   clientInfo.UserAgent,
 	}
 }
@@ -0,0 +1,36 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type RevokeSessionRequest struct {
+	SessionId uuid.UUID `json:"session_id" validate:"required"`
+}
+
+func NewRevokeSessionRequest(r *http.Request) (*RevokeSessionRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req RevokeSessionRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *RevokeSessionRequest) ToRevokeSessionCommand(userId uuid.UUID) *command.RevokeSessionCommand {
+	return &command.RevokeSessionCommand{
+		UserId:    userId,
+		SessionId: req.SessionId,
+	}
+}
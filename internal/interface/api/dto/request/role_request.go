@@ -0,0 +1,36 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+func NewAssignRoleRequest(r *http.Request) (*AssignRoleRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req AssignRoleRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *AssignRoleRequest) ToAssignRoleCommand(userId uuid.UUID) *command.AssignRoleCommand {
+	return &command.AssignRoleCommand{
+		UserId: userId,
+		Role:   req.Role,
+	}
+}
@@ -31,12 +31,15 @@ func NewUpdateProfileRequest(r *http.Request) (*UpdateProfileRequest, error) {
 	return &req, nil
 }
 
-func (req *UpdateProfileRequest) ToUpdateProfileCommand(id uuid.UUID) *command.UpdateProfileCommand {
+func (req *UpdateProfileRequest) ToUpdateProfileCommand(id uuid.UUID, sessionId uuid.UUID, clientInfo *ClientInfo) *command.UpdateProfileCommand {
 	return &command.UpdateProfileCommand{
 		Id:              id,
+		SessionId:       sessionId,
 		Name:            req.Name,
 		Email:           req.Email,
 		CurrentPassword: req.CurrentPassword,
 		NewPassword:     req.NewPassword,
+		IpAddress:       clientInfo.IpAddress,
+		UserAgent:       clientInfo.UserAgent,
 	}
 }
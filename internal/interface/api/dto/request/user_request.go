@@ -0,0 +1,38 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type UpdateUserRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func NewUpdateUserRequest(r *http.Request) (*UpdateUserRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req UpdateUserRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (req *UpdateUserRequest) ToUpdateUserCommand(id uuid.UUID) *command.UpdateUserCommand {
+	return &command.UpdateUserCommand{
+		Id:    id,
+		Name:  req.Name,
+		Email: req.Email,
+	}
+}
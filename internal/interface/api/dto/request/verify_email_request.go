@@ -0,0 +1,62 @@
+package request
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/internal/application/command"
+	"io"
+	"net/http"
+)
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func NewVerifyEmailRequest(r *http.Request) (*VerifyEmailRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req VerifyEmailRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (request *VerifyEmailRequest) ToVerifyEmailCommand(clientInfo *ClientInfo) *command.VerifyEmailCommand {
+	return &command.VerifyEmailCommand{
+		Token:     request.Token,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
+
+type ResendVerifyEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func NewResendVerifyEmailRequest(r *http.Request) (*ResendVerifyEmailRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var req ResendVerifyEmailRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (request *ResendVerifyEmailRequest) ToResendVerifyEmailCommand(clientInfo *ClientInfo) *command.ResendVerifyEmailCommand {
+	return &command.ResendVerifyEmailCommand{
+		Email:     request.Email,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+	}
+}
@@ -0,0 +1,21 @@
+package response
+
+import "time"
+
+type AuditEventResponse struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserId    string    `json:"user_id,omitempty"`
+	ActorId   string    `json:"actor_id,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Action    string    `json:"action"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason,omitempty"`
+	IpAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+}
+
+type ListAuditEventsResponse struct {
+	Events []*AuditEventResponse `json:"events"`
+	Total  int64                 `json:"total"`
+}
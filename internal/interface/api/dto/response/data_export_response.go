@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type DataExportResponse struct {
+	Id          string     `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,45 @@
+package response
+
+import "time"
+
+type OAuthClientResponse struct {
+	ClientId     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret,omitempty"`
+	Name         string    `json:"name"`
+	RedirectUris []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	Public       bool      `json:"public"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type ListOAuthClientsResponse struct {
+	Clients []*OAuthClientResponse `json:"clients"`
+}
+
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	IdToken      string `json:"id_token,omitempty"`
+}
+
+type IntrospectionResponse struct {
+	Active      bool   `json:"active"`
+	TokenType   string `json:"token_type,omitempty"`
+	Subject     string `json:"sub,omitempty"`
+	SubjectType string `json:"sub_type,omitempty"`
+	Username    string `json:"username,omitempty"`
+	SessionId   string `json:"sid,omitempty"`
+	TokenId     string `json:"jti,omitempty"`
+	ClientId    string `json:"client_id,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	ExpiresAt   int64  `json:"exp,omitempty"`
+}
+
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
@@ -0,0 +1,65 @@
+package response
+
+import "time"
+
+type PasskeyResponse struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+type ListPasskeysResponse struct {
+	Passkeys []*PasskeyResponse `json:"passkeys"`
+}
+
+// The options responses follow PublicKeyCredentialCreationOptionsJSON and
+// PublicKeyCredentialRequestOptionsJSON, so browsers can pass them to
+// PublicKeyCredential.parseCreationOptionsFromJSON and its request variant.
+
+type PasskeyRelyingPartyResponse struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type PasskeyUserResponse struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type PasskeyCredentialParameterResponse struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type PasskeyCredentialDescriptorResponse struct {
+	Type       string   `json:"type"`
+	Id         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type PasskeyAuthenticatorSelectionResponse struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+type PasskeyCreationOptionsResponse struct {
+	Challenge              string                                `json:"challenge"`
+	Rp                     PasskeyRelyingPartyResponse           `json:"rp"`
+	User                   PasskeyUserResponse                   `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParameterResponse  `json:"pubKeyCredParams"`
+	Timeout                int64                                 `json:"timeout"`
+	ExcludeCredentials     []PasskeyCredentialDescriptorResponse `json:"excludeCredentials"`
+	AuthenticatorSelection PasskeyAuthenticatorSelectionResponse `json:"authenticatorSelection"`
+	Attestation            string                                `json:"attestation"`
+}
+
+type PasskeyRequestOptionsResponse struct {
+	Challenge        string `json:"challenge"`
+	RpId             string `json:"rpId"`
+	Timeout          int64  `json:"timeout"`
+	UserVerification string `json:"userVerification"`
+}
@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type RoleResponse struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+	RequireMfa  bool      `json:"require_mfa"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ListRolesResponse struct {
+	Roles []*RoleResponse `json:"roles"`
+}
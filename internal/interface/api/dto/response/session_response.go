@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type SessionResponse struct {
+	Id         string    `json:"id"`
+	Device     string    `json:"device"`
+	IpAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+type ListSessionsResponse struct {
+	Sessions []*SessionResponse `json:"sessions"`
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type JwksController struct{}
+
+func NewJwksController(r *mux.Router) *JwksController {
+	controller := JwksController{}
+
+	r.Handle("/.well-known/jwks.json", http.HandlerFunc(controller.JwksV1)).Methods(http.MethodGet)
+
+	return &controller
+}
+
+func (jc *JwksController) JwksV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Cache-Control", "public, max-age=300")
+
+	response := mapper.ToJwksResponse(util.CurrentKeyring().PublicKeys())
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var mfaPasswordRateLimit = middleware.RateLimitPolicy{Name: "mfa-password", Limit: 10, Window: time.Hour, Key: middleware.ByUserId}
+
+type MfaController struct {
+	service        interfaces.MfaService
+	trustedProxies []netip.Prefix
+}
+
+func NewMfaController(r *mux.Router, service interfaces.MfaService, authenticator *middleware.Authenticator, rateLimit *middleware.RateLimit, trustedProxies []netip.Prefix) *MfaController {
+	controller := MfaController{
+		service:        service,
+		trustedProxies: trustedProxies,
+	}
+
+	r.Handle("/api/v1/mfa", authenticator.SessionHandler(http.HandlerFunc(controller.StatusV1))).Methods(http.MethodGet)
+	r.Handle("/api/v1/mfa/totp/enroll", authenticator.SessionHandler(http.HandlerFunc(controller.EnrollTotpV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/mfa/totp/confirm", authenticator.SessionHandler(http.HandlerFunc(controller.ConfirmTotpV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/mfa/totp/disable", authenticator.SessionHandler(rateLimit.Handler(http.HandlerFunc(controller.DisableTotpV1), mfaPasswordRateLimit))).Methods(http.MethodPost)
+	r.Handle("/api/v1/mfa/recovery-codes/regenerate", authenticator.SessionHandler(rateLimit.Handler(http.HandlerFunc(controller.RegenerateRecoveryCodesV1), mfaPasswordRateLimit))).Methods(http.MethodPost)
+
+	return &controller
+}
+
+func (mc *MfaController) StatusV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := mc.service.GetMfaStatus(&command.GetMfaStatusCommand{
+		UserId: claims.Id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToMfaStatusResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// EnrollTotpV1 answers with the secret to add to an authenticator app. MFA is
+// only turned on once a first code is confirmed.
+func (mc *MfaController) EnrollTotpV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := mc.service.EnrollTotp(&command.EnrollTotpCommand{
+		UserId: claims.Id,
+		Email:  claims.Email,
+	})
+	if errors.Is(err, entity.ErrMfaAlreadyEnabled) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on enroll totp: %v", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToEnrollTotpResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ConfirmTotpV1 answers with the recovery codes. They cannot be retrieved
+// again later, only regenerated.
+func (mc *MfaController) ConfirmTotpV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewConfirmTotpRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := mc.service.ConfirmTotp(req.ToConfirmTotpCommand(claims.Id))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToRecoveryCodesResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (mc *MfaController) DisableTotpV1(w http.ResponseWriter, r *http.Request) {
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewDisableTotpRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = mc.service.DisableTotp(req.ToDisableTotpCommand(claims.Id, request.NewClientInfo(r, mc.trustedProxies)))
+	var locked *entity.LoginLockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (mc *MfaController) RegenerateRecoveryCodesV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewRegenerateRecoveryCodesRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := mc.service.RegenerateRecoveryCodes(req.ToRegenerateRecoveryCodesCommand(claims.Id, request.NewClientInfo(r, mc.trustedProxies)))
+	var locked *entity.LoginLockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToRecoveryCodesResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
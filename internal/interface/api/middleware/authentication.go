@@ -1,37 +1,149 @@
 package middleware
 
 import (
-	"context"
 	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
 	"github/imfropz/go-ddd/internal/domain/repository"
 	"net/http"
+	"slices"
+	"strings"
 )
 
-func AuthenticationHandler(next http.Handler, userRepository repository.UserRepository) http.Handler {
+const API_KEY_HEADER = "X-API-Key"
+
+// Authenticator builds the handlers letting through the requests of signed in
+// users and of their API keys.
+type Authenticator struct {
+	userRepository          repository.UserRepository
+	tokenService            interfaces.TokenService
+	emailVerificationPolicy string
+}
+
+// NewAuthenticator takes the email verification policy, which sets what users
+// who have not verified their email are allowed to do.
+func NewAuthenticator(userRepository repository.UserRepository, tokenService interfaces.TokenService, emailVerificationPolicy string) *Authenticator {
+	return &Authenticator{
+		userRepository:          userRepository,
+		tokenService:            tokenService,
+		emailVerificationPolicy: emailVerificationPolicy,
+	}
+}
+
+// AuthenticationHandler only lets through tokens issued to the user directly
+// and the user's API keys; tokens issued to OAuth clients are limited to their
+// scopes and are rejected.
+func (authenticator *Authenticator) AuthenticationHandler(next http.Handler) http.Handler {
+	return authenticator.authenticate(next, true, false)
+}
+
+// SessionHandler is AuthenticationHandler for endpoints tied to a signed in
+// session, such as managing sessions and API keys, where API keys are refused.
+func (authenticator *Authenticator) SessionHandler(next http.Handler) http.Handler {
+	return authenticator.authenticate(next, false, false)
+}
+
+// UnverifiedSessionHandler is SessionHandler for the endpoints users must be
+// able to reach whatever the email verification policy, such as signing out.
+func (authenticator *Authenticator) UnverifiedSessionHandler(next http.Handler) http.Handler {
+	return authenticator.authenticate(next, false, true)
+}
+
+func (authenticator *Authenticator) authenticate(next http.Handler, allowApiKey bool, allowUnverified bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token, ok := util.RemoveBearer(r.Header.Get("Authorization"))
-		if !ok {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
+		var claims *util.AccessTokenClaims
+		if apiKey := r.Header.Get(API_KEY_HEADER); apiKey != "" {
+			if !allowApiKey {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			result, err := authenticator.tokenService.ValidateApiKey(&command.ValidateApiKeyCommand{
+				ApiKey: apiKey,
+			})
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			claims = result.Result
+
+			if !apiKeyAllows(claims.Scope, r.Method) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		} else {
+			token, ok := util.RemoveBearer(r.Header.Get("Authorization"))
+			if !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			result, err := authenticator.tokenService.ValidateAccessToken(&command.ValidateAccessTokenCommand{
+				AccessToken: token,
+			})
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			claims = result.Result
+			if claims.ClientId != "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
 		}
 
-		claims, err := util.ValidateAccessToken(token)
-		if err != nil {
+		user, err := authenticator.userRepository.FindByEmail(claims.Email)
+		if err != nil || user.SignInError() != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		user, err := userRepository.FindByEmail(claims.Email)
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
+		if !user.EmailVerified && !allowUnverified && !unverifiedAllows(authenticator.emailVerificationPolicy, r.Method) {
+			w.WriteHeader(http.StatusForbidden)
 			return
 		}
 
-		r = r.WithContext(context.WithValue(r.Context(), util.AccessTokenClaims{}, util.AccessTokenClaims{
-			Id:    user.Id,
-			Name:  user.Name,
-			Email: user.Email,
+		r = r.WithContext(util.ContextWithAccessTokenClaims(r.Context(), util.AccessTokenClaims{
+			Id:          user.Id,
+			Name:        user.Name,
+			Email:       user.Email,
+			SessionId:   claims.SessionId,
+			TokenId:     claims.TokenId,
+			ExpiresAt:   claims.ExpiresAt,
+			Scope:       claims.Scope,
+			Roles:       claims.Roles,
+			Permissions: claims.Permissions,
+			ApiKeyId:    claims.ApiKeyId,
 		}))
 		next.ServeHTTP(w, r)
 	})
 }
+
+// apiKeyAllows lets read only keys through to safe methods. A key created
+// without scopes has full access.
+func apiKeyAllows(scope string, method string) bool {
+	if scope == "" {
+		return true
+	}
+
+	scopes := strings.Fields(scope)
+	if slices.Contains(scopes, entity.SCOPE_API_WRITE) {
+		return true
+	}
+
+	return slices.Contains(scopes, entity.SCOPE_API_READ) && (method == http.MethodGet || method == http.MethodHead)
+}
+
+// unverifiedAllows applies the email verification policy to users who have not
+// verified their email yet.
+func unverifiedAllows(emailVerificationPolicy string, method string) bool {
+	switch emailVerificationPolicy {
+	case entity.EMAIL_VERIFICATION_BLOCK:
+		return false
+	case entity.EMAIL_VERIFICATION_RESTRICT:
+		return method == http.MethodGet || method == http.MethodHead
+	default:
+		return true
+	}
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthenticator_EmailVerificationPolicy(t *testing.T) {
+	keyring, _ := util.NewEphemeralKeyring()
+	util.SetKeyring(keyring)
+
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(t *testing.T, policy string, method string) int {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(user, nil)
+
+		handler := middleware.NewAuthenticator(mockUserRepo, tokenService{}, policy).SessionHandler(ok)
+
+		token, err := util.GenerateAccessToken(util.AccessTokenClaims{
+			Id:        user.Id,
+			Name:      user.Name,
+			Email:     user.Email,
+			SessionId: uuid.New(),
+			TokenId:   uuid.NewString(),
+		})
+		assert.NoError(t, err)
+
+		r := httptest.NewRequest(method, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("success: off", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(t, entity.EMAIL_VERIFICATION_OFF, http.MethodPost))
+	})
+
+	t.Run("success: restrict lets reads through", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(t, entity.EMAIL_VERIFICATION_RESTRICT, http.MethodGet))
+	})
+
+	t.Run("failure: restrict refuses writes", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(t, entity.EMAIL_VERIFICATION_RESTRICT, http.MethodPost))
+	})
+
+	t.Run("failure: block", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(t, entity.EMAIL_VERIFICATION_BLOCK, http.MethodGet))
+	})
+}
@@ -0,0 +1,8 @@
+package middleware
+
+func (limiter *MemoryRateLimiter) Keys() int {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	return len(limiter.windows)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"net/http"
+)
+
+// RequirePermission only lets through users whose token carries every one of
+// the permissions. It reads the claims stored by an authentication handler, so
+// it has to be wrapped inside one. API keys carry no permissions.
+func RequirePermission(next http.Handler, permissions ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := util.AccessTokenClaimsFromContext(r.Context())
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		for _, permission := range permissions {
+			if !claims.HasPermission(permission) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
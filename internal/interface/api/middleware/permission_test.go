@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequirePermission(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.RequirePermission(ok, entity.PERMISSION_USERS_READ, entity.PERMISSION_USERS_WRITE)
+
+	serve := func(claims *util.AccessTokenClaims) int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if claims != nil {
+			r = r.WithContext(util.ContextWithAccessTokenClaims(r.Context(), *claims))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(&util.AccessTokenClaims{
+			Permissions: []string{entity.PERMISSION_USERS_READ, entity.PERMISSION_USERS_WRITE},
+		}))
+	})
+
+	t.Run("failure: missing permission", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(&util.AccessTokenClaims{
+			Permissions: []string{entity.PERMISSION_USERS_READ},
+		}))
+	})
+
+	t.Run("failure: unauthenticated", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serve(nil))
+	})
+}
+
+// tokenService validates access tokens the way TokenService does, without the
+// denylist and session lookups.
+type tokenService struct {
+	interfaces.TokenService
+}
+
+func (tokenService) ValidateAccessToken(validateAccessTokenCommand *command.ValidateAccessTokenCommand) (*command.ValidateAccessTokenCommandResult, error) {
+	claims, err := util.ValidateAccessToken(validateAccessTokenCommand.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	return &command.ValidateAccessTokenCommandResult{Result: &claims}, nil
+}
+
+func TestRequirePermission_AuthenticationHandler(t *testing.T) {
+	keyring, _ := util.NewEphemeralKeyring()
+	util.SetKeyring(keyring)
+
+	user := entity.NewUser("John Doe", "test@example.com", "hashed-password")
+	user.VerifyEmail()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(t *testing.T, permissions []string) int {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockUserRepo.EXPECT().FindByEmail(user.Email).Return(user, nil)
+
+		handler := middleware.NewAuthenticator(mockUserRepo, tokenService{}, entity.EMAIL_VERIFICATION_OFF).AuthenticationHandler(middleware.RequirePermission(ok, entity.PERMISSION_USERS_READ))
+
+		token, err := util.GenerateAccessToken(util.AccessTokenClaims{
+			Id:          user.Id,
+			Name:        user.Name,
+			Email:       user.Email,
+			SessionId:   uuid.New(),
+			TokenId:     uuid.NewString(),
+			Permissions: permissions,
+		})
+		assert.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(t, []string{entity.PERMISSION_USERS_WRITE, entity.PERMISSION_USERS_READ}))
+	})
+
+	t.Run("failure: missing permission", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(t, []string{entity.PERMISSION_USERS_WRITE}))
+	})
+}
@@ -0,0 +1,274 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	Reset     time.Duration
+}
+
+// RateLimiter counts the requests made under key over the last window and
+// allows them while there are fewer than limit.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+}
+
+// RateLimitKeyFunc returns what requests are counted by. An empty key falls
+// back to the client ip.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// RateLimitPolicy allows Limit requests per Window for every key. The name
+// keeps the counters of different routes apart and a nil Key counts requests
+// by client ip.
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	Key    RateLimitKeyFunc
+}
+
+// RateLimit builds the rate limiting handlers. Client ips are read behind the
+// trusted proxies only, and a nil limiter disables rate limiting.
+type RateLimit struct {
+	limiter        RateLimiter
+	trustedProxies []netip.Prefix
+}
+
+func NewRateLimit(limiter RateLimiter, trustedProxies []netip.Prefix) *RateLimit {
+	return &RateLimit{
+		limiter:        limiter,
+		trustedProxies: trustedProxies,
+	}
+}
+
+// Handler answers 429 once the policy is exhausted and sets the RateLimit-*
+// headers on every response. Requests go through when the limiter fails, so an
+// unavailable Valkey does not take the api down.
+func (rateLimit *RateLimit) Handler(next http.Handler, policy RateLimitPolicy) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rateLimit.limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := ""
+		if policy.Key != nil {
+			key = policy.Key(r)
+		}
+		if key == "" {
+			key = "ip:" + request.ClientIp(r, rateLimit.trustedProxies)
+		}
+
+		result, err := rateLimit.limiter.Allow(r.Context(), "rate-limit:"+policy.Name+":"+key, policy.Limit, policy.Window)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to rate limit %s: %v", policy.Name, err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Window.Seconds())))
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", reset)
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", reset)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ByUserId needs the claims of an authentication handler, so the rate limit
+// has to be wrapped inside it.
+func ByUserId(r *http.Request) string {
+	claims, ok := util.AccessTokenClaimsFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return "user:" + claims.Id.String()
+}
+
+// maxEmailBodyBytes caps how much of the body ByEmail reads before any other
+// handler has had a chance to reject the request.
+const maxEmailBodyBytes = 64 << 10
+
+// ByEmail reads the email field of a JSON body and puts the body back for the
+// next handler. A body over maxEmailBodyBytes is not counted by email and the
+// next handler fails reading it.
+func ByEmail(r *http.Request) string {
+	limited := http.MaxBytesReader(nil, r.Body, maxEmailBodyBytes)
+	body, err := io.ReadAll(limited)
+	if err != nil {
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), limited))
+		return ""
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Email == "" {
+		return ""
+	}
+	return "email:" + strings.ToLower(req.Email)
+}
+
+// slidingWindowScript keeps the timestamps of the requests in the window in a
+// sorted set, so the whole check and count is a single atomic step.
+const slidingWindowScript = `
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`
+
+type ValkeyRateLimiter struct {
+	valkeyRepository repository.ValkeyRepository
+}
+
+func NewValkeyRateLimiter(valkeyRepository repository.ValkeyRepository) *ValkeyRateLimiter {
+	return &ValkeyRateLimiter{
+		valkeyRepository: valkeyRepository,
+	}
+}
+
+func (limiter *ValkeyRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	reply, err := limiter.valkeyRepository.Eval(ctx, slidingWindowScript, []string{key},
+		time.Now().UnixMilli(), window.Milliseconds(), limit, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return nil, errors.New("unexpected rate limit reply")
+	}
+
+	numbers := make([]int64, len(values))
+	for i, value := range values {
+		number, ok := value.(int64)
+		if !ok {
+			return nil, errors.New("unexpected rate limit reply")
+		}
+		numbers[i] = number
+	}
+
+	return &RateLimitResult{
+		Allowed:   numbers[0] == 1,
+		Remaining: int(numbers[1]),
+		Reset:     time.Duration(numbers[2]) * time.Millisecond,
+	}, nil
+}
+
+// memoryRateLimiterSweepSize is the number of keys after which the
+// MemoryRateLimiter first drops the ones whose window has passed.
+const memoryRateLimiterSweepSize = 1024
+
+type memoryWindow struct {
+	window   time.Duration
+	requests []time.Time
+}
+
+// MemoryRateLimiter is the same sliding window kept in process. Counters are
+// not shared between instances, so it is only meant for tests.
+// Keys that are not requested again are dropped once the number of keys has
+// doubled since the last sweep, which keeps memory bounded by the live keys.
+type MemoryRateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+	sweepAt int
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		windows: map[string]*memoryWindow{},
+		sweepAt: memoryRateLimiterSweepSize,
+	}
+}
+
+func (limiter *MemoryRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	var requests []time.Time
+	if entry, ok := limiter.windows[key]; ok {
+		requests = entry.requests
+	}
+	for len(requests) > 0 && !requests[0].After(now.Add(-window)) {
+		requests = requests[1:]
+	}
+
+	allowed := len(requests) < limit
+	if allowed {
+		requests = append(requests, now)
+	}
+
+	if len(requests) == 0 {
+		delete(limiter.windows, key)
+		return &RateLimitResult{Allowed: allowed, Remaining: limit, Reset: window}, nil
+	}
+	limiter.windows[key] = &memoryWindow{window: window, requests: requests}
+
+	if len(limiter.windows) >= limiter.sweepAt {
+		limiter.evict(now)
+	}
+
+	return &RateLimitResult{
+		Allowed:   allowed,
+		Remaining: limit - len(requests),
+		Reset:     requests[0].Add(window).Sub(now),
+	}, nil
+}
+
+func (limiter *MemoryRateLimiter) evict(now time.Time) {
+	for key, entry := range limiter.windows {
+		if !entry.requests[len(entry.requests)-1].After(now.Add(-entry.window)) {
+			delete(limiter.windows, key)
+		}
+	}
+	limiter.sweepAt = max(2*len(limiter.windows), memoryRateLimiterSweepSize)
+}
@@ -0,0 +1,210 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/internal/domain/mocks"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimit_Handler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("success: limits per key", func(t *testing.T) {
+		handler := middleware.NewRateLimit(middleware.NewMemoryRateLimiter(), nil).Handler(ok, middleware.RateLimitPolicy{
+			Name:   "test",
+			Limit:  2,
+			Window: time.Minute,
+		})
+
+		codes := make([]int, 0, 3)
+		var last *httptest.ResponseRecorder
+		for i := 0; i < 3; i++ {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.RemoteAddr = "203.0.113.7:1234"
+			last = httptest.NewRecorder()
+			handler.ServeHTTP(last, r)
+			codes = append(codes, last.Code)
+		}
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+		assert.Equal(t, "2", last.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", last.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", last.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "60", last.Header().Get("Retry-After"))
+		assert.Equal(t, "2;w=60", last.Header().Get("RateLimit-Policy"))
+
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.RemoteAddr = "198.51.100.1:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("success: by email keeps the body", func(t *testing.T) {
+		var body string
+		handler := middleware.NewRateLimit(middleware.NewMemoryRateLimiter(), nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+		}), middleware.RateLimitPolicy{
+			Name:   "test",
+			Limit:  1,
+			Window: time.Minute,
+			Key:    middleware.ByEmail,
+		})
+
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"Test@Example.com"}`))
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"test@example.com"}`))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, `{"email":"Test@Example.com"}`, body)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("failure: spoofed forwarded for shares the ip limit", func(t *testing.T) {
+		handler := middleware.NewRateLimit(middleware.NewMemoryRateLimiter(), nil).Handler(ok, middleware.RateLimitPolicy{
+			Name:   "test",
+			Limit:  2,
+			Window: time.Minute,
+		})
+
+		codes := make([]int, 0, 3)
+		for _, forwardedFor := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.RemoteAddr = "203.0.113.7:1234"
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			codes = append(codes, w.Code)
+		}
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	})
+
+	t.Run("success: forwarded for from a trusted proxy", func(t *testing.T) {
+		trustedProxies, err := request.ParseTrustedProxies([]string{"10.0.0.0/8"})
+		assert.NoError(t, err)
+
+		handler := middleware.NewRateLimit(middleware.NewMemoryRateLimiter(), trustedProxies).Handler(ok, middleware.RateLimitPolicy{
+			Name:   "test",
+			Limit:  1,
+			Window: time.Minute,
+		})
+
+		codes := make([]int, 0, 2)
+		for _, forwardedFor := range []string{"198.51.100.1", "198.51.100.2"} {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.RemoteAddr = "10.0.0.2:1234"
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			codes = append(codes, w.Code)
+		}
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK}, codes)
+	})
+
+	t.Run("failure: by email body too large", func(t *testing.T) {
+		var readErr error
+		handler := middleware.NewRateLimit(middleware.NewMemoryRateLimiter(), nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, readErr = io.ReadAll(r.Body)
+		}), middleware.RateLimitPolicy{
+			Name:   "test",
+			Limit:  1,
+			Window: time.Minute,
+			Key:    middleware.ByEmail,
+		})
+
+		body := `{"email":"test@example.com","padding":"` + strings.Repeat("a", 1<<20) + `"}`
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.RemoteAddr = "203.0.113.7:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		var maxBytesErr *http.MaxBytesError
+		assert.ErrorAs(t, readErr, &maxBytesErr)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("success: disabled", func(t *testing.T) {
+		handler := middleware.NewRateLimit(nil, nil).Handler(ok, middleware.RateLimitPolicy{Name: "test", Limit: 0, Window: time.Minute})
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestMemoryRateLimiter_Allow(t *testing.T) {
+	t.Run("success: evicts expired keys", func(t *testing.T) {
+		limiter := middleware.NewMemoryRateLimiter()
+
+		for i := 0; i < 1023; i++ {
+			limiter.Allow(context.Background(), fmt.Sprintf("expired:%d", i), 5, time.Millisecond)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		result, err := limiter.Allow(context.Background(), "live", 5, time.Minute)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 1, limiter.Keys())
+	})
+}
+
+func TestValkeyRateLimiter_Allow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), []string{"key"}, gomock.Any(), int64(60000), 5, gomock.Any()).
+			Return([]interface{}{int64(1), int64(4), int64(60000)}, nil)
+
+		limiter := middleware.NewValkeyRateLimiter(mockValkeyRepo)
+
+		result, err := limiter.Allow(context.Background(), "key", 5, time.Minute)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 4, result.Remaining)
+		assert.Equal(t, time.Minute, result.Reset)
+	})
+
+	t.Run("failure: valkey error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockValkeyRepo := mocks.NewMockValkeyRepository(ctrl)
+		mockValkeyRepo.EXPECT().
+			Eval(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("connection refused"))
+
+		limiter := middleware.NewValkeyRateLimiter(mockValkeyRepo)
+
+		_, err := limiter.Allow(context.Background(), "key", 5, time.Minute)
+
+		assert.Error(t, err)
+	})
+}
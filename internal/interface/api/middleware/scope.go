@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"net/http"
+	"slices"
+)
+
+// ScopeHandler accepts user access tokens issued to OAuth clients as long as
+// they were granted the scope, as well as tokens issued to the user directly.
+// Machine tokens have no user and are rejected. The claims are stored as they
+// are, including the client id and scope.
+func ScopeHandler(next http.Handler, tokenService interfaces.TokenService, scope string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := util.RemoveBearer(r.Header.Get("Authorization"))
+		if !ok {
+			w.Header().Add("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		result, err := tokenService.ValidateAccessToken(&command.ValidateAccessTokenCommand{
+			AccessToken: token,
+		})
+		if err != nil {
+			w.Header().Add("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		claims := result.Result
+
+		if claims.IsMachine() || claims.ClientId != "" && !slices.Contains(entity.ParseScope(claims.Scope), scope) {
+			w.Header().Add("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		r = r.WithContext(util.ContextWithAccessTokenClaims(r.Context(), *claims))
+		next.ServeHTTP(w, r)
+	})
+}
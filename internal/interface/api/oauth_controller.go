@@ -0,0 +1,406 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/common"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/domain/repository"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// oauthSessionCookie holds the access token of the session the user signed in
+// with on the authorization endpoint.
+const oauthSessionCookie = "oauth_session"
+
+type OAuthController struct {
+	service             interfaces.OAuthService
+	authenticateService interfaces.AuthenticateService
+	tokenService        interfaces.TokenService
+	mfaService          interfaces.MfaService
+	userRepository      repository.UserRepository
+	trustedProxies      []netip.Prefix
+}
+
+func NewOAuthController(r *mux.Router, service interfaces.OAuthService, authenticateService interfaces.AuthenticateService, tokenService interfaces.TokenService, mfaService interfaces.MfaService, userRepository repository.UserRepository, authenticator *middleware.Authenticator, rateLimit *middleware.RateLimit, trustedProxies []netip.Prefix) *OAuthController {
+	controller := OAuthController{
+		service:             service,
+		authenticateService: authenticateService,
+		tokenService:        tokenService,
+		mfaService:          mfaService,
+		userRepository:      userRepository,
+		trustedProxies:      trustedProxies,
+	}
+
+	r.Handle("/oauth/clients", authenticator.SessionHandler(http.HandlerFunc(controller.RegisterClientV1))).Methods(http.MethodPost)
+	r.Handle("/oauth/clients", authenticator.SessionHandler(http.HandlerFunc(controller.ListClientsV1))).Methods(http.MethodGet)
+	r.Handle("/oauth/authorize", http.HandlerFunc(controller.AuthorizeV1)).Methods(http.MethodGet)
+	r.Handle("/oauth/authorize/login", rateLimit.Handler(http.HandlerFunc(controller.AuthorizeLoginV1), loginRateLimit)).Methods(http.MethodPost)
+	r.Handle("/oauth/authorize/mfa", rateLimit.Handler(http.HandlerFunc(controller.AuthorizeMfaV1), loginRateLimit)).Methods(http.MethodPost)
+	r.Handle("/oauth/authorize/consent", http.HandlerFunc(controller.ConsentV1)).Methods(http.MethodPost)
+	r.Handle("/oauth/token", http.HandlerFunc(controller.TokenV1)).Methods(http.MethodPost)
+	r.Handle("/oauth/introspect", http.HandlerFunc(controller.IntrospectV1)).Methods(http.MethodPost)
+	r.Handle("/oauth/revoke", http.HandlerFunc(controller.RevokeV1)).Methods(http.MethodPost)
+
+	return &controller
+}
+
+func (oc *OAuthController) RegisterClientV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewRegisterOAuthClientRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := oc.service.RegisterClient(req.ToRegisterOAuthClientCommand(claims.Id))
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on register oauth client: %v", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToOAuthClientResponse(result.Result)
+	response.ClientSecret = result.ClientSecret
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (oc *OAuthController) ListClientsV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := oc.service.ListClients(&command.ListOAuthClientsCommand{
+		OwnerId: claims.Id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToOAuthClientListResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// AuthorizeV1 is opened in the user's browser. A user without a session on the
+// authorization endpoint is asked to sign in first, then to consent unless the
+// client was already granted the requested scopes. It ends with a redirect to
+// the client.
+func (oc *OAuthController) AuthorizeV1(w http.ResponseWriter, r *http.Request) {
+	claims, ok := oc.sessionClaims(r)
+	if !ok {
+		renderOAuthPage(w, http.StatusOK, "login", oauthPage{
+			Title:     "Sign in",
+			Authorize: r.URL.RawQuery,
+		})
+		return
+	}
+
+	req := request.NewAuthorizeRequest(r)
+
+	result, err := oc.service.Authorize(req.ToAuthorizeCommand(claims.Id, claims.SessionId))
+	oc.completeAuthorize(w, r, result, err)
+}
+
+func (oc *OAuthController) AuthorizeLoginV1(w http.ResponseWriter, r *http.Request) {
+	req, err := request.NewAuthorizeLoginRequest(r)
+	if err != nil {
+		renderOAuthPage(w, http.StatusBadRequest, "error", oauthPage{Title: "Sign in", Error: "Malformed sign in request."})
+		return
+	}
+
+	page := oauthPage{
+		Title:     "Sign in",
+		Authorize: req.Authorize,
+		Email:     req.Email,
+	}
+
+	user, err := oc.authenticateService.Login(req.ToLoginCommand(request.NewClientInfo(r, oc.trustedProxies)))
+	var locked *entity.LoginLockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		page.Error = "Too many failed attempts, try again later."
+		renderOAuthPage(w, http.StatusTooManyRequests, "login", page)
+		return
+	}
+	if errors.Is(err, entity.ErrEmailNotVerified) || errors.Is(err, entity.ErrUserDisabled) || errors.Is(err, entity.ErrUserDeleted) {
+		page.Error = "This account cannot sign in."
+		renderOAuthPage(w, http.StatusForbidden, "login", page)
+		return
+	}
+	if err != nil {
+		page.Error = "Invalid email or password."
+		renderOAuthPage(w, http.StatusUnauthorized, "login", page)
+		return
+	}
+
+	challenge, err := oc.mfaService.CreateMfaChallenge(&command.CreateMfaChallengeCommand{
+		User:   user.Result,
+		Method: entity.LOGIN_METHOD_PASSWORD,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on create mfa challenge: %v", err))
+		renderOAuthPage(w, http.StatusInternalServerError, "error", oauthPage{Title: "Sign in", Error: "Unable to sign in, try again later."})
+		return
+	}
+	if challenge.Result.Required {
+		renderOAuthPage(w, http.StatusOK, "mfa", oauthPage{
+			Title:     "Two-factor authentication",
+			Authorize: req.Authorize,
+			MfaToken:  challenge.Result.MfaToken,
+		})
+		return
+	}
+
+	oc.startSession(w, r, user.Result, entity.LOGIN_METHOD_PASSWORD, req.Authorize)
+}
+
+func (oc *OAuthController) AuthorizeMfaV1(w http.ResponseWriter, r *http.Request) {
+	req, err := request.NewAuthorizeMfaRequest(r)
+	if err != nil {
+		renderOAuthPage(w, http.StatusBadRequest, "error", oauthPage{Title: "Two-factor authentication", Error: "Malformed request."})
+		return
+	}
+
+	user, err := oc.mfaService.VerifyMfaChallenge(req.ToVerifyMfaChallengeCommand(request.NewClientInfo(r, oc.trustedProxies)))
+	var locked *entity.LoginLockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		renderOAuthPage(w, http.StatusTooManyRequests, "login", oauthPage{
+			Title:     "Sign in",
+			Authorize: req.Authorize,
+			Error:     "Too many failed attempts, try again later.",
+		})
+		return
+	}
+	if err != nil {
+		renderOAuthPage(w, http.StatusUnauthorized, "login", oauthPage{
+			Title:     "Sign in",
+			Authorize: req.Authorize,
+			Error:     "The code was not accepted, please sign in again.",
+		})
+		return
+	}
+
+	oc.startSession(w, r, user.Result, user.Method, req.Authorize)
+}
+
+// ConsentV1 answers the consent page. The consent id is only ever shown to the
+// signed in user it was asked of, and the session cookie is not sent along
+// with cross-site posts.
+func (oc *OAuthController) ConsentV1(w http.ResponseWriter, r *http.Request) {
+	claims, ok := oc.sessionClaims(r)
+	if !ok {
+		renderOAuthPage(w, http.StatusUnauthorized, "error", oauthPage{Title: "Authorization", Error: "Your session has expired, please start again."})
+		return
+	}
+
+	req, err := request.NewOAuthConsentRequest(r)
+	if err != nil {
+		renderOAuthPage(w, http.StatusBadRequest, "error", oauthPage{Title: "Authorization", Error: "Malformed consent request."})
+		return
+	}
+
+	result, err := oc.service.Consent(req.ToOAuthConsentCommand(claims.Id, claims.SessionId))
+	oc.completeAuthorize(w, r, result, err)
+}
+
+// sessionClaims validates the session cookie like SessionHandler validates a
+// bearer token.
+func (oc *OAuthController) sessionClaims(r *http.Request) (*util.AccessTokenClaims, bool) {
+	cookie, err := r.Cookie(oauthSessionCookie)
+	if err != nil {
+		return nil, false
+	}
+
+	result, err := oc.tokenService.ValidateAccessToken(&command.ValidateAccessTokenCommand{
+		AccessToken: cookie.Value,
+	})
+	if err != nil || result.Result.ClientId != "" {
+		return nil, false
+	}
+
+	user, err := oc.userRepository.FindById(result.Result.Id)
+	if err != nil || user.SignInError() != nil {
+		return nil, false
+	}
+
+	return result.Result, true
+}
+
+// startSession signs the user in on the authorization endpoint and goes back
+// to the authorization request.
+func (oc *OAuthController) startSession(w http.ResponseWriter, r *http.Request, user *common.UserResult, method string, authorize string) {
+	clientInfo := request.NewClientInfo(r, oc.trustedProxies)
+	token, err := oc.tokenService.IssueToken(&command.IssueTokenCommand{
+		User:      user,
+		Device:    clientInfo.Device,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+		Method:    method,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on issue oauth session: %v", err))
+		renderOAuthPage(w, http.StatusInternalServerError, "error", oauthPage{Title: "Sign in", Error: "Unable to sign in, try again later."})
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthSessionCookie,
+		Value:    token.Result.AccessToken,
+		Path:     "/oauth/authorize",
+		MaxAge:   token.Result.ExpiresIn,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, "/oauth/authorize?"+request.AuthorizeQuery(authorize), http.StatusSeeOther)
+}
+
+// completeAuthorize shows the consent page when the result asks for it and
+// otherwise redirects to the client with the code or the error. Errors that
+// come without a result are shown to the user instead, as the redirect uri
+// could not be trusted.
+func (oc *OAuthController) completeAuthorize(w http.ResponseWriter, r *http.Request, result *command.AuthorizeCommandResult, err error) {
+	if err != nil && result == nil {
+		renderOAuthPage(w, http.StatusBadRequest, "error", oauthPage{
+			Title: "Authorization failed",
+			Error: mapper.ToOAuthErrorResponse(err).ErrorDescription,
+		})
+		return
+	}
+
+	if err == nil && result.ConsentRequired {
+		renderOAuthPage(w, http.StatusOK, "consent", oauthPage{
+			Title:      "Authorize " + result.ClientName,
+			ConsentId:  result.ConsentId,
+			ClientName: result.ClientName,
+			Scopes:     result.Scopes,
+		})
+		return
+	}
+
+	redirectUri, parseErr := url.Parse(result.RedirectUri)
+	if parseErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	query := redirectUri.Query()
+	if err != nil {
+		errorResponse := mapper.ToOAuthErrorResponse(err)
+		query.Set("error", errorResponse.Error)
+		query.Set("error_description", errorResponse.ErrorDescription)
+	} else {
+		query.Set("code", result.Code)
+	}
+	if result.State != "" {
+		query.Set("state", result.State)
+	}
+	redirectUri.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirectUri.String(), http.StatusFound)
+}
+
+func (oc *OAuthController) TokenV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Cache-Control", "no-store")
+
+	req, err := request.NewOAuthTokenRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(mapper.ToOAuthErrorResponse(entity.NewOAuthError(entity.OAUTH_INVALID_REQUEST, "malformed token request")))
+		return
+	}
+
+	result, err := oc.service.Token(req.ToOAuthTokenCommand(request.NewClientInfo(r, oc.trustedProxies)))
+	if err != nil {
+		writeOAuthError(w, "oauth token", err)
+		return
+	}
+
+	response := mapper.ToOAuthTokenResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (oc *OAuthController) IntrospectV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Cache-Control", "no-store")
+
+	req, err := request.NewTokenHintRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(mapper.ToOAuthErrorResponse(entity.NewOAuthError(entity.OAUTH_INVALID_REQUEST, "malformed introspection request")))
+		return
+	}
+
+	result, err := oc.service.Introspect(req.ToIntrospectCommand())
+	if err != nil {
+		writeOAuthError(w, "oauth introspect", err)
+		return
+	}
+
+	response := mapper.ToIntrospectionResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (oc *OAuthController) RevokeV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewTokenHintRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(mapper.ToOAuthErrorResponse(entity.NewOAuthError(entity.OAUTH_INVALID_REQUEST, "malformed revocation request")))
+		return
+	}
+
+	if err := oc.service.Revoke(req.ToRevokeCommand()); err != nil {
+		writeOAuthError(w, "oauth revoke", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeOAuthError answers with the status RFC 6749 section 5.2 asks for the
+// error, asking the client to authenticate again when that failed.
+func writeOAuthError(w http.ResponseWriter, action string, err error) {
+	errorResponse := mapper.ToOAuthErrorResponse(err)
+	switch errorResponse.Error {
+	case entity.OAUTH_INVALID_CLIENT:
+		w.Header().Add("WWW-Authenticate", `Basic realm="oauth"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	case entity.OAUTH_SERVER_ERROR:
+		slog.Error(fmt.Sprintf("error on %s: %v", action, err))
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(errorResponse)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+)
+
+// The authorization endpoint is opened in the user's browser, so unlike the
+// rest of the api it answers with plain HTML pages.
+var oauthPages = template.Must(template.New("layout").Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "login"}}{{template "header" .}}
+<form method="post" action="/oauth/authorize/login">
+<input type="hidden" name="authorize" value="{{.Authorize}}">
+<label>Email <input type="email" name="email" value="{{.Email}}" autocomplete="username" required autofocus></label>
+<label>Password <input type="password" name="password" autocomplete="current-password" required></label>
+<button type="submit">Sign in</button>
+</form>
+{{template "footer" .}}{{end}}
+
+{{define "mfa"}}{{template "header" .}}
+<form method="post" action="/oauth/authorize/mfa">
+<input type="hidden" name="authorize" value="{{.Authorize}}">
+<input type="hidden" name="mfa_token" value="{{.MfaToken}}">
+<label>Authentication code <input type="text" name="code" inputmode="numeric" autocomplete="one-time-code" autofocus></label>
+<label>Or a recovery code <input type="text" name="recovery_code" autocomplete="off"></label>
+<button type="submit">Verify</button>
+</form>
+{{template "footer" .}}{{end}}
+
+{{define "consent"}}{{template "header" .}}
+<p>{{.ClientName}} would like to access your account:</p>
+<ul>{{range .Scopes}}<li>{{.}}</li>{{end}}</ul>
+<form method="post" action="/oauth/authorize/consent">
+<input type="hidden" name="consent_id" value="{{.ConsentId}}">
+<button type="submit" name="decision" value="approve">Allow</button>
+<button type="submit" name="decision" value="deny">Deny</button>
+</form>
+{{template "footer" .}}{{end}}
+
+{{define "error"}}{{template "header" .}}{{template "footer" .}}{{end}}
+`))
+
+type oauthPage struct {
+	Title      string
+	Error      string
+	Authorize  string
+	Email      string
+	MfaToken   string
+	ConsentId  string
+	ClientName string
+	Scopes     []string
+}
+
+// renderOAuthPage keeps the pages out of frames, so the consent cannot be
+// clickjacked, and out of caches, as they carry single-use tokens.
+func renderOAuthPage(w http.ResponseWriter, status int, name string, page oauthPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+	w.WriteHeader(status)
+
+	if err := oauthPages.ExecuteTemplate(w, name, page); err != nil {
+		slog.Error(fmt.Sprintf("error on render oauth page %s: %v", name, err))
+	}
+}
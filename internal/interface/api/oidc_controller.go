@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type OidcController struct {
+	service interfaces.OAuthService
+	issuer  string
+}
+
+func NewOidcController(r *mux.Router, service interfaces.OAuthService, tokenService interfaces.TokenService, issuer string) *OidcController {
+	controller := OidcController{
+		service: service,
+		issuer:  issuer,
+	}
+
+	r.Handle("/.well-known/openid-configuration", http.HandlerFunc(controller.DiscoveryV1)).Methods(http.MethodGet)
+	r.Handle("/userinfo", middleware.ScopeHandler(http.HandlerFunc(controller.UserInfoV1), tokenService, entity.SCOPE_OPENID)).Methods(http.MethodGet, http.MethodPost)
+
+	return &controller
+}
+
+func (oc *OidcController) DiscoveryV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Cache-Control", "public, max-age=300")
+
+	response := mapper.ToOpenIdConfigurationResponse(oc.issuer, util.CurrentKeyring().ValidMethods())
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (oc *OidcController) UserInfoV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Cache-Control", "no-store")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := oc.service.UserInfo(&command.UserInfoCommand{
+		UserId:   claims.Id,
+		ClientId: claims.ClientId,
+		Scope:    claims.Scope,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	response := mapper.ToUserInfoResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"log/slog"
+	"net/http"
+	"net/netip"
+
+	"github.com/gorilla/mux"
+)
+
+type PasskeyController struct {
+	service        interfaces.PasskeyService
+	tokenService   interfaces.TokenService
+	trustedProxies []netip.Prefix
+}
+
+func NewPasskeyController(r *mux.Router, service interfaces.PasskeyService, tokenService interfaces.TokenService, authenticator *middleware.Authenticator, rateLimit *middleware.RateLimit, trustedProxies []netip.Prefix) *PasskeyController {
+	controller := PasskeyController{
+		service:        service,
+		tokenService:   tokenService,
+		trustedProxies: trustedProxies,
+	}
+
+	r.Handle("/api/v1/passkeys", authenticator.SessionHandler(http.HandlerFunc(controller.ListPasskeysV1))).Methods(http.MethodGet)
+	r.Handle("/api/v1/passkeys/register/begin", authenticator.SessionHandler(http.HandlerFunc(controller.BeginRegistrationV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/passkeys/register/finish", authenticator.SessionHandler(http.HandlerFunc(controller.FinishRegistrationV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/delete-passkey", authenticator.SessionHandler(http.HandlerFunc(controller.DeletePasskeyV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/login/passkey/begin", rateLimit.Handler(http.HandlerFunc(controller.BeginLoginV1), loginRateLimit)).Methods(http.MethodPost)
+	r.Handle("/api/v1/login/passkey/finish", rateLimit.Handler(http.HandlerFunc(controller.FinishLoginV1), loginRateLimit)).Methods(http.MethodPost)
+
+	return &controller
+}
+
+func (pc *PasskeyController) ListPasskeysV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := pc.service.ListPasskeys(&command.ListPasskeysCommand{
+		UserId: claims.Id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToPasskeyListResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (pc *PasskeyController) BeginRegistrationV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := pc.service.BeginPasskeyRegistration(&command.BeginPasskeyRegistrationCommand{
+		UserId: claims.Id,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on begin passkey registration: %v", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToPasskeyCreationOptionsResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (pc *PasskeyController) FinishRegistrationV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewPasskeyRegistrationRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	finishCommand, err := req.ToFinishPasskeyRegistrationCommand(claims.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := pc.service.FinishPasskeyRegistration(finishCommand)
+	if errors.Is(err, entity.ErrPasskeyAlreadyRegistered) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on finish passkey registration: %v", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToPasskeyResponse(result.Result)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (pc *PasskeyController) DeletePasskeyV1(w http.ResponseWriter, r *http.Request) {
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewDeletePasskeyRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := pc.service.DeletePasskey(req.ToDeletePasskeyCommand(claims.Id)); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (pc *PasskeyController) BeginLoginV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	result, err := pc.service.BeginPasskeyLogin()
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on begin passkey login: %v", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := mapper.ToPasskeyRequestOptionsResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (pc *PasskeyController) FinishLoginV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	req, err := request.NewPasskeyLoginRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	clientInfo := request.NewClientInfo(r, pc.trustedProxies)
+	finishCommand, err := req.ToFinishPasskeyLoginCommand(clientInfo)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := pc.service.FinishPasskeyLogin(finishCommand)
+	if errors.Is(err, entity.ErrEmailNotVerified) || errors.Is(err, entity.ErrUserDisabled) || errors.Is(err, entity.ErrUserDeleted) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	token, err := pc.tokenService.IssueToken(&command.IssueTokenCommand{
+		User:      user.Result,
+		Device:    clientInfo.Device,
+		IpAddress: clientInfo.IpAddress,
+		UserAgent: clientInfo.UserAgent,
+		Method:    entity.LOGIN_METHOD_PASSKEY,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToTokenResponse(token.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
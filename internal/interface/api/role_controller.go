@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type RoleController struct {
+	service interfaces.RoleService
+}
+
+// NewRoleController registers the routes admins use to assign roles. They need
+// a signed in session holding roles:manage.
+func NewRoleController(r *mux.Router, service interfaces.RoleService, authenticator *middleware.Authenticator) *RoleController {
+	controller := RoleController{
+		service: service,
+	}
+
+	admin := func(h http.HandlerFunc) http.Handler {
+		return authenticator.SessionHandler(middleware.RequirePermission(h, entity.PERMISSION_ROLES_MANAGE))
+	}
+
+	r.Handle("/api/v1/admin/roles", admin(controller.ListRolesV1)).Methods(http.MethodGet)
+	r.Handle("/api/v1/admin/users/{id}/roles", admin(controller.ListUserRolesV1)).Methods(http.MethodGet)
+	r.Handle("/api/v1/admin/users/{id}/roles", admin(controller.AssignRoleV1)).Methods(http.MethodPost)
+	r.Handle("/api/v1/admin/users/{id}/roles/{role}", admin(controller.UnassignRoleV1)).Methods(http.MethodDelete)
+
+	return &controller
+}
+
+func (rc *RoleController) ListRolesV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	result, err := rc.service.ListRoles()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := mapper.ToRoleListResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rc *RoleController) ListUserRolesV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := rc.service.ListUserRoles(&command.ListUserRolesCommand{
+		UserId: id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	response := mapper.ToRoleListResponse(result.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (rc *RoleController) AssignRoleV1(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	req, err := request.NewAssignRoleRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := rc.service.AssignRole(req.ToAssignRoleCommand(id)); err != nil {
+		slog.Error(fmt.Sprintf("error on assign role: %v", err))
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rc *RoleController) UnassignRoleV1(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = rc.service.UnassignRole(&command.UnassignRoleCommand{
+		UserId: id,
+		Role:   mux.Vars(r)["role"],
+	})
+	if errors.Is(err, entity.ErrLastAdmin) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
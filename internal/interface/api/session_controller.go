@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type SessionController struct {
+	service interfaces.SessionService
+}
+
+func NewSessionController(r *mux.Router, service interfaces.SessionService, authenticator *middleware.Authenticator) *SessionController {
+	controller := SessionController{
+		service: service,
+	}
+
+	r.Handle("/api/v1/sessions", authenticator.SessionHandler(http.HandlerFunc(controller.ListSessionsV1))).Methods(http.MethodGet)
+	r.Handle("/api/v1/revoke-session", authenticator.SessionHandler(http.HandlerFunc(controller.RevokeSessionV1))).Methods(http.MethodPost)
+	r.Handle("/api/v1/revoke-other-sessions", authenticator.SessionHandler(http.HandlerFunc(controller.RevokeOtherSessionsV1))).Methods(http.MethodPost)
+
+	return &controller
+}
+
+func (sc *SessionController) ListSessionsV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	result, err := sc.service.ListSessions(&command.ListSessionsCommand{
+		UserId: claims.Id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToSessionListResponse(result.Result, claims.SessionId)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (sc *SessionController) RevokeSessionV1(w http.ResponseWriter, r *http.Request) {
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	req, err := request.NewRevokeSessionRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	revokeSessionCommand := req.ToRevokeSessionCommand(claims.Id)
+	if err := sc.service.RevokeSession(revokeSessionCommand); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (sc *SessionController) RevokeOtherSessionsV1(w http.ResponseWriter, r *http.Request) {
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	if err := sc.service.RevokeOtherSessions(&command.RevokeOtherSessionsCommand{
+		UserId:           claims.Id,
+		CurrentSessionId: claims.SessionId,
+	}); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/imfropz/go-ddd/common/util"
+	"github/imfropz/go-ddd/internal/application/command"
+	"github/imfropz/go-ddd/internal/application/interfaces"
+	"github/imfropz/go-ddd/internal/domain/entity"
+	"github/imfropz/go-ddd/internal/interface/api/dto/filter"
+	"github/imfropz/go-ddd/internal/interface/api/dto/mapper"
+	"github/imfropz/go-ddd/internal/interface/api/dto/request"
+	"github/imfropz/go-ddd/internal/interface/api/middleware"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type UserController struct {
+	service interfaces.UserService
+}
+
+// NewUserController registers the routes called by other backend services with
+// a machine token, and the routes admins use to manage users, which need a
+// signed in session holding users:read or users:write.
+func NewUserController(r *mux.Router, service interfaces.UserService, tokenService interfaces.TokenService, authenticator *middleware.Authenticator) *UserController {
+	controller := UserController{
+		service: service,
+	}
+
+	r.Handle("/api/v1/users/{id}", middleware.MachineHandler(http.HandlerFunc(controller.GetUserV1), tokenService, entity.SCOPE_USERS_READ)).Methods(http.MethodGet)
+
+	admin := func(h http.HandlerFunc, permission string) http.Handler {
+		return authenticator.SessionHandler(middleware.RequirePermission(h, permission))
+	}
+
+	r.Handle("/api/v1/admin/users", admin(controller.ListUsersV1, entity.PERMISSION_USERS_READ)).Methods(http.MethodGet)
+	r.Handle("/api/v1/admin/users/{id}", admin(controller.GetUserV1, entity.PERMISSION_USERS_READ)).Methods(http.MethodGet)
+	r.Handle("/api/v1/admin/users/{id}", admin(controller.UpdateUserV1, entity.PERMISSION_USERS_WRITE)).Methods(http.MethodPut)
+	r.Handle("/api/v1/admin/users/{id}", admin(controller.DeleteUserV1, entity.PERMISSION_USERS_WRITE)).Methods(http.MethodDelete)
+	r.Handle("/api/v1/admin/users/{id}/reset-password", admin(controller.ForcePasswordResetV1, entity.PERMISSION_USERS_WRITE)).Methods(http.MethodPost)
+	r.Handle("/api/v1/admin/users/{id}/disable", admin(controller.DisableUserV1, entity.PERMISSION_USERS_WRITE)).Methods(http.MethodPost)
+	r.Handle("/api/v1/admin/users/{id}/enable", admin(controller.EnableUserV1, entity.PERMISSION_USERS_WRITE)).Methods(http.MethodPost)
+	r.Handle("/api/v1/admin/users/{id}/restore", admin(controller.RestoreUserV1, entity.PERMISSION_USERS_WRITE)).Methods(http.MethodPost)
+
+	return &controller
+}
+
+func (uc *UserController) GetUserV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := uc.service.GetUser(&command.GetUserCommand{
+		Id: id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (uc *UserController) ListUsersV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	userCriteria, err := filter.RequestToUserCriteria(*r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	users, err := uc.service.ListUsers(&command.ListUsersCommand{
+		Criteria: userCriteria,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error on list users: %v", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := mapper.ToUserListResponse(users.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (uc *UserController) UpdateUserV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	req, err := request.NewUpdateUserRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := uc.service.UpdateUser(req.ToUpdateUserCommand(id))
+	if errors.Is(err, entity.ErrEmailTaken) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (uc *UserController) ForcePasswordResetV1(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := uc.service.ForcePasswordReset(&command.ForcePasswordResetCommand{
+		Id: id,
+	}); err != nil {
+		slog.Error(fmt.Sprintf("error on force password reset: %v", err))
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (uc *UserController) DisableUserV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := uc.service.DisableUser(&command.DisableUserCommand{
+		ActorId: claims.Id,
+		Id:      id,
+	})
+	if errors.Is(err, entity.ErrSelfManagement) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (uc *UserController) EnableUserV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := uc.service.EnableUser(&command.EnableUserCommand{
+		Id: id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (uc *UserController) DeleteUserV1(w http.ResponseWriter, r *http.Request) {
+	claims := util.MustAccessTokenClaims(r.Context())
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = uc.service.DeleteUser(&command.DeleteUserCommand{
+		ActorId: claims.Id,
+		Id:      id,
+	})
+	if errors.Is(err, entity.ErrSelfManagement) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (uc *UserController) RestoreUserV1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := uc.service.RestoreUser(&command.RestoreUserCommand{
+		Id: id,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	response := mapper.ToUserResponse(user.Result)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}